@@ -44,11 +44,60 @@ type Flags struct {
 	// Supported values: camel, none
 	NamingScheme string
 
-	// IgnoreStringFormat states whether the properties' format (date, date-time) should impact the type in types
+	// IgnoreStringFormat states whether the properties' format (date, date-time,
+	// uuid, etc) should impact the type in types
 	IgnoreStringFormat bool
 
+	// IgnoreStringFormats are the properties' formats that should not impact
+	// the type in types, while the other formats still do.
+	IgnoreStringFormats []string
+
 	// ForcePointers can be used to force all struct fields to be generated as pointers
 	ForcePointers bool
+
+	// UseNullable generates the optional and nullable struct fields with the
+	// 'extensions.Nullable' wrapper instead of pointers
+	UseNullable bool
+
+	// CloudEvents defines the CloudEvents content mode of generated messages
+	// Supported values: binary, structured
+	CloudEvents string
+
+	// AllowUnknownEnums disables the rejection of unknown enum values when unmarshaling
+	AllowUnknownEnums bool
+
+	// StrictAdditionalProperties rejects unknown properties when additional properties are forbidden
+	StrictAdditionalProperties bool
+
+	// PlainMaps generates objects that only have additional properties as plain maps
+	PlainMaps bool
+
+	// EscapeChannelParameters escapes the brokers separators and wildcards in channel parameters
+	EscapeChannelParameters bool
+
+	// AvroConfluent (un)marshals the Avro payloads with the Confluent wire format
+	AvroConfluent bool
+
+	// ProtobufGoTypes references the types generated by protoc-gen-go for protobuf payloads
+	ProtobufGoTypes bool
+
+	// TraitMixins generates the headers of the message traits as types embedded in the messages headers
+	TraitMixins bool
+
+	// Examples generates the messages examples and their test next to the generated code
+	Examples bool
+
+	// RandomMessages generates functions creating random valid messages
+	RandomMessages bool
+
+	// FakeControllers generates the interfaces of the controllers and their fake implementations
+	FakeControllers bool
+
+	// SplitFiles writes the generated code into one file per part instead of a single file
+	SplitFiles bool
+
+	// TypesPackage is the import path of the package containing the types, when generated in their own package
+	TypesPackage string
 }
 
 // SetToCommand adds the flags to a cobra command.
@@ -65,20 +114,73 @@ func (f *Flags) SetToCommand(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&f.NamingScheme, "naming-scheme", "n", "none",
 		"Naming scheme for generated golang elements.\nSupported values: camel, none.")
 	cmd.Flags().BoolVar(&f.IgnoreStringFormat, "ignore-string-format", false,
-		"Ignores the format (date, date-time) on string properties, generating golang string, instead of dates")
+		"Ignores the format (date, date-time, uuid, etc) on string properties, generating golang string, instead of dates, UUIDs, etc")
+	cmd.Flags().StringSliceVar(&f.IgnoreStringFormats, "ignore-string-formats", nil,
+		"Ignores only the given formats on string properties, generating golang string for them.\n"+
+			"Supported values: date, date-time, uuid, byte, binary, duration, uri, ipv4, ipv6.")
 	cmd.Flags().BoolVar(&f.ForcePointers, "force-pointers", false, "Forces all struct fields to be generated as pointers")
+	cmd.Flags().BoolVar(&f.UseNullable, "use-nullable", false,
+		"Generates optional and nullable fields with the extensions.Nullable wrapper instead of pointers (AsyncAPI v3 only)")
+	cmd.Flags().StringVar(&f.CloudEvents, "cloudevents", "",
+		"CloudEvents content mode of generated messages (AsyncAPI v3 only).\nSupported values: binary, structured.")
+	cmd.Flags().BoolVar(&f.AllowUnknownEnums, "allow-unknown-enums", false,
+		"Accepts unknown enum values when unmarshaling, for forward compatibility (AsyncAPI v3 only)")
+	cmd.Flags().BoolVar(&f.StrictAdditionalProperties, "strict-additional-properties", false,
+		"Rejects unknown properties when unmarshaling objects with 'additionalProperties: false' (AsyncAPI v3 only)")
+	cmd.Flags().BoolVar(&f.PlainMaps, "plain-maps", false,
+		"Generates objects that only have additional properties as plain golang maps (AsyncAPI v3 only)")
+	cmd.Flags().BoolVar(&f.EscapeChannelParameters, "escape-channel-parameters", false,
+		"Escapes the separators and wildcards of the brokers in the channel parameters values (AsyncAPI v3 only)")
+	cmd.Flags().BoolVar(&f.AvroConfluent, "avro-confluent", false,
+		"Prefixes the Avro payloads with a magic byte and the schema registry ID (AsyncAPI v3 only)")
+	cmd.Flags().BoolVar(&f.ProtobufGoTypes, "protobuf-go-types", false,
+		"References the types generated by protoc-gen-go in the 'go_package' of the protobuf files,\n"+
+			"instead of generating them (AsyncAPI v3 only)")
+	cmd.Flags().BoolVar(&f.TraitMixins, "trait-mixins", false,
+		"Generates the headers of the message traits from the components as types embedded\n"+
+			"in the headers of the messages using them (AsyncAPI v3 only)")
+	cmd.Flags().BoolVar(&f.Examples, "examples", false,
+		"Generates the messages examples from the specification and a test checking them,\n"+
+			"in '<output>_examples.gen.go' and '<output>_examples_test.go' files (AsyncAPI v3 only)")
+	cmd.Flags().BoolVar(&f.RandomMessages, "random-messages", false,
+		"Generates functions creating random messages that are valid against the schemas,\n"+
+			"for property-based tests, fuzzing and load tests (AsyncAPI v3 only)")
+	cmd.Flags().BoolVar(&f.FakeControllers, "fake-controllers", false,
+		"Generates the interfaces of the controllers and fake implementations of them\n"+
+			"recording the calls and injecting received messages, for tests (AsyncAPI v3 only)")
+	cmd.Flags().BoolVar(&f.SplitFiles, "split-files", false,
+		"Writes the generated code in one file per part (app, user, types, messages, channels,\n"+
+			"errors, controller) named '<output>_<part>.gen.go', instead of a single file (AsyncAPI v3 only)")
+	cmd.Flags().StringVar(&f.TypesPackage, "types-package", "",
+		"Import path of the package containing the types, generated in it with '-g types'\n"+
+			"and imported by the application and user code generated with the same flag (AsyncAPI v3 only)")
 }
 
 // ToCodegenOptions processes command line flags structure to code generation tool options.
 func (f Flags) ToCodegenOptions() (options.Options, error) {
 	opt := options.Options{
-		OutputPath:         f.OutputPath,
-		PackageName:        f.PackageName,
-		DisableFormatting:  f.DisableFormatting,
-		ConvertKeys:        f.ConvertKeys,
-		NamingScheme:       f.NamingScheme,
-		IgnoreStringFormat: f.IgnoreStringFormat,
-		ForcePointers:      f.ForcePointers,
+		OutputPath:                 f.OutputPath,
+		PackageName:                f.PackageName,
+		DisableFormatting:          f.DisableFormatting,
+		ConvertKeys:                f.ConvertKeys,
+		NamingScheme:               f.NamingScheme,
+		IgnoreStringFormat:         f.IgnoreStringFormat,
+		IgnoreStringFormats:        f.IgnoreStringFormats,
+		ForcePointers:              f.ForcePointers,
+		UseNullable:                f.UseNullable,
+		CloudEvents:                f.CloudEvents,
+		AllowUnknownEnums:          f.AllowUnknownEnums,
+		StrictAdditionalProperties: f.StrictAdditionalProperties,
+		PlainMaps:                  f.PlainMaps,
+		EscapeChannelParameters:    f.EscapeChannelParameters,
+		AvroConfluent:              f.AvroConfluent,
+		ProtobufGoTypes:            f.ProtobufGoTypes,
+		TraitMixins:                f.TraitMixins,
+		Examples:                   f.Examples,
+		RandomMessages:             f.RandomMessages,
+		FakeControllers:            f.FakeControllers,
+		SplitFiles:                 f.SplitFiles,
+		TypesPackage:               f.TypesPackage,
 	}
 
 	if f.Generate != "" {
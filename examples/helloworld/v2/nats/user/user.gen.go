@@ -89,9 +89,20 @@ func (c UserController) wrapMiddlewares(
 	}
 }
 
-func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -112,6 +123,7 @@ func (c *UserController) Close(ctx context.Context) {
 func (c *UserController) PublishHello(
 	ctx context.Context,
 	msg HelloMessage,
+	options ...OperationOption,
 ) error {
 	// Get channel path
 	path := "hello"
@@ -130,8 +142,11 @@ func (c *UserController) PublishHello(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Publish the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, path, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, path, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -150,6 +165,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -172,6 +193,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -179,6 +214,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
@@ -95,9 +96,20 @@ func (c AppController) wrapMiddlewares(
 	}
 }
 
-func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -140,14 +152,10 @@ func (c *AppController) UnsubscribeFromAllChannels(ctx context.Context) {
 // SubscribeToReceiveHelloOperation will receive SayHelloMessageFromHelloChannel messages from Hello channel.
 //
 // Callback function 'fn' will be called each time a new message is received.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SubscribeToReceiveHelloOperation(
 	ctx context.Context,
 	fn func(ctx context.Context, msg SayHelloMessageFromHelloChannel) error,
+	options ...OperationOption,
 ) error {
 	// Get channel address
 	addr := "hello"
@@ -172,11 +180,14 @@ func (c *AppController) SubscribeToReceiveHelloOperation(
 	}
 	c.logger.Info(ctx, "Subscribed to channel")
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Asynchronously listen to new messages and pass them to app receiver
 	go func() {
 		for {
 			// Listen to next message
-			stop, err := c.listenToReceiveHelloOperationNextMessage(addr, sub, fn)
+			stop, err := c.listenToReceiveHelloOperationNextMessage(addr, sub, opts, fn)
 			if err != nil {
 				c.logger.Error(ctx, err.Error())
 			}
@@ -197,6 +208,7 @@ func (c *AppController) SubscribeToReceiveHelloOperation(
 func (c *AppController) listenToReceiveHelloOperationNextMessage(
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 	fn func(ctx context.Context, msg SayHelloMessageFromHelloChannel) error,
 ) (stop bool, err error) {
 	// Create a context for the received response
@@ -214,11 +226,21 @@ func (c *AppController) listenToReceiveHelloOperationNextMessage(
 		return true, nil
 	}
 
-	// Set broker message to context
+	// Set broker message and its metadata to context
 	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
+
+	// Set the address on which the message has been received to context, if
+	// the broker gives it, as it can differ from the subscribed one
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsSubscribedChannel, addr)
+	receivedAddr := addr
+	if acknowledgeableBrokerMessage.Metadata.Channel != "" {
+		receivedAddr = acknowledgeableBrokerMessage.Metadata.Channel
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsChannel, receivedAddr)
+	}
 
 	// Execute middlewares before handling the message
-	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, func(middlewareCtx context.Context) error {
+	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, func(middlewareCtx context.Context) error {
 		// Process message
 		msg, err := brokerMessageToSayHelloMessageFromHelloChannel(acknowledgeableBrokerMessage.BrokerMessage)
 		if err != nil {
@@ -234,6 +256,13 @@ func (c *AppController) listenToReceiveHelloOperationNextMessage(
 
 		return nil
 	}); err != nil {
+		// If a middleware asked to skip the message, then acknowledge it as it
+		// should not be processed again and do not consider it as an error
+		if errors.Is(err, extensions.ErrSkipMessage) {
+			acknowledgeableBrokerMessage.Ack()
+			return false, nil
+		}
+
 		c.errorHandler(msgCtx, addr, &acknowledgeableBrokerMessage, err)
 		// On error execute the acknowledgeableBrokerMessage nack() function and
 		// let the BrokerAcknowledgment decide what is the right nack behavior for the broker
@@ -284,6 +313,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -306,6 +341,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -313,6 +362,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -343,9 +419,15 @@ func NewSayHelloMessageFromHelloChannel() SayHelloMessageFromHelloChannel {
 func brokerMessageToSayHelloMessageFromHelloChannel(bMsg extensions.BrokerMessage) (SayHelloMessageFromHelloChannel, error) {
 	var msg SayHelloMessageFromHelloChannel
 
-	// Convert to string
-	payload := string(bMsg.Payload)
-	msg.Payload = payload // No need for type conversion to reference
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
+		return msg, err
+	}
 
 	// TODO: run checks on msg type
 
@@ -356,8 +438,12 @@ func brokerMessageToSayHelloMessageFromHelloChannel(bMsg extensions.BrokerMessag
 func (msg SayHelloMessageFromHelloChannel) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Convert to []byte
-	payload := []byte(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
+	if err != nil {
+		return extensions.BrokerMessage{}, err
+	}
 
 	// There is no headers here
 	headers := make(map[string][]byte, 0)
@@ -89,9 +89,20 @@ func (c UserController) wrapMiddlewares(
 	}
 }
 
-func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -109,12 +120,10 @@ func (c *UserController) Close(ctx context.Context) {
 }
 
 // SendToReceiveHelloOperation will send a SayHelloMessageFromHelloChannel message on Hello channel.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *UserController) SendToReceiveHelloOperation(
 	ctx context.Context,
 	msg SayHelloMessageFromHelloChannel,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := "hello"
@@ -133,8 +142,11 @@ func (c *UserController) SendToReceiveHelloOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -153,6 +165,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -175,6 +193,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -182,6 +214,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -212,9 +271,15 @@ func NewSayHelloMessageFromHelloChannel() SayHelloMessageFromHelloChannel {
 func brokerMessageToSayHelloMessageFromHelloChannel(bMsg extensions.BrokerMessage) (SayHelloMessageFromHelloChannel, error) {
 	var msg SayHelloMessageFromHelloChannel
 
-	// Convert to string
-	payload := string(bMsg.Payload)
-	msg.Payload = payload // No need for type conversion to reference
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
+		return msg, err
+	}
 
 	// TODO: run checks on msg type
 
@@ -225,8 +290,12 @@ func brokerMessageToSayHelloMessageFromHelloChannel(bMsg extensions.BrokerMessag
 func (msg SayHelloMessageFromHelloChannel) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Convert to []byte
-	payload := []byte(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
+	if err != nil {
+		return extensions.BrokerMessage{}, err
+	}
 
 	// There is no headers here
 	headers := make(map[string][]byte, 0)
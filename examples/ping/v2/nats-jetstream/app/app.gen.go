@@ -6,6 +6,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -99,9 +100,20 @@ func (c AppController) wrapMiddlewares(
 	}
 }
 
-func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -146,6 +158,7 @@ func (c *AppController) UnsubscribeAll(ctx context.Context) {
 func (c *AppController) SubscribePing(
 	ctx context.Context,
 	fn func(ctx context.Context, msg PingMessage) error,
+	options ...OperationOption,
 ) error {
 	// Get channel path
 	path := "ping.v2"
@@ -170,11 +183,14 @@ func (c *AppController) SubscribePing(
 	}
 	c.logger.Info(ctx, "Subscribed to channel")
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Asynchronously listen to new messages and pass them to app subscriber
 	go func() {
 		for {
 			// Listen to next message
-			stop, err := c.listenToPingNextMessage(path, sub, fn)
+			stop, err := c.listenToPingNextMessage(path, sub, opts, fn)
 			if err != nil {
 				c.logger.Error(ctx, err.Error())
 			}
@@ -195,11 +211,13 @@ func (c *AppController) SubscribePing(
 func (c *AppController) listenToPingNextMessage(
 	path string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 	fn func(ctx context.Context, msg PingMessage) error,
 ) (stop bool, err error) {
 	// Create a context for the received response
 	msgCtx, cancel := context.WithCancel(context.Background())
 	msgCtx = addAppContextValues(msgCtx, path)
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsSubscribedChannel, path)
 	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsDirection, "reception")
 	defer cancel()
 
@@ -212,11 +230,12 @@ func (c *AppController) listenToPingNextMessage(
 		return true, nil
 	}
 
-	// Set broker message to context
+	// Set broker message and its metadata to context
 	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
 
 	// Execute middlewares before handling the message
-	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, func(middlewareCtx context.Context) error {
+	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, func(middlewareCtx context.Context) error {
 		// Process message
 		msg, err := brokerMessageToPingMessage(acknowledgeableBrokerMessage.BrokerMessage)
 		if err != nil {
@@ -237,6 +256,13 @@ func (c *AppController) listenToPingNextMessage(
 
 		return nil
 	}); err != nil {
+		// If a middleware asked to skip the message, then acknowledge it as it
+		// should not be processed again and do not consider it as an error
+		if errors.Is(err, extensions.ErrSkipMessage) {
+			acknowledgeableBrokerMessage.Ack()
+			return false, nil
+		}
+
 		c.errorHandler(msgCtx, path, &acknowledgeableBrokerMessage, err)
 		// On error execute the acknowledgeableBrokerMessage nack() function and
 		// let the BrokerAcknowledgment decide what is the right nack behavior for the broker
@@ -274,6 +300,7 @@ func (c *AppController) UnsubscribePing(ctx context.Context) {
 func (c *AppController) PublishPong(
 	ctx context.Context,
 	msg PongMessage,
+	options ...OperationOption,
 ) error {
 	// Get channel path
 	path := "pong.v2"
@@ -298,8 +325,11 @@ func (c *AppController) PublishPong(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Publish the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, path, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, path, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -318,6 +348,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -340,6 +376,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -347,6 +397,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -5,7 +5,7 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
@@ -98,9 +98,20 @@ func (c AppController) wrapMiddlewares(
 	}
 }
 
-func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -143,14 +154,10 @@ func (c *AppController) UnsubscribeFromAllChannels(ctx context.Context) {
 // SubscribeToPingRequestOperation will receive Ping messages from Ping channel.
 //
 // Callback function 'fn' will be called each time a new message is received.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SubscribeToPingRequestOperation(
 	ctx context.Context,
 	fn func(ctx context.Context, msg PingMessage) error,
+	options ...OperationOption,
 ) error {
 	// Get channel address
 	addr := "ping.v3"
@@ -175,11 +182,14 @@ func (c *AppController) SubscribeToPingRequestOperation(
 	}
 	c.logger.Info(ctx, "Subscribed to channel")
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Asynchronously listen to new messages and pass them to app receiver
 	go func() {
 		for {
 			// Listen to next message
-			stop, err := c.listenToPingRequestOperationNextMessage(addr, sub, fn)
+			stop, err := c.listenToPingRequestOperationNextMessage(addr, sub, opts, fn)
 			if err != nil {
 				c.logger.Error(ctx, err.Error())
 			}
@@ -200,6 +210,7 @@ func (c *AppController) SubscribeToPingRequestOperation(
 func (c *AppController) listenToPingRequestOperationNextMessage(
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 	fn func(ctx context.Context, msg PingMessage) error,
 ) (stop bool, err error) {
 	// Create a context for the received response
@@ -217,11 +228,21 @@ func (c *AppController) listenToPingRequestOperationNextMessage(
 		return true, nil
 	}
 
-	// Set broker message to context
+	// Set broker message and its metadata to context
 	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
+
+	// Set the address on which the message has been received to context, if
+	// the broker gives it, as it can differ from the subscribed one
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsSubscribedChannel, addr)
+	receivedAddr := addr
+	if acknowledgeableBrokerMessage.Metadata.Channel != "" {
+		receivedAddr = acknowledgeableBrokerMessage.Metadata.Channel
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsChannel, receivedAddr)
+	}
 
 	// Execute middlewares before handling the message
-	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, func(middlewareCtx context.Context) error {
+	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, func(middlewareCtx context.Context) error {
 		// Process message
 		msg, err := brokerMessageToPingMessage(acknowledgeableBrokerMessage.BrokerMessage)
 		if err != nil {
@@ -242,6 +263,13 @@ func (c *AppController) listenToPingRequestOperationNextMessage(
 
 		return nil
 	}); err != nil {
+		// If a middleware asked to skip the message, then acknowledge it as it
+		// should not be processed again and do not consider it as an error
+		if errors.Is(err, extensions.ErrSkipMessage) {
+			acknowledgeableBrokerMessage.Ack()
+			return false, nil
+		}
+
 		c.errorHandler(msgCtx, addr, &acknowledgeableBrokerMessage, err)
 		// On error execute the acknowledgeableBrokerMessage nack() function and
 		// let the BrokerAcknowledgment decide what is the right nack behavior for the broker
@@ -253,7 +281,7 @@ func (c *AppController) listenToPingRequestOperationNextMessage(
 
 // ReplyToPingRequestOperation is a helper function to
 // reply to a Ping message with a Pong message on Pong channel.
-func (c *AppController) ReplyToPingRequestOperation(ctx context.Context, recvMsg PingMessage, fn func(replyMsg *PongMessage)) error {
+func (c *AppController) ReplyToPingRequestOperation(ctx context.Context, recvMsg PingMessage, fn func(replyMsg *PongMessage), options ...OperationOption) error {
 	// Create reply message
 	replyMsg := NewPongMessage()
 	replyMsg.SetAsResponseFrom(&recvMsg)
@@ -262,7 +290,7 @@ func (c *AppController) ReplyToPingRequestOperation(ctx context.Context, recvMsg
 	fn(&replyMsg)
 
 	// Publish reply
-	return c.SendAsReplyToPingRequestOperation(ctx, replyMsg)
+	return c.SendAsReplyToPingRequestOperation(ctx, replyMsg, options...)
 }
 
 // UnsubscribeFromPingRequestOperation will stop the reception of Ping messages from Ping channel.
@@ -292,12 +320,10 @@ func (c *AppController) UnsubscribeFromPingRequestOperation(
 }
 
 // SendAsReplyToPingRequestOperation will send a Pong message on Pong channel.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SendAsReplyToPingRequestOperation(
 	ctx context.Context,
 	msg PongMessage,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := "pong.v3"
@@ -324,8 +350,11 @@ func (c *AppController) SendAsReplyToPingRequestOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -344,6 +373,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -366,6 +401,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -373,6 +422,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -424,6 +500,12 @@ func NewPingMessage() PingMessage {
 	u := uuid.New().String()
 	msg.Headers.CorrelationId = &u
 
+	// Set constant 'event' payload property
+	{
+		v := string("ping")
+		msg.Payload.Event = &v
+	}
+
 	return msg
 }
 
@@ -431,9 +513,13 @@ func NewPingMessage() PingMessage {
 func brokerMessageToPingMessage(bMsg extensions.BrokerMessage) (PingMessage, error) {
 	var msg PingMessage
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
@@ -453,12 +539,30 @@ func brokerMessageToPingMessage(bMsg extensions.BrokerMessage) (PingMessage, err
 	return msg, nil
 }
 
+const (
+	// PingMessageCorrelationIdHeader is the key of the 'correlationId' header of PingMessage.
+	PingMessageCorrelationIdHeader = "correlationId"
+)
+
+// GetPingMessageCorrelationIdHeader returns the 'correlationId' header from a broker
+// message carrying PingMessage (e.g. in middlewares), decoded as in the
+// message headers. It returns false if the header is not set.
+func GetPingMessageCorrelationIdHeader(bMsg extensions.BrokerMessage) (h string, ok bool, err error) {
+	v, ok := bMsg.Headers[PingMessageCorrelationIdHeader]
+	if !ok {
+		return h, false, nil
+	}
+	h = string(v)
+	return h, true, err
+}
+
 // toBrokerMessage will generate a generic broker message from PingMessage data
 func (msg PingMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
@@ -526,6 +630,12 @@ func NewPongMessage() PongMessage {
 	u := uuid.New().String()
 	msg.Headers.CorrelationId = &u
 
+	// Set constant 'event' payload property
+	{
+		v := string("pong")
+		msg.Payload.Event = &v
+	}
+
 	return msg
 }
 
@@ -533,9 +643,13 @@ func NewPongMessage() PongMessage {
 func brokerMessageToPongMessage(bMsg extensions.BrokerMessage) (PongMessage, error) {
 	var msg PongMessage
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
@@ -555,12 +669,30 @@ func brokerMessageToPongMessage(bMsg extensions.BrokerMessage) (PongMessage, err
 	return msg, nil
 }
 
+const (
+	// PongMessageCorrelationIdHeader is the key of the 'correlationId' header of PongMessage.
+	PongMessageCorrelationIdHeader = "correlationId"
+)
+
+// GetPongMessageCorrelationIdHeader returns the 'correlationId' header from a broker
+// message carrying PongMessage (e.g. in middlewares), decoded as in the
+// message headers. It returns false if the header is not set.
+func GetPongMessageCorrelationIdHeader(bMsg extensions.BrokerMessage) (h string, ok bool, err error) {
+	v, ok := bMsg.Headers[PongMessageCorrelationIdHeader]
+	if !ok {
+		return h, false, nil
+	}
+	h = string(v)
+	return h, true, err
+}
+
 // toBrokerMessage will generate a generic broker message from PongMessage data
 func (msg PongMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
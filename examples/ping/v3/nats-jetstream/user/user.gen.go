@@ -5,7 +5,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
@@ -92,9 +91,20 @@ func (c UserController) wrapMiddlewares(
 	}
 }
 
-func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -114,11 +124,10 @@ func (c *UserController) Close(ctx context.Context) {
 // SendToPingRequestOperation will send a Ping message on Ping channel.
 //
 // NOTE: this won't wait for reply, use the normal version to get the reply or do the catching reply manually.
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *UserController) SendToPingRequestOperation(
 	ctx context.Context,
 	msg PingMessage,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := "ping.v3"
@@ -143,8 +152,11 @@ func (c *UserController) SendToPingRequestOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -155,11 +167,15 @@ func (c *UserController) SendToPingRequestOperation(
 // reply with the same correlation ID. Otherwise, it will returns the first
 // message on the reply channel.
 //
+// The operation options are applied both to the request publication and to the
+// reply reception: the operation middlewares will be executed on both messages.
+//
 // A timeout can be set in context to avoid blocking operation, if needed.
 
 func (c *UserController) RequestToPingRequestOperation(
 	ctx context.Context,
 	msg PingMessage,
+	options ...OperationOption,
 ) (PongMessage, error) {
 	// Get receiving channel address
 	addr := "pong.v3"
@@ -191,15 +207,18 @@ func (c *UserController) RequestToPingRequestOperation(
 	}
 
 	// Send the message
-	if err := c.SendToPingRequestOperation(ctx, msg); err != nil {
+	if err := c.SendToPingRequestOperation(ctx, msg, options...); err != nil {
 		c.logger.Error(ctx, "error happened when sending message", extensions.LogInfo{Key: "error", Value: err.Error()})
 		return PongMessage{}, fmt.Errorf("error happened when sending message: %w", err)
 	}
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Wait for corresponding response
 	for {
 		// Listen to next message
-		msg, err := c.waitForPingRequestOperationNextResponse(ctx, addr, sub, msg)
+		msg, err := c.waitForPingRequestOperationNextResponse(ctx, addr, sub, opts, msg)
 		if err != nil {
 			c.logger.Error(ctx, err.Error())
 		}
@@ -217,6 +236,7 @@ func (c *UserController) waitForPingRequestOperationNextResponse(
 	ctx context.Context,
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 	msg PingMessage,
 ) (*PongMessage, error) {
 	// Create a context for the received response
@@ -252,9 +272,10 @@ func (c *UserController) waitForPingRequestOperationNextResponse(
 
 		// Set context with received values as it is the expected message
 		msgCtx := context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
 
 		// Execute middlewares before returning
-		if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, nil); err != nil {
+		if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, nil); err != nil {
 			return nil, err
 		}
 
@@ -289,6 +310,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -311,6 +338,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -318,6 +359,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -369,6 +437,12 @@ func NewPingMessage() PingMessage {
 	u := uuid.New().String()
 	msg.Headers.CorrelationId = &u
 
+	// Set constant 'event' payload property
+	{
+		v := string("ping")
+		msg.Payload.Event = &v
+	}
+
 	return msg
 }
 
@@ -376,9 +450,13 @@ func NewPingMessage() PingMessage {
 func brokerMessageToPingMessage(bMsg extensions.BrokerMessage) (PingMessage, error) {
 	var msg PingMessage
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
@@ -398,12 +476,30 @@ func brokerMessageToPingMessage(bMsg extensions.BrokerMessage) (PingMessage, err
 	return msg, nil
 }
 
+const (
+	// PingMessageCorrelationIdHeader is the key of the 'correlationId' header of PingMessage.
+	PingMessageCorrelationIdHeader = "correlationId"
+)
+
+// GetPingMessageCorrelationIdHeader returns the 'correlationId' header from a broker
+// message carrying PingMessage (e.g. in middlewares), decoded as in the
+// message headers. It returns false if the header is not set.
+func GetPingMessageCorrelationIdHeader(bMsg extensions.BrokerMessage) (h string, ok bool, err error) {
+	v, ok := bMsg.Headers[PingMessageCorrelationIdHeader]
+	if !ok {
+		return h, false, nil
+	}
+	h = string(v)
+	return h, true, err
+}
+
 // toBrokerMessage will generate a generic broker message from PingMessage data
 func (msg PingMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
@@ -471,6 +567,12 @@ func NewPongMessage() PongMessage {
 	u := uuid.New().String()
 	msg.Headers.CorrelationId = &u
 
+	// Set constant 'event' payload property
+	{
+		v := string("pong")
+		msg.Payload.Event = &v
+	}
+
 	return msg
 }
 
@@ -478,9 +580,13 @@ func NewPongMessage() PongMessage {
 func brokerMessageToPongMessage(bMsg extensions.BrokerMessage) (PongMessage, error) {
 	var msg PongMessage
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
@@ -500,12 +606,30 @@ func brokerMessageToPongMessage(bMsg extensions.BrokerMessage) (PongMessage, err
 	return msg, nil
 }
 
+const (
+	// PongMessageCorrelationIdHeader is the key of the 'correlationId' header of PongMessage.
+	PongMessageCorrelationIdHeader = "correlationId"
+)
+
+// GetPongMessageCorrelationIdHeader returns the 'correlationId' header from a broker
+// message carrying PongMessage (e.g. in middlewares), decoded as in the
+// message headers. It returns false if the header is not set.
+func GetPongMessageCorrelationIdHeader(bMsg extensions.BrokerMessage) (h string, ok bool, err error) {
+	v, ok := bMsg.Headers[PongMessageCorrelationIdHeader]
+	if !ok {
+		return h, false, nil
+	}
+	h = string(v)
+	return h, true, err
+}
+
 // toBrokerMessage will generate a generic broker message from PongMessage data
 func (msg PongMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
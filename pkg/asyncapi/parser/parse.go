@@ -46,20 +46,32 @@ func FromFile(params FromFileParams) (asyncapi.Specification, error) {
 		return nil, err
 	}
 
+	var spec asyncapi.Specification
 	switch filepath.Ext(params.Path) {
 	case ".yaml", ".yml":
-		return FromYAML(FromYAMLParams{
+		spec, err = FromYAML(FromYAMLParams{
 			Data:         data,
 			MajorVersion: params.MajorVersion,
 		})
 	case ".json":
-		return FromJSON(FromJSONParams{
+		spec, err = FromJSON(FromJSONParams{
 			Data:         data,
 			MajorVersion: params.MajorVersion,
 		})
 	default:
 		return nil, fmt.Errorf("%w: %q", ErrInvalidFileFormat, params.MajorVersion)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the path of the specification, in order to resolve the files
+	// referenced by the specification relatively to it
+	if s, ok := spec.(interface{ SetPath(path string) }); ok {
+		s.SetPath(params.Path)
+	}
+
+	return spec, nil
 }
 
 // FromYAMLParams are the parameters to parse an AsyncAPI specification from a YAML file.
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/asyncapi"
+	asyncapiv3 "github.com/lerenn/asyncapi-codegen/pkg/asyncapi/v3"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -48,3 +49,25 @@ func (suite *ParseSuite) TestIncorrectVersions() {
 		suite.Require().ErrorIs(err, ErrInvalidVersion)
 	}
 }
+
+func (suite *ParseSuite) TestFilesRelativeToSpecification() {
+	// The protobuf files are referenced relatively to the specification,
+	// that is not in the working directory
+	spec, err := FromFile(FromFileParams{
+		Path: "../../../test/v3/features/protobuf/asyncapi.yaml",
+	})
+	suite.Require().NoError(err)
+	suite.Require().NoError(spec.Process())
+
+	specV3, ok := spec.(*asyncapiv3.Specification)
+	suite.Require().True(ok)
+	payload := specV3.Components.Messages["User"].Payload
+	suite.Require().True(payload.IsProtobuf())
+	suite.Require().Equal("features.protobuf.User", payload.ProtobufMessage)
+
+	// Imported messages and oneofs are converted
+	suite.Require().Equal("Address", payload.Properties["address"].Title)
+	suite.Require().Equal("Address", payload.Properties["postal"].Title)
+	suite.Require().NotContains(payload.Required, "phone")
+	suite.Require().Equal("date-time", payload.Properties["created_at"].Format)
+}
@@ -0,0 +1,248 @@
+package asyncapiv3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+)
+
+const (
+	// AvroSchemaFormatPrefix is the prefix of the schema formats of Avro schemas
+	// (e.g. 'application/vnd.apache.avro;version=1.9.0').
+	AvroSchemaFormatPrefix = "application/vnd.apache.avro"
+)
+
+var (
+	// ErrInvalidAvroSchema is the error returned when an Avro schema can't be
+	// converted to a schema.
+	ErrInvalidAvroSchema = fmt.Errorf("%w: invalid avro schema", extensions.ErrAsyncAPI)
+)
+
+// IsAvroSchemaFormat checks if the schema format corresponds to Avro schemas.
+func IsAvroSchemaFormat(format string) bool {
+	return strings.HasPrefix(format, AvroSchemaFormatPrefix)
+}
+
+// IsAvro checks if the schema, or the referenced schema, comes from an Avro
+// schema and should be (un)marshaled with the Avro binary encoding.
+func (s Schema) IsAvro() bool {
+	return s.Follow().AvroSchema != ""
+}
+
+// setFromAvro sets the schema from the JSON representation of an Avro schema.
+func (s *Schema) setFromAvro(data []byte) error {
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAvroSchema, err)
+	}
+
+	// Check that the schema is valid for the generated code
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAvroSchema, err)
+	}
+	if _, err := extensions.ParseAvroSchema(compact.String()); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAvroSchema, err)
+	}
+
+	c := avroConverter{named: make(map[string]*Schema)}
+	if _, err := c.convert(raw, "", s); err != nil {
+		return err
+	}
+	s.AvroSchema = compact.String()
+
+	return nil
+}
+
+// avroConverter converts Avro schemas to schemas, keeping track of the named
+// types by full name so they can be referenced.
+type avroConverter struct {
+	named map[string]*Schema
+}
+
+// convert converts an Avro schema into the target schema, or into a new
+// schema if there is no target.
+func (c avroConverter) convert(v any, namespace string, target *Schema) (*Schema, error) {
+	if target == nil {
+		target = &Schema{}
+	}
+
+	switch t := v.(type) {
+	case string:
+		return c.convertName(t, namespace, target)
+	case []any:
+		return c.convertUnion(t, namespace, target)
+	case map[string]any:
+		return c.convertComplex(t, namespace, target)
+	default:
+		return nil, fmt.Errorf("%w: unexpected schema %v", ErrInvalidAvroSchema, v)
+	}
+}
+
+func (c avroConverter) convertName(name, namespace string, target *Schema) (*Schema, error) {
+	switch name {
+	case "boolean":
+		target.Type = "boolean"
+	case "int":
+		target.Type, target.Format = SchemaTypeIsInteger.String(), "int32"
+	case "long":
+		target.Type = SchemaTypeIsInteger.String()
+	case "float":
+		target.Type, target.Format = SchemaTypeIsNumber.String(), "float"
+	case "double":
+		target.Type = SchemaTypeIsNumber.String()
+	case "bytes":
+		target.Type, target.Format = SchemaTypeIsString.String(), "binary"
+	case "string":
+		target.Type = SchemaTypeIsString.String()
+	default:
+		named, ok := c.named[avroFullName(name, namespace)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidAvroSchema, name)
+		}
+		target.ReferenceTo = named
+		target.Title = named.Title
+		return target, nil
+	}
+
+	target.Title = name
+	return target, nil
+}
+
+func (c avroConverter) convertUnion(branches []any, namespace string, target *Schema) (*Schema, error) {
+	others := make([]any, 0, len(branches))
+	for _, b := range branches {
+		if b != "null" {
+			others = append(others, b)
+		}
+	}
+	nullable := len(others) < len(branches)
+
+	switch len(others) {
+	case 0:
+		return nil, fmt.Errorf("%w: union without non-null type", ErrInvalidAvroSchema)
+	case 1:
+		// Convert directly into the target, as it can be a named type
+		if _, err := c.convert(others[0], namespace, target); err != nil {
+			return nil, err
+		}
+	default:
+		for _, b := range others {
+			v, err := c.convert(b, namespace, nil)
+			if err != nil {
+				return nil, err
+			}
+			target.OneOf = append(target.OneOf, v)
+		}
+	}
+	target.Nullable = nullable
+
+	return target, nil
+}
+
+//nolint:cyclop // Not necessary to split the Avro types
+func (c avroConverter) convertComplex(m map[string]any, namespace string, target *Schema) (*Schema, error) {
+	typ, ok := m["type"].(string)
+	if !ok {
+		return c.convert(m["type"], namespace, target)
+	}
+	target.Description, _ = m["doc"].(string)
+
+	// Register the named types before converting their content, as they can be recursive
+	if name, ok := m["name"].(string); ok && (typ == "record" || typ == "error" || typ == "enum" || typ == "fixed") {
+		if ns, ok := m["namespace"].(string); ok {
+			namespace = ns
+		}
+		fullName := avroFullName(name, namespace)
+		if i := strings.LastIndex(fullName, "."); i >= 0 {
+			namespace = fullName[:i]
+		}
+		target.Title = fullName[strings.LastIndex(fullName, ".")+1:]
+		c.named[fullName] = target
+	}
+
+	switch typ {
+	case "record", "error":
+		return c.convertRecord(m, namespace, target)
+	case "enum":
+		target.Type = SchemaTypeIsString.String()
+		symbols, _ := m["symbols"].([]any)
+		target.Enum = append(target.Enum, symbols...)
+	case "array":
+		target.Type = SchemaTypeIsArray.String()
+		items, err := c.convert(m["items"], namespace, nil)
+		if err != nil {
+			return nil, err
+		}
+		target.Items = items
+	case "map":
+		target.Type = SchemaTypeIsObject.String()
+		values, err := c.convert(m["values"], namespace, nil)
+		if err != nil {
+			return nil, err
+		}
+		target.AdditionalProperties = values
+	case "fixed":
+		target.Type, target.Format = SchemaTypeIsString.String(), "binary"
+	default:
+		if _, err := c.convertName(typ, namespace, target); err != nil {
+			return nil, err
+		}
+		c.convertLogicalType(m, target)
+	}
+
+	return target, nil
+}
+
+func (c avroConverter) convertRecord(m map[string]any, namespace string, target *Schema) (*Schema, error) {
+	target.Type = SchemaTypeIsObject.String()
+	target.Properties = make(map[string]*Schema)
+
+	fields, _ := m["fields"].([]any)
+	for _, f := range fields {
+		field, _ := f.(map[string]any)
+		name, _ := field["name"].(string)
+
+		p, err := c.convert(field["type"], namespace, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w (field %q)", err, name)
+		}
+		if doc, ok := field["doc"].(string); ok {
+			p.Description = doc
+		}
+		// Only keep the defaults that have the same representation in JSON
+		if def, ok := field["default"]; ok && p.Format != "binary" && p.Format != "date" && p.Format != "date-time" {
+			p.Default = def
+		}
+
+		target.Properties[name] = p
+		if !p.Nullable {
+			target.Required = append(target.Required, name)
+		}
+	}
+
+	return target, nil
+}
+
+// convertLogicalType converts the logical types that have a corresponding
+// string format. The other logical types keep their underlying type.
+func (c avroConverter) convertLogicalType(m map[string]any, target *Schema) {
+	switch logicalType, _ := m["logicalType"].(string); {
+	case logicalType == "date" && target.Format == "int32":
+		target.Type, target.Format = SchemaTypeIsString.String(), "date"
+	case strings.HasPrefix(logicalType, "timestamp-") || strings.HasPrefix(logicalType, "local-timestamp-"):
+		target.Type, target.Format = SchemaTypeIsString.String(), "date-time"
+	case logicalType == "uuid" && target.Type == SchemaTypeIsString.String():
+		target.Format = "uuid"
+	}
+}
+
+func avroFullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
@@ -0,0 +1,45 @@
+package asyncapiv3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestAvroSuite(t *testing.T) {
+	suite.Run(t, new(AvroSuite))
+}
+
+type AvroSuite struct {
+	suite.Suite
+}
+
+func (suite *AvroSuite) TestNamedTypesByFullName() {
+	var s Schema
+	suite.Require().NoError(s.setFromAvro([]byte(`{"type": "record", "name": "Order", "namespace": "com.shop", "fields": [
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["OPEN", "CLOSED"]}},
+		{"name": "payment", "type": {"type": "record", "name": "Payment", "namespace": "com.bank", "fields": [
+			{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["PAID"]}},
+			{"name": "order", "type": ["null", "com.shop.Status"]}
+		]}},
+		{"name": "previous", "type": ["null", "Status"]}
+	]}`)))
+
+	// Names without namespace are resolved in the namespace of the enclosing type
+	payment := s.Properties["payment"]
+	suite.Require().Equal([]any{"PAID"}, payment.Properties["status"].Enum)
+	suite.Require().Equal(s.Properties["status"], payment.Properties["order"].ReferenceTo)
+	suite.Require().Equal(s.Properties["status"], s.Properties["previous"].ReferenceTo)
+}
+
+func (suite *AvroSuite) TestUnknownShortName() {
+	// A short name can't reference a type from another namespace
+	var s Schema
+	err := s.setFromAvro([]byte(`{"type": "record", "name": "Order", "namespace": "com.shop", "fields": [
+		{"name": "payment", "type": {"type": "record", "name": "Payment", "namespace": "com.bank", "fields": [
+			{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["PAID"]}}
+		]}},
+		{"name": "status", "type": "Status"}
+	]}`))
+	suite.Require().ErrorIs(err, ErrInvalidAvroSchema)
+}
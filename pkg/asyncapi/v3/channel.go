@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
 )
 
 const (
@@ -152,10 +153,21 @@ func (ch *Channel) Follow() *Channel {
 	return ch
 }
 
-// GetMessage will return the channel message.
+// GetMessage will return the first channel message, sorted by name.
 func (ch Channel) GetMessage() (*Message, error) {
-	for _, m := range ch.Follow().Messages {
-		return m.Follow(), nil // TODO: change
+	msgs := ch.GetMessages()
+	if len(msgs) == 0 {
+		return nil, fmt.Errorf("%w: channel %q", ErrNoMessageInChannel, ch.Name)
 	}
-	return nil, fmt.Errorf("%w: channel %q", ErrNoMessageInChannel, ch.Name)
+	return msgs[0], nil
+}
+
+// GetMessages will return all the channel messages, sorted by name.
+func (ch Channel) GetMessages() []*Message {
+	msgs := make([]*Message, 0, len(ch.Follow().Messages))
+	for _, name := range utils.SortedKeys(ch.Follow().Messages) {
+		msgs = append(msgs, ch.Follow().Messages[name].Follow())
+	}
+
+	return msgs
 }
@@ -17,6 +17,10 @@ type Extensions struct {
 	// Controls whether to include omitempty in JSON tags
 	// If false, omitempty will be removed from JSON tags even if the field can be null
 	ExtOmitEmpty *bool `json:"x-omitempty"`
+
+	// Embed the schemas referenced in allOf as Go structs, instead of
+	// flattening their properties into the generated struct
+	ExtGoEmbed bool `json:"x-go-embed"`
 }
 
 // GoTypeImportExtension specifies the required import statement
@@ -110,3 +114,29 @@ func importsMapToList(importsSet map[GoTypeImportPath]GoTypeImportName) []string
 	}
 	return imports
 }
+
+// ProtobufGoImports collects the imports of the packages of the types generated
+// by protoc-gen-go for the protobuf payloads of the messages in the Specification.
+// Returns import strings like `alias "abc.xyz/repo/package"` for code generation.
+func (s Specification) ProtobufGoImports() []string {
+	importsSet := make(map[GoTypeImportPath]GoTypeImportName)
+	addImport := func(msg *Message) {
+		if msg == nil || msg.Follow().Payload == nil {
+			return
+		}
+		if imp := msg.Follow().Payload.Follow().ProtobufGoImport; imp != nil {
+			importsSet[imp.Path] = imp.Name
+		}
+	}
+
+	for _, ch := range s.Channels {
+		for _, msg := range ch.Messages {
+			addImport(msg)
+		}
+	}
+	for _, msg := range s.Components.Messages {
+		addImport(msg)
+	}
+
+	return importsMapToList(importsSet)
+}
@@ -2,6 +2,8 @@ package asyncapiv3
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
@@ -160,7 +162,22 @@ func (msg *Message) setDependencies(spec Specification) error {
 	}
 
 	// Set traits dependencies
-	return msg.setTraitsDependencies(spec)
+	if err := msg.setTraitsDependencies(spec); err != nil {
+		return err
+	}
+
+	// Set the protobuf content type if there is none for protobuf payloads
+	if msg.ContentType == "" && msg.Payload != nil && msg.Payload.IsProtobuf() {
+		msg.ContentType = extensions.ProtobufContentType
+	}
+
+	// Set the default content type of the specification if there is none,
+	// except for Avro payloads that have their own encoding
+	if msg.ContentType == "" && msg.Reference == "" && (msg.Payload == nil || !msg.Payload.IsAvro()) {
+		msg.ContentType = spec.DefaultContentType
+	}
+
+	return nil
 }
 
 func (msg *Message) setReference(spec Specification) error {
@@ -279,11 +296,27 @@ func (msg *Message) setTraitsDependencies(spec Specification) error {
 		if err := msg.ApplyTrait(t.Follow(), spec); err != nil {
 			return err
 		}
+		msg.addTraitHeaders(t)
 	}
 
 	return nil
 }
 
+// addTraitHeaders keeps the headers of a trait from the components on the
+// message headers, so they can be generated as a type shared by the messages.
+func (msg *Message) addTraitHeaders(t *MessageTrait) {
+	if t.ReferenceTo == nil || t.ReferenceTo.Headers == nil || msg.Headers == nil || msg.Headers.ReferenceTo != nil {
+		return
+	}
+
+	for _, h := range msg.Headers.TraitHeaders {
+		if h == t.ReferenceTo.Headers {
+			return
+		}
+	}
+	msg.Headers.TraitHeaders = append(msg.Headers.TraitHeaders, t.ReferenceTo.Headers)
+}
+
 func (msg Message) isCorrelationIDRequired() bool {
 	if msg.CorrelationID == nil || msg.CorrelationID.Location == "" {
 		return false
@@ -487,9 +520,10 @@ func (msg *Message) mergeWithMessageHeaders(msg2 Message, spec Specification) er
 }
 
 // Follow returns referenced message if specified or the actual message.
+// If the referenced message is also a reference, it will be followed too.
 func (msg *Message) Follow() *Message {
 	if msg.ReferenceTo != nil {
-		return msg.ReferenceTo
+		return msg.ReferenceTo.Follow()
 	}
 	return msg
 }
@@ -564,6 +598,9 @@ func (msg *Message) mergeHeaders(spec Specification, headers *Schema) error {
 	// Check if message headers are nil, then create them
 	if msg.Headers == nil {
 		newHeaders := utils.ToValue(headers.Follow())
+		// Copy the properties and requirements, as other traits can be merged
+		newHeaders.Properties = maps.Clone(newHeaders.Properties)
+		newHeaders.Required = slices.Clone(newHeaders.Required)
 		msg.Headers = &newHeaders
 		if err := newHeaders.generateMetadata(msg.Name, "Headers", nil, false); err != nil {
 			return err
@@ -599,3 +636,34 @@ func (msg *Message) mergePayload(spec Specification, payload *Schema) error {
 func (msg Message) HaveCorrelationID() bool {
 	return msg.Follow().CorrelationID.Exists()
 }
+
+// CorrelationIDSchema returns the schema of the field holding the correlation
+// ID, or nil if there is none.
+func (msg Message) CorrelationIDSchema() *Schema {
+	if !msg.HaveCorrelationID() {
+		return nil
+	}
+
+	return msg.LocationSchema(msg.Follow().CorrelationID.Location)
+}
+
+// LocationSchema returns the schema of the field at the given runtime
+// expression location (e.g. '$message.header#/replyTo'), or nil if there is none.
+func (msg Message) LocationSchema(location string) *Schema {
+	var s *Schema
+	switch {
+	case strings.HasPrefix(location, "$message.header#"):
+		s = msg.Follow().Headers
+	case strings.HasPrefix(location, "$message.payload#"):
+		s = msg.Follow().Payload
+	}
+
+	for _, v := range strings.Split(location, "/")[1:] {
+		if s == nil {
+			return nil
+		}
+		s = s.Follow().Properties[v]
+	}
+
+	return s
+}
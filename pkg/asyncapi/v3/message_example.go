@@ -7,7 +7,7 @@ type MessageExample struct {
 	// --- AsyncAPI fields -----------------------------------------------------
 
 	Headers   map[string]any `json:"headers"`
-	Payload   map[string]any `json:"payload"`
+	Payload   any            `json:"payload"`
 	Name      string         `json:"name"`
 	Summary   string         `json:"summary"`
 	Reference string         `json:"$ref"`
@@ -46,3 +46,11 @@ func (me *MessageExample) setDependencies(spec Specification) error {
 
 	return nil
 }
+
+// Follow returns referenced MessageExample if specified or the actual MessageExample.
+func (me *MessageExample) Follow() *MessageExample {
+	if me.ReferenceTo != nil {
+		return me.ReferenceTo
+	}
+	return me
+}
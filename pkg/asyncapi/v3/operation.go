@@ -191,6 +191,21 @@ func (op Operation) GetMessage() (*Message, error) {
 	return op.Channel.GetMessage()
 }
 
+// GetMessages will return all the operation messages, or all the channel
+// messages if the operation doesn't restrict them.
+func (op Operation) GetMessages() []*Message {
+	if len(op.Messages) == 0 {
+		return op.Channel.GetMessages()
+	}
+
+	msgs := make([]*Message, 0, len(op.Messages))
+	for _, msg := range op.Messages {
+		msgs = append(msgs, msg.Follow())
+	}
+
+	return msgs
+}
+
 // ApplyTrait applies a trait to the operation.
 func (op *Operation) ApplyTrait(ot *OperationTrait, spec Specification) {
 	// Check operation is not nil
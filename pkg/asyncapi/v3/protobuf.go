@@ -0,0 +1,359 @@
+package asyncapiv3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	// ProtobufSchemaFormatPrefix is the prefix of the schema formats of protobuf
+	// schemas (e.g. 'application/vnd.google.protobuf;version=3').
+	ProtobufSchemaFormatPrefix = "application/vnd.google.protobuf"
+
+	// protobufInlineFile is the name of the protobuf files that are inline in
+	// the specification.
+	protobufInlineFile = "asyncapi-inline.proto"
+)
+
+var (
+	// ErrInvalidProtobufSchema is the error returned when a protobuf schema
+	// can't be converted to a schema.
+	ErrInvalidProtobufSchema = fmt.Errorf("%w: invalid protobuf schema", extensions.ErrAsyncAPI)
+)
+
+// IsProtobufSchemaFormat checks if the schema format corresponds to protobuf schemas.
+func IsProtobufSchemaFormat(format string) bool {
+	return strings.HasPrefix(format, ProtobufSchemaFormatPrefix)
+}
+
+// IsProtobuf checks if the schema, or the referenced schema, comes from a
+// protobuf message and should be (un)marshaled with the protobuf binary encoding.
+func (s Schema) IsProtobuf() bool {
+	return s.Follow().ProtobufSchema != ""
+}
+
+// protobufSource is the protobuf file of a schema, that is loaded when the
+// specification is processed, as referenced files are relative to it.
+type protobufSource struct {
+	// Content is the content of the file when it is inline.
+	Content string
+	// File is the path of the file when it is referenced.
+	File string
+	// Message is the name of the message in the reference fragment, if any.
+	Message string
+}
+
+// setFromProtobuf sets the source of the schema from a protobuf file, that is
+// either inline or referenced with '$ref' (e.g. './user.proto#User'). It is
+// loaded when the specification is processed.
+func (s *Schema) setFromProtobuf(data []byte) error {
+	// Inline protobuf file
+	var content string
+	if err := json.Unmarshal(data, &content); err == nil {
+		s.protobufSource = &protobufSource{Content: content}
+		return nil
+	}
+
+	// Referenced protobuf file
+	var ref struct {
+		Ref string `json:"$ref"`
+	}
+	if err := json.Unmarshal(data, &ref); err != nil || ref.Ref == "" {
+		return fmt.Errorf("%w: expected a protobuf file content or reference", ErrInvalidProtobufSchema)
+	}
+	file, message, _ := strings.Cut(ref.Ref, "#")
+	s.protobufSource = &protobufSource{File: file, Message: message}
+
+	return nil
+}
+
+// loadProtobufSchemas loads the protobuf files of the schemas from the
+// Specification and its dependencies.
+func (s *Specification) loadProtobufSchemas() error {
+	for _, spec := range s.dependencies {
+		if err := spec.loadProtobufSchemas(); err != nil {
+			return err
+		}
+	}
+
+	// Protobuf schemas are only allowed as schemas and payloads
+	dir := filepath.Dir(s.path)
+	schemas := make([]*Schema, 0, len(s.Components.Schemas))
+	for _, schema := range s.Components.Schemas {
+		schemas = append(schemas, schema)
+	}
+	for _, msg := range s.Components.Messages {
+		schemas = append(schemas, msg.Payload)
+	}
+	for _, channels := range []map[string]*Channel{s.Channels, s.Components.Channels} {
+		for _, ch := range channels {
+			for _, msg := range ch.Messages {
+				schemas = append(schemas, msg.Payload)
+			}
+		}
+	}
+
+	for _, schema := range schemas {
+		if schema == nil || schema.protobufSource == nil {
+			continue
+		}
+		if err := schema.loadProtobuf(dir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadProtobuf compiles the protobuf file of the schema, with the files
+// referenced relatively to the given directory, and sets the schema from the
+// message in the reference fragment, or the first message of the file.
+func (s *Schema) loadProtobuf(dir string) error {
+	src := s.protobufSource
+	s.protobufSource = nil
+
+	// Resolve the imports relatively to the protobuf file, or to the
+	// specification for inline files
+	file, importPath := protobufInlineFile, dir
+	if src.File != "" {
+		file = filepath.Join(dir, filepath.FromSlash(src.File))
+		file, importPath = filepath.Base(file), filepath.Dir(file)
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: []string{importPath},
+			Accessor: func(p string) (io.ReadCloser, error) {
+				if src.File == "" && p == filepath.Join(importPath, protobufInlineFile) {
+					return io.NopCloser(strings.NewReader(src.Content)), nil
+				}
+				return os.Open(p)
+			},
+		}),
+	}
+	files, err := compiler.Compile(context.Background(), file)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProtobufSchema, err)
+	}
+
+	m, err := protobufMessage(files[0], src.Message)
+	if err != nil {
+		return err
+	}
+	descriptors, err := protobufDescriptorSet(files[0])
+	if err != nil {
+		return err
+	}
+
+	c := protobufConverter{named: make(map[protoreflect.FullName]*Schema)}
+	c.convertMessage(m, s)
+	s.ProtobufSchema, s.ProtobufMessage = descriptors, string(m.FullName())
+
+	// Set the type generated by protoc-gen-go, if the go package is known
+	options, _ := files[0].Options().(*descriptorpb.FileOptions)
+	if goPackage := options.GetGoPackage(); goPackage != "" {
+		importPath, name, found := strings.Cut(goPackage, ";")
+		if !found {
+			name = path.Base(importPath)
+		}
+		s.ProtobufGoImport = &GoTypeImportExtension{
+			Name: GoTypeImportName(name),
+			Path: GoTypeImportPath(importPath),
+		}
+		s.ProtobufGoType = name + "." + protobufGoName(string(m.FullName()), string(files[0].Package()))
+	}
+
+	return nil
+}
+
+// protobufMessage returns the message with the given name, either full or
+// relative to the package of the file, or the first message if there is no name.
+func protobufMessage(f protoreflect.FileDescriptor, name string) (protoreflect.MessageDescriptor, error) {
+	if name == "" {
+		if f.Messages().Len() == 0 {
+			return nil, fmt.Errorf("%w: no message in %q", ErrInvalidProtobufSchema, f.Path())
+		}
+		return f.Messages().Get(0), nil
+	}
+
+	for _, fullName := range []string{name, string(f.Package()) + "." + name} {
+		if m := protobufFindMessage(f.Messages(), protoreflect.FullName(fullName)); m != nil {
+			return m, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no message %q in %q", ErrInvalidProtobufSchema, name, f.Path())
+}
+
+// protobufFindMessage looks for a message and its nested messages by full name.
+func protobufFindMessage(messages protoreflect.MessageDescriptors, name protoreflect.FullName) protoreflect.MessageDescriptor {
+	for i := 0; i < messages.Len(); i++ {
+		m := messages.Get(i)
+		if m.FullName() == name {
+			return m
+		}
+		if nested := protobufFindMessage(m.Messages(), name); nested != nil {
+			return nested
+		}
+	}
+	return nil
+}
+
+// protobufDescriptorSet returns the serialized FileDescriptorSet of a file and
+// its imports, that is used by the generated code to (un)marshal the messages.
+func protobufDescriptorSet(f protoreflect.FileDescriptor) (string, error) {
+	var set descriptorpb.FileDescriptorSet
+	added := make(map[string]bool)
+
+	var add func(f protoreflect.FileDescriptor)
+	add = func(f protoreflect.FileDescriptor) {
+		if added[f.Path()] {
+			return
+		}
+		added[f.Path()] = true
+
+		// Add the imports first, as they are needed to build the file
+		imports := f.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+
+		fd := protodesc.ToFileDescriptorProto(f)
+		fd.SourceCodeInfo = nil
+		set.File = append(set.File, fd)
+	}
+	add(f)
+
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(&set)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidProtobufSchema, err)
+	}
+	return string(b), nil
+}
+
+// protobufConverter converts protobuf messages to schemas, keeping track of
+// the converted messages and enums so they can be referenced.
+type protobufConverter struct {
+	named map[protoreflect.FullName]*Schema
+}
+
+func (c protobufConverter) convertMessage(m protoreflect.MessageDescriptor, target *Schema) {
+	c.named[m.FullName()] = target
+
+	fields := m.Fields()
+	target.Type = SchemaTypeIsObject.String()
+	target.Title = string(m.Name())
+	target.Properties = make(map[string]*Schema, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		target.Properties[string(f.Name())] = c.convertField(f)
+
+		// Fields without presence always have a value, including the ones of
+		// oneofs that are never required
+		if !f.IsList() && !f.IsMap() && (!f.HasPresence() || f.Cardinality() == protoreflect.Required) {
+			target.Required = append(target.Required, string(f.Name()))
+		}
+	}
+}
+
+func (c protobufConverter) convertField(f protoreflect.FieldDescriptor) *Schema {
+	switch {
+	case f.IsMap():
+		return &Schema{Type: SchemaTypeIsObject.String(), AdditionalProperties: c.convertType(f.MapValue())}
+	case f.IsList():
+		return &Schema{Type: SchemaTypeIsArray.String(), Items: c.convertType(f)}
+	default:
+		return c.convertType(f)
+	}
+}
+
+//nolint:cyclop // Not necessary to split the protobuf types
+func (c protobufConverter) convertType(f protoreflect.FieldDescriptor) *Schema {
+	s := &Schema{Title: f.Kind().String()}
+
+	switch f.Kind() {
+	case protoreflect.BoolKind:
+		s.Type = "boolean"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		s.Type, s.Format = SchemaTypeIsInteger.String(), "int32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		s.Type = SchemaTypeIsInteger.String()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		s.Type, s.Extensions.ExtGoType = SchemaTypeIsInteger.String(), "uint32"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		s.Type, s.Extensions.ExtGoType = SchemaTypeIsInteger.String(), "uint64"
+	case protoreflect.FloatKind:
+		s.Type, s.Format = SchemaTypeIsNumber.String(), "float"
+	case protoreflect.DoubleKind:
+		s.Type = SchemaTypeIsNumber.String()
+	case protoreflect.StringKind:
+		s.Type = SchemaTypeIsString.String()
+	case protoreflect.BytesKind:
+		s.Type, s.Format = SchemaTypeIsString.String(), "binary"
+	case protoreflect.EnumKind:
+		// Reference the enums that are already converted
+		e := f.Enum()
+		if named, ok := c.named[e.FullName()]; ok {
+			return &Schema{ReferenceTo: named, Title: named.Title}
+		}
+		c.named[e.FullName()] = s
+		s.Type, s.Title = SchemaTypeIsString.String(), string(e.Name())
+		for i := 0; i < e.Values().Len(); i++ {
+			s.Enum = append(s.Enum, string(e.Values().Get(i).Name()))
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		m := f.Message()
+		if m.FullName() == extensions.ProtobufTimestampType {
+			s.Type, s.Format, s.Title = SchemaTypeIsString.String(), "date-time", string(m.FullName())
+			break
+		}
+
+		// Reference the messages that are already converted, as they can be recursive
+		if named, ok := c.named[m.FullName()]; ok {
+			return &Schema{ReferenceTo: named, Title: named.Title}
+		}
+		c.convertMessage(m, s)
+	}
+
+	return s
+}
+
+// protobufGoName returns the name of the golang type generated by
+// protoc-gen-go for a message (e.g. 'User_Address' for 'pkg.User.Address').
+func protobufGoName(fullName, pkg string) string {
+	if pkg != "" {
+		fullName = strings.TrimPrefix(fullName, pkg+".")
+	}
+
+	parts := strings.Split(fullName, ".")
+	for i, p := range parts {
+		// Capitalize the first letter and the lowercase letters following an underscore
+		var b strings.Builder
+		for j := 0; j < len(p); j++ {
+			switch c := p[j]; {
+			case j == 0 && c >= 'a' && c <= 'z':
+				b.WriteByte(c - 'a' + 'A')
+			case c == '_' && j+1 < len(p) && p[j+1] >= 'a' && p[j+1] <= 'z':
+				b.WriteByte(p[j+1] - 'a' + 'A')
+				j++
+			default:
+				b.WriteByte(c)
+			}
+		}
+		parts[i] = b.String()
+	}
+	return strings.Join(parts, "_")
+}
@@ -1,10 +1,30 @@
 package asyncapiv3
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	"github.com/lerenn/asyncapi-codegen/pkg/asyncapi"
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
 	"github.com/lerenn/asyncapi-codegen/pkg/utils"
 )
 
+var (
+	// ErrAllOfConflict is the error returned when the schemas of an allOf can't
+	// be merged into one object.
+	ErrAllOfConflict = fmt.Errorf("%w: conflict in allOf schemas", extensions.ErrAsyncAPI)
+
+	// ErrInvalidSchemaType is the error returned when the type of a schema
+	// is not a string or a list of a type and "null".
+	ErrInvalidSchemaType = fmt.Errorf("%w: invalid schema type", extensions.ErrAsyncAPI)
+
+	// ErrUnsupportedSchemaFormat is the error returned when the format of a
+	// Multi Format Schema Object is not supported.
+	ErrUnsupportedSchemaFormat = fmt.Errorf("%w: unsupported schema format", extensions.ErrAsyncAPI)
+)
+
 // SchemaType is a structure that represents the type of a field.
 type SchemaType string
 
@@ -22,6 +42,8 @@ const (
 	SchemaTypeIsString SchemaType = "string"
 	// SchemaTypeIsInteger represents the type of an integer.
 	SchemaTypeIsInteger SchemaType = "integer"
+	// SchemaTypeIsNumber represents the type of a number.
+	SchemaTypeIsNumber SchemaType = "number"
 )
 
 // Schema is a representation of the corresponding asyncapi object filled
@@ -49,9 +71,39 @@ type Schema struct {
 
 	// --- AsyncAPI specific ---------------------------------------------------
 
-	Description string `json:"description"`
-	Format      string `json:"format"`
-	Default     any    `json:"default"`
+	Description   string               `json:"description"`
+	Format        string               `json:"format"`
+	Default       any                  `json:"default"`
+	Discriminator *SchemaDiscriminator `json:"discriminator"`
+	Nullable      bool                 `json:"nullable"`
+
+	// Forbidden is set when the schema is the boolean schema 'false', that
+	// doesn't accept any value (e.g. 'additionalProperties: false').
+	Forbidden bool `json:"-"`
+
+	// SchemaFormat is the format of the schema when it is defined with a
+	// Multi Format Schema Object, or empty otherwise.
+	SchemaFormat string `json:"-"`
+
+	// AvroSchema is the JSON representation of the original Avro schema, when
+	// the schema is converted from an Avro schema.
+	AvroSchema string `json:"-"`
+
+	// ProtobufSchema is the serialized FileDescriptorSet of the original
+	// protobuf file and its imports, when the schema is converted from a
+	// protobuf message.
+	ProtobufSchema string `json:"-"`
+
+	// ProtobufMessage is the full name of the protobuf message that the schema
+	// is converted from.
+	ProtobufMessage string `json:"-"`
+
+	// ProtobufGoType is the golang type generated by protoc-gen-go for the
+	// protobuf message, when the protobuf file has a 'go_package' option.
+	ProtobufGoType string `json:"-"`
+
+	// ProtobufGoImport is the import of the package of ProtobufGoType.
+	ProtobufGoImport *GoTypeImportExtension `json:"-"`
 
 	Reference string `json:"$ref"`
 
@@ -60,6 +112,23 @@ type Schema struct {
 	Name        string  `json:"-"`
 	ReferenceTo *Schema `json:"-"`
 
+	// TraitHeaders are the headers of the message traits from the components
+	// that have been merged into this schema, when it is the headers of a message.
+	TraitHeaders []*Schema `json:"-"`
+
+	// ZeroBounds are the bounds (e.g. 'maximum' or 'maxLength') explicitly set
+	// to zero in the specification, as the zero validations are otherwise
+	// considered as unset.
+	ZeroBounds []string `json:"-"`
+
+	// protobufSource is the protobuf file of the schema, until it is loaded
+	// when the specification is processed.
+	protobufSource *protobufSource
+
+	// allOfMerged is set when the AllOf schemas have been merged into this one,
+	// in order to merge them only once.
+	allOfMerged bool
+
 	// Embedded validation fields
 	asyncapi.Validations[Schema]
 
@@ -133,15 +202,15 @@ func (s *Schema) generateMetadata(parentName, name string, number *int, isRequir
 	}
 
 	// Generate AnyOf metadata
-	for _, v := range s.AnyOf {
-		if err := v.generateMetadata(s.Name, "Any_Of", nil, false); err != nil {
+	for i, v := range s.AnyOf {
+		if err := v.generateMetadata(s.Name, "Any_Of", &i, false); err != nil {
 			return err
 		}
 	}
 
 	// Generate OneOf metadata
-	for _, v := range s.OneOf {
-		if err := v.generateMetadata(s.Name, "One_Of", nil, false); err != nil {
+	for i, v := range s.OneOf {
+		if err := v.generateMetadata(s.Name, "One_Of", &i, false); err != nil {
 			return err
 		}
 	}
@@ -220,13 +289,25 @@ func (s *Schema) setDependencies(spec Specification) error {
 	}
 
 	// Set AnyOf dependencies
-	if err := s.setAnyOfDependenciesAndMerge(spec); err != nil {
-		return err
+	for _, v := range s.AnyOf {
+		if err := v.setDependencies(spec); err != nil {
+			return err
+		}
 	}
 
 	// Set OneOf dependencies
-	if err := s.setOneOfDependenciesAndMerge(spec); err != nil {
-		return err
+	for _, v := range s.OneOf {
+		if err := v.setDependencies(spec); err != nil {
+			return err
+		}
+	}
+
+	// Merge the variants properties if the schema is not a pure union, as it
+	// is generated as a struct with properties of its own
+	if !s.IsUnion() {
+		for _, v := range append(append([]*Schema{}, s.AnyOf...), s.OneOf...) {
+			s.mergeWithVariantProperties(*v)
+		}
 	}
 
 	// Set AllOf dependencies
@@ -243,48 +324,73 @@ func (s *Schema) setDependencies(spec Specification) error {
 }
 
 func (s *Schema) setAllOfDependenciesAndMerge(spec Specification) error {
-	for _, v := range s.AllOf {
-		if err := v.setDependencies(spec); err != nil {
-			return err
-		}
+	// Only merge once, as referenced schemas can be merged before being set
+	if s.allOfMerged {
+		return nil
+	}
+	s.allOfMerged = true
 
-		// Merge with other fields as one struct (invalidate references)
-		if err := s.MergeWith(spec, *v); err != nil {
+	// Use directly the referenced schema if it is the only one and there is
+	// nothing else to merge with (i.e. allOf is only used to document a reference)
+	if len(s.AllOf) == 1 && s.AllOf[0].Reference != "" && s.Type == "" && len(s.Properties) == 0 {
+		if err := s.AllOf[0].setDependencies(spec); err != nil {
 			return err
 		}
+		s.ReferenceTo = s.AllOf[0].ReferenceTo
+		return nil
 	}
 
-	return nil
-}
-
-func (s *Schema) setOneOfDependenciesAndMerge(spec Specification) error {
-	for _, v := range s.OneOf {
+	for i, v := range s.AllOf {
 		if err := v.setDependencies(spec); err != nil {
 			return err
 		}
 
+		// Merge the AllOf of the referenced schema first, as it may not have
+		// been done yet
+		if err := v.Follow().setAllOfDependenciesAndMerge(spec); err != nil {
+			return err
+		}
+
 		// Merge with other fields as one struct (invalidate references)
 		if err := s.MergeWith(spec, *v); err != nil {
-			return err
+			return fmt.Errorf("%w: allOf[%d] of schema %q: %w", ErrAllOfConflict, i, s.Name, err)
 		}
 	}
 
 	return nil
 }
 
-func (s *Schema) setAnyOfDependenciesAndMerge(spec Specification) error {
-	for _, v := range s.AnyOf {
-		if err := v.setDependencies(spec); err != nil {
-			return err
+// mergeWithVariantProperties adds the properties of a oneOf/anyOf variant to
+// the schema, as optional properties: only one of the variants has to match,
+// so their requirements are not merged and the first definition of a property
+// is kept.
+func (s *Schema) mergeWithVariantProperties(v Schema) {
+	variant := v.Follow()
+	if len(variant.Properties) == 0 {
+		return
+	}
+
+	// Initialize properties if they are nil
+	if s.Properties == nil {
+		s.Properties = make(map[string]*Schema)
+	}
+
+	for _, k := range utils.SortedKeys(variant.Properties) {
+		p := variant.Properties[k]
+		if _, exists := s.Properties[k]; exists {
+			continue
 		}
 
-		// Merge with other fields as one struct (invalidate references)
-		if err := s.MergeWith(spec, *v); err != nil {
-			return err
+		// Reference the types of a referenced variant, as they are generated
+		// with it, or copy the property to make it optional
+		if v.ReferenceTo != nil && (p.Type == "object" || p.IsEnum() || p.IsUnion()) {
+			s.Properties[k] = &Schema{ReferenceTo: p}
+		} else {
+			cp := *p
+			cp.IsRequired = false
+			s.Properties[k] = &cp
 		}
 	}
-
-	return nil
 }
 
 func (s *Schema) setReference(spec Specification) error {
@@ -303,6 +409,155 @@ func (s *Schema) setReference(spec Specification) error {
 	return nil
 }
 
+// UnmarshalJSON unmarshals the schema, accepting the type as a list of a
+// type and "null" (e.g. ["string", "null"]) for nullable schemas, and the
+// boolean schemas 'true' (any value) and 'false' (no value).
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	switch string(bytes.TrimSpace(data)) {
+	case "true":
+		*s = Schema{}
+		return nil
+	case "false":
+		*s = Schema{Forbidden: true}
+		return nil
+	}
+
+	// Check if the schema is a Multi Format Schema Object
+	var multiFormat struct {
+		SchemaFormat *string         `json:"schemaFormat"`
+		Schema       json.RawMessage `json:"schema"`
+	}
+	if err := json.Unmarshal(data, &multiFormat); err == nil &&
+		multiFormat.SchemaFormat != nil && multiFormat.Schema != nil {
+		return s.unmarshalMultiFormat(*multiFormat.SchemaFormat, multiFormat.Schema)
+	}
+
+	type alias Schema
+	a := struct {
+		*alias
+		Type any `json:"type"`
+	}{alias: (*alias)(s)}
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	if err := s.setZeroBounds(data); err != nil {
+		return err
+	}
+
+	switch t := a.Type.(type) {
+	case nil:
+		return nil
+	case string:
+		s.Type = t
+		return nil
+	case []any:
+		for _, v := range t {
+			str, ok := v.(string)
+			switch {
+			case !ok || (str != "null" && s.Type != ""):
+				return fmt.Errorf("%w: %v", ErrInvalidSchemaType, t)
+			case str == "null":
+				s.Nullable = true
+			default:
+				s.Type = str
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %v", ErrInvalidSchemaType, t)
+	}
+}
+
+// setZeroBounds sets the bounds of the schema that are explicitly set to zero.
+func (s *Schema) setZeroBounds(data []byte) error {
+	var bounds map[string]any
+	if err := json.Unmarshal(data, &bounds); err != nil {
+		return err
+	}
+
+	for _, k := range []string{"maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum", "maxLength", "maxItems"} {
+		if v, ok := bounds[k].(float64); ok && v == 0 {
+			s.ZeroBounds = append(s.ZeroBounds, k)
+		}
+	}
+
+	return nil
+}
+
+// unmarshalMultiFormat unmarshals the schema of a Multi Format Schema Object
+// with the given format.
+func (s *Schema) unmarshalMultiFormat(format string, data []byte) error {
+	switch {
+	case IsAvroSchemaFormat(format):
+		if err := s.setFromAvro(data); err != nil {
+			return err
+		}
+	case IsProtobufSchemaFormat(format):
+		if err := s.setFromProtobuf(data); err != nil {
+			return err
+		}
+	case format == "" || strings.HasPrefix(format, "application/vnd.aai.asyncapi") ||
+		strings.HasPrefix(format, "application/schema+json") || strings.HasPrefix(format, "application/schema+yaml"):
+		if err := json.Unmarshal(data, s); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedSchemaFormat, format)
+	}
+
+	s.SchemaFormat = format
+	return nil
+}
+
+// IsNullable checks if the schema, or the referenced schema, accepts null values.
+func (s Schema) IsNullable() bool {
+	return s.Nullable || (s.ReferenceTo != nil && s.ReferenceTo.IsNullable())
+}
+
+// IsAny checks if the schema accepts any value, i.e. if it is an empty
+// schema (or the boolean schema 'true').
+func (s Schema) IsAny() bool {
+	return s.Type == "" && s.Reference == "" && s.ReferenceTo == nil && !s.Forbidden && !s.IsUnion() &&
+		len(s.Properties) == 0 && len(s.PatternProperties) == 0 && s.AdditionalProperties == nil &&
+		len(s.AllOf) == 0 && len(s.Enum) == 0
+}
+
+// HasAdditionalProperties checks if the schema accepts additional properties
+// that are explicitly described, i.e. if 'additionalProperties' is set and
+// is not 'false'.
+func (s Schema) HasAdditionalProperties() bool {
+	return s.AdditionalProperties != nil && !s.AdditionalProperties.Forbidden
+}
+
+// ForbidsAdditionalProperties checks if the schema rejects additional
+// properties, i.e. if 'additionalProperties' is 'false'.
+func (s Schema) ForbidsAdditionalProperties() bool {
+	return s.AdditionalProperties != nil && s.AdditionalProperties.Forbidden
+}
+
+// IsUnion checks if the schema is a union of other schemas, i.e. a schema
+// with oneOf or anyOf and without properties of its own.
+func (s Schema) IsUnion() bool {
+	return (len(s.OneOf) > 0 || len(s.AnyOf) > 0) && len(s.Properties) == 0
+}
+
+// IsEnum checks if the schema is an enumeration of scalar values, i.e. a
+// string, integer or number schema with enum values and without custom type.
+func (s Schema) IsEnum() bool {
+	if len(s.Enum) == 0 || s.ExtGoType != "" {
+		return false
+	}
+
+	switch s.Type {
+	case SchemaTypeIsString.String():
+		return s.Format != "date" && s.Format != "date-time"
+	case SchemaTypeIsInteger.String(), SchemaTypeIsNumber.String():
+		return true
+	default:
+		return false
+	}
+}
+
 // IsFieldRequired checks if a field is required in the asyncapi struct.
 func (s Schema) IsFieldRequired(field string) bool {
 	return utils.IsInSlice(s.Required, field)
@@ -323,45 +578,32 @@ func (s *Schema) MergeWith(spec Specification, s2 Schema) error {
 		return nil
 	}
 
+	// Check that the schemas are both objects
+	if t := s2.Follow().Type; t != "" && t != SchemaTypeIsObject.String() {
+		return fmt.Errorf("cannot merge an object with a schema of type %q", t)
+	}
+	if s.Type != "" && s.Type != SchemaTypeIsObject.String() {
+		return fmt.Errorf("cannot merge a schema of type %q with an object", s.Type)
+	}
 	s.Type = SchemaTypeIsObject.String()
 
 	// Merge with other fields
-	s.mergeWithSchemaAllOf(s2)
 	s.mergeWithSchemaAnyOf(s2)
 	s.mergeWithSchemaOneOf(s2)
-	s.mergeWithSchemaProperties(s2)
+	if err := s.mergeWithSchemaProperties(s2); err != nil {
+		return err
+	}
 
 	// Merge requirements
 	s.Required = append(s.Required, s2.Required...)
+	if s2.ReferenceTo != nil {
+		s.Required = append(s.Required, s2.ReferenceTo.Required...)
+	}
 	s.Required = utils.RemoveDuplicateFromSlice(s.Required)
 
 	return nil
 }
 
-func (s *Schema) mergeWithSchemaAllOf(s2 Schema) {
-	// Return if there are no AllOf to merge
-	if s2.AllOf == nil && (s2.ReferenceTo == nil || s2.ReferenceTo.AllOf == nil) {
-		return
-	}
-
-	// Initialize AllOf if they are nil
-	if s.AllOf == nil {
-		s.AllOf = make([]*Schema, 0)
-	}
-
-	// Add AllOf from s2 to s
-	if s2.AllOf != nil {
-		s.AllOf = append(s.AllOf, s2.AllOf...)
-	}
-
-	// Add AllOf from s2 reference to s
-	if s2.ReferenceTo != nil && s2.ReferenceTo.AllOf != nil {
-		for _, v := range s2.ReferenceTo.AllOf {
-			s.AllOf = append(s.AllOf, &Schema{ReferenceTo: v})
-		}
-	}
-}
-
 func (s *Schema) mergeWithSchemaAnyOf(s2 Schema) {
 	// Return if there are no AnyOf to merge
 	if s2.AnyOf == nil && (s2.ReferenceTo == nil || s2.ReferenceTo.AnyOf == nil) {
@@ -410,10 +652,10 @@ func (s *Schema) mergeWithSchemaOneOf(s2 Schema) {
 	}
 }
 
-func (s *Schema) mergeWithSchemaProperties(s2 Schema) {
+func (s *Schema) mergeWithSchemaProperties(s2 Schema) error {
 	// Return if there are no properties to merge
 	if s2.Properties == nil && (s2.ReferenceTo == nil || s2.ReferenceTo.Properties == nil) {
-		return
+		return nil
 	}
 
 	// Initialize properties if they are nil
@@ -422,9 +664,12 @@ func (s *Schema) mergeWithSchemaProperties(s2 Schema) {
 	}
 
 	// Add properties from s2 to s
-	for k, v := range s2.Properties {
-		_, exists := s.Properties[k]
-		if exists {
+	for _, k := range utils.SortedKeys(s2.Properties) {
+		v := s2.Properties[k]
+		if p, exists := s.Properties[k]; exists {
+			if !p.isCompatibleWith(v) {
+				return fmt.Errorf("property %q is defined with different types", k)
+			}
 			continue
 		}
 
@@ -432,25 +677,29 @@ func (s *Schema) mergeWithSchemaProperties(s2 Schema) {
 	}
 
 	// Add properties from s2 reference to s
-	s.mergeWithSchemaReferenceProperties(s2)
+	return s.mergeWithSchemaReferenceProperties(s2)
 }
 
-func (s *Schema) mergeWithSchemaReferenceProperties(s2 Schema) {
+func (s *Schema) mergeWithSchemaReferenceProperties(s2 Schema) error {
 	// Return if there are no properties to merge
 	if s2.ReferenceTo == nil || s2.ReferenceTo.Properties == nil {
-		return
+		return nil
 	}
 
 	// Add properties from s2 reference to s
-	for k, v := range s2.ReferenceTo.Properties {
-		// Skip if the property already exists
-		_, exists := s.Properties[k]
-		if exists {
+	for _, k := range utils.SortedKeys(s2.ReferenceTo.Properties) {
+		v := s2.ReferenceTo.Properties[k]
+
+		// Skip if the property already exists and is compatible
+		if p, exists := s.Properties[k]; exists {
+			if !p.isCompatibleWith(v) {
+				return fmt.Errorf("property %q is defined with different types", k)
+			}
 			continue
 		}
 
 		// Add the property
-		if v.Type == "object" {
+		if v.Type == "object" || v.IsEnum() || v.IsUnion() {
 			s.Properties[k] = &Schema{
 				Validations: asyncapi.Validations[Schema]{
 					IsRequired: v.IsRequired,
@@ -466,6 +715,27 @@ func (s *Schema) mergeWithSchemaReferenceProperties(s2 Schema) {
 			s.Required = append(s.Required, k)
 		}
 	}
+
+	return nil
+}
+
+// isCompatibleWith checks if two schemas can be used for the same property
+// when merging schemas, i.e. if they would generate the same type.
+func (s *Schema) isCompatibleWith(s2 *Schema) bool {
+	a, b := s.Follow(), s2.Follow()
+	switch {
+	case a == b:
+		return true
+	case a.Type != b.Type || a.Format != b.Format || a.ExtGoType != b.ExtGoType:
+		return false
+	case a.Type == SchemaTypeIsArray.String():
+		return a.Items == nil || b.Items == nil || a.Items.isCompatibleWith(b.Items)
+	case a.Type == SchemaTypeIsObject.String(), a.IsUnion(), b.IsUnion():
+		// Different objects or unions generate different types
+		return false
+	default:
+		return true
+	}
 }
 
 // Follow returns referenced schema if specified or the actual schema.
@@ -0,0 +1,34 @@
+package asyncapiv3
+
+import "encoding/json"
+
+// SchemaDiscriminator is a representation of the discriminator of a schema,
+// used to know which schema of a oneOf/anyOf is used by a value.
+//
+// It can be specified as a property name (AsyncAPI style) or as an object with
+// a property name and an optional mapping between values and schemas (OpenAPI
+// style).
+type SchemaDiscriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping"`
+}
+
+// UnmarshalJSON unmarshals the discriminator from its string or object form.
+func (d *SchemaDiscriminator) UnmarshalJSON(data []byte) error {
+	// Try the property name only form
+	var propertyName string
+	if err := json.Unmarshal(data, &propertyName); err == nil {
+		*d = SchemaDiscriminator{PropertyName: propertyName}
+		return nil
+	}
+
+	// Use the object form
+	type alias SchemaDiscriminator
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*d = SchemaDiscriminator(a)
+
+	return nil
+}
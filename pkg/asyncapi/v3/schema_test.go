@@ -0,0 +1,316 @@
+package asyncapiv3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/asyncapi"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSchemaSuite(t *testing.T) {
+	suite.Run(t, new(SchemaSuite))
+}
+
+type SchemaSuite struct {
+	suite.Suite
+}
+
+func (suite *SchemaSuite) TestAllOfMergeWithNestedReferences() {
+	spec := Specification{
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Admin": {AllOf: []*Schema{
+					{Reference: "#/components/schemas/User"},
+					{Type: "object", Properties: map[string]*Schema{"role": {Type: "string"}}},
+				}},
+				"User": {AllOf: []*Schema{
+					{Reference: "#/components/schemas/Entity"},
+					{
+						Type:        "object",
+						Properties:  map[string]*Schema{"name": {Type: "string"}},
+						Validations: asyncapi.Validations[Schema]{Required: []string{"name"}},
+					},
+				}},
+				"Entity": {
+					Type:        "object",
+					Properties:  map[string]*Schema{"id": {Type: "string"}},
+					Validations: asyncapi.Validations[Schema]{Required: []string{"id"}},
+				},
+			},
+		},
+	}
+	suite.Require().NoError(spec.Process())
+
+	admin := spec.Components.Schemas["Admin"]
+	suite.Require().Equal("object", admin.Type)
+	suite.Require().Len(admin.Properties, 3)
+	suite.Require().Contains(admin.Properties, "id")
+	suite.Require().Contains(admin.Properties, "name")
+	suite.Require().Contains(admin.Properties, "role")
+	suite.Require().ElementsMatch([]string{"id", "name"}, admin.Required)
+}
+
+func (suite *SchemaSuite) TestAllOfConflict() {
+	spec := Specification{
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Merged": {AllOf: []*Schema{
+					{Type: "object", Properties: map[string]*Schema{"id": {Type: "string"}}},
+					{Type: "object", Properties: map[string]*Schema{"id": {Type: "integer"}}},
+				}},
+			},
+		},
+	}
+	suite.Require().ErrorIs(spec.Process(), ErrAllOfConflict)
+}
+
+func (suite *SchemaSuite) TestAllOfCompatibleProperties() {
+	spec := Specification{
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Merged": {AllOf: []*Schema{
+					{Type: "object", Properties: map[string]*Schema{"id": {Type: "string"}}},
+					{Type: "object", Properties: map[string]*Schema{"id": {Type: "string"}}},
+				}},
+			},
+		},
+	}
+	suite.Require().NoError(spec.Process())
+	suite.Require().Len(spec.Components.Schemas["Merged"].Properties, 1)
+}
+
+func (suite *SchemaSuite) TestAllOfWithNonObject() {
+	spec := Specification{
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Merged": {AllOf: []*Schema{
+					{Type: "object", Properties: map[string]*Schema{"id": {Type: "string"}}},
+					{Type: "string"},
+				}},
+			},
+		},
+	}
+	suite.Require().ErrorIs(spec.Process(), ErrAllOfConflict)
+}
+
+func (suite *SchemaSuite) TestAllOfWithSingleReference() {
+	spec := Specification{
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Status": {Type: "string"},
+				"Documented": {
+					Description: "A documented status",
+					AllOf:       []*Schema{{Reference: "#/components/schemas/Status"}},
+				},
+			},
+		},
+	}
+	suite.Require().NoError(spec.Process())
+	suite.Require().Equal(spec.Components.Schemas["Status"], spec.Components.Schemas["Documented"].Follow())
+}
+
+func (suite *SchemaSuite) TestOneOfWithPropertiesMergesVariants() {
+	spec := Specification{
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Mixed": {
+					Type:        "object",
+					Properties:  map[string]*Schema{"id": {Type: "string"}},
+					Validations: asyncapi.Validations[Schema]{Required: []string{"id"}},
+					OneOf: []*Schema{
+						{
+							Type:        "object",
+							Properties:  map[string]*Schema{"a": {Type: "string"}},
+							Validations: asyncapi.Validations[Schema]{Required: []string{"a"}},
+						},
+						{Reference: "#/components/schemas/B"},
+					},
+				},
+				"B": {
+					Type:        "object",
+					Properties:  map[string]*Schema{"b": {Type: "integer"}, "id": {Type: "integer"}},
+					Validations: asyncapi.Validations[Schema]{Required: []string{"b"}},
+				},
+			},
+		},
+	}
+	suite.Require().NoError(spec.Process())
+
+	// The variants properties are kept next to the schema own properties, as
+	// optional properties, the first definition winning
+	mixed := spec.Components.Schemas["Mixed"]
+	suite.Require().False(mixed.IsUnion())
+	suite.Require().Len(mixed.Properties, 3)
+	suite.Require().Equal("string", mixed.Properties["a"].Type)
+	suite.Require().Equal("integer", mixed.Properties["b"].Type)
+	suite.Require().Equal("string", mixed.Properties["id"].Type)
+	suite.Require().Equal([]string{"id"}, mixed.Required)
+
+	// The pure unions are not merged
+	union := Specification{
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Union": {OneOf: []*Schema{{Reference: "#/components/schemas/B"}}},
+				"B":     {Type: "object", Properties: map[string]*Schema{"b": {Type: "integer"}}},
+			},
+		},
+	}
+	suite.Require().NoError(union.Process())
+	suite.Require().True(union.Components.Schemas["Union"].IsUnion())
+	suite.Require().Empty(union.Components.Schemas["Union"].Properties)
+}
+
+func (suite *SchemaSuite) TestUnmarshalNullableType() {
+	var s Schema
+	suite.Require().NoError(json.Unmarshal([]byte(`{"type": ["string", "null"]}`), &s))
+	suite.Require().Equal("string", s.Type)
+	suite.Require().True(s.IsNullable())
+
+	s = Schema{}
+	suite.Require().NoError(json.Unmarshal([]byte(`{"type": "integer", "nullable": true}`), &s))
+	suite.Require().Equal("integer", s.Type)
+	suite.Require().True(s.IsNullable())
+
+	s = Schema{}
+	suite.Require().NoError(json.Unmarshal([]byte(`{"type": "string"}`), &s))
+	suite.Require().False(s.IsNullable())
+	suite.Require().True(Schema{ReferenceTo: &Schema{Nullable: true}}.IsNullable())
+
+	suite.Require().ErrorIs(json.Unmarshal([]byte(`{"type": ["string", "integer"]}`), &s), ErrInvalidSchemaType)
+	suite.Require().ErrorIs(json.Unmarshal([]byte(`{"type": 42}`), &s), ErrInvalidSchemaType)
+}
+
+func (suite *SchemaSuite) TestUnmarshalZeroBounds() {
+	var s Schema
+	suite.Require().NoError(json.Unmarshal([]byte(`{
+		"type": "integer",
+		"minimum": -5,
+		"maximum": 0,
+		"exclusiveMinimum": 0.0,
+		"maxItems": 2
+	}`), &s))
+	suite.Require().Equal([]string{"maximum", "exclusiveMinimum"}, s.ZeroBounds)
+
+	s = Schema{}
+	suite.Require().NoError(json.Unmarshal([]byte(`{"type": "string", "maxLength": 8}`), &s))
+	suite.Require().Empty(s.ZeroBounds)
+}
+
+func (suite *SchemaSuite) TestUnmarshalBooleanSchemas() {
+	var s Schema
+	suite.Require().NoError(json.Unmarshal([]byte(`{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {"any": true}
+	}`), &s))
+	suite.Require().True(s.ForbidsAdditionalProperties())
+	suite.Require().False(s.HasAdditionalProperties())
+	suite.Require().True(s.Properties["any"].IsAny())
+
+	s = Schema{}
+	suite.Require().NoError(json.Unmarshal([]byte(`{"type": "object", "additionalProperties": {}}`), &s))
+	suite.Require().False(s.ForbidsAdditionalProperties())
+	suite.Require().True(s.HasAdditionalProperties())
+	suite.Require().True(s.AdditionalProperties.IsAny())
+	suite.Require().False(s.IsAny())
+}
+
+func (suite *SchemaSuite) TestUnmarshalMultiFormatSchema() {
+	var s Schema
+	suite.Require().NoError(json.Unmarshal([]byte(`{
+		"schemaFormat": "application/vnd.aai.asyncapi+json;version=3.0.0",
+		"schema": {"type": "string"}
+	}`), &s))
+	suite.Require().Equal("string", s.Type)
+	suite.Require().False(s.IsAvro())
+
+	s = Schema{}
+	suite.Require().ErrorIs(json.Unmarshal([]byte(`{
+		"schemaFormat": "application/raml+yaml;version=1.0",
+		"schema": {"type": "string"}
+	}`), &s), ErrUnsupportedSchemaFormat)
+}
+
+func (suite *SchemaSuite) TestUnmarshalAvroSchema() {
+	var s Schema
+	suite.Require().NoError(json.Unmarshal([]byte(`{
+		"schemaFormat": "application/vnd.apache.avro;version=1.9.0",
+		"schema": {"type": "record", "name": "User", "namespace": "com.example", "fields": [
+			{"name": "id", "type": {"type": "string", "logicalType": "uuid"}},
+			{"name": "age", "type": "int", "default": 18},
+			{"name": "email", "type": ["null", "string"]},
+			{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["ACTIVE", "BANNED"]}},
+			{"name": "contact", "type": ["null", "string", "long"]},
+			{"name": "friend", "type": ["null", "com.example.User"]}
+		]}
+	}`), &s))
+	suite.Require().True(s.IsAvro())
+	suite.Require().Equal(SchemaTypeIsObject.String(), s.Type)
+	suite.Require().Equal("User", s.Title)
+	suite.Require().ElementsMatch([]string{"id", "age", "status"}, s.Required)
+
+	suite.Require().Equal("uuid", s.Properties["id"].Format)
+	suite.Require().Equal("int32", s.Properties["age"].Format)
+	suite.Require().Equal(float64(18), s.Properties["age"].Default)
+	suite.Require().True(s.Properties["email"].IsNullable())
+	suite.Require().Equal([]any{"ACTIVE", "BANNED"}, s.Properties["status"].Enum)
+	suite.Require().Len(s.Properties["contact"].OneOf, 2)
+	suite.Require().Same(&s, s.Properties["friend"].ReferenceTo)
+
+	s = Schema{}
+	suite.Require().ErrorIs(json.Unmarshal([]byte(`{
+		"schemaFormat": "application/vnd.apache.avro",
+		"schema": {"type": "record", "name": "User", "fields": [{"name": "a", "type": "Unknown"}]}
+	}`), &s), ErrInvalidAvroSchema)
+}
+
+func (suite *SchemaSuite) TestUnmarshalProtobufSchema() {
+	source := `syntax = "proto3";
+package example;
+option go_package = "example.com/pb;userpb";
+message User {
+	enum Status { UNKNOWN = 0; ACTIVE = 1; }
+	string id = 1;
+	optional string email = 2;
+	Status status = 3;
+	repeated uint64 ids = 4;
+	map<string, int32> scores = 5;
+	User friend = 6;
+}`
+
+	data, err := json.Marshal(map[string]any{
+		"schemaFormat": "application/vnd.google.protobuf;version=3",
+		"schema":       source,
+	})
+	suite.Require().NoError(err)
+
+	var s Schema
+	suite.Require().NoError(json.Unmarshal(data, &s))
+	suite.Require().False(s.IsProtobuf())
+	suite.Require().NoError(s.loadProtobuf("."))
+	suite.Require().True(s.IsProtobuf())
+	suite.Require().Equal("example.User", s.ProtobufMessage)
+	suite.Require().Equal("userpb.User", s.ProtobufGoType)
+	suite.Require().Equal(GoTypeImportPath("example.com/pb"), s.ProtobufGoImport.Path)
+	suite.Require().ElementsMatch([]string{"id", "status"}, s.Required)
+
+	suite.Require().Equal(SchemaTypeIsString.String(), s.Properties["email"].Type)
+	suite.Require().Equal([]any{"UNKNOWN", "ACTIVE"}, s.Properties["status"].Enum)
+	suite.Require().Equal("uint64", s.Properties["ids"].Items.ExtGoType)
+	suite.Require().Equal("int32", s.Properties["scores"].AdditionalProperties.Format)
+	suite.Require().Same(&s, s.Properties["friend"].ReferenceTo)
+
+	s = Schema{}
+	suite.Require().NoError(json.Unmarshal([]byte(`{
+		"schemaFormat": "application/vnd.google.protobuf",
+		"schema": "message User { Unknown a = 1; }"
+	}`), &s))
+	suite.Require().ErrorIs(s.loadProtobuf("."), ErrInvalidProtobufSchema)
+
+	s = Schema{}
+	suite.Require().ErrorIs(json.Unmarshal([]byte(`{
+		"schemaFormat": "application/vnd.google.protobuf",
+		"schema": 42
+	}`), &s), ErrInvalidProtobufSchema)
+}
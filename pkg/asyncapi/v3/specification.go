@@ -39,6 +39,10 @@ type Specification struct {
 	// specificationReferenced is a map of all the outside specifications that
 	// are referenced in this specification.
 	dependencies map[string]*Specification
+
+	// path is the path of the specification file, if any, used to resolve
+	// the files referenced by the specification.
+	path string
 }
 
 // NewSpecification creates a new Specification struct.
@@ -52,6 +56,10 @@ func NewSpecification() *Specification {
 
 // Process processes the Specification to make it ready for code generation.
 func (s *Specification) Process() error {
+	if err := s.loadProtobufSchemas(); err != nil {
+		return err
+	}
+
 	if err := s.generateMetadata(); err != nil {
 		return err
 	}
@@ -59,6 +67,13 @@ func (s *Specification) Process() error {
 	return s.setDependencies()
 }
 
+// SetPath sets the path of the specification file, used to resolve the files
+// referenced by the specification. They are resolved relatively to the working
+// directory otherwise.
+func (s *Specification) SetPath(path string) {
+	s.path = path
+}
+
 // AddDependency adds a specification dependency to the Specification.
 func (s *Specification) AddDependency(path string, spec asyncapi.Specification) error {
 	// Cast to Specification v3
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"runtime/debug"
+	"strings"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/asyncapi"
 	"github.com/lerenn/asyncapi-codegen/pkg/asyncapi/parser"
@@ -91,28 +92,217 @@ func (cg CodeGen) Generate(opt options.Options) error {
 	}
 
 	if opt.IgnoreStringFormat {
-		template.DisableDateOrTimeGeneration()
+		if err := template.DisableStringFormats(template.StringFormats...); err != nil {
+			return err
+		}
+	}
+	if err := template.DisableStringFormats(opt.IgnoreStringFormats...); err != nil {
+		return err
 	}
 	if opt.ForcePointers {
 		templatesv2.ForcePointerOnFields()
 		templatesv3.ForcePointerOnFields()
 	}
 
+	if opt.AllowUnknownEnums && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("unknown enum values are only supported with AsyncAPI v3")
+	}
+	if opt.AllowUnknownEnums {
+		templatesv3.AllowUnknownEnumValues()
+	}
+
+	if opt.StrictAdditionalProperties && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("strict additional properties are only supported with AsyncAPI v3")
+	}
+	if opt.StrictAdditionalProperties {
+		templatesv3.RejectAdditionalProperties()
+	}
+
+	if opt.PlainMaps && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("plain maps are only supported with AsyncAPI v3")
+	}
+	if opt.PlainMaps {
+		templatesv3.UsePlainMaps()
+	}
+
+	if opt.EscapeChannelParameters && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("channel parameters escaping is only supported with AsyncAPI v3")
+	}
+	if opt.EscapeChannelParameters {
+		templatesv3.UseChannelParametersEscaping()
+	}
+
+	if opt.AvroConfluent && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("Avro Confluent wire format is only supported with AsyncAPI v3")
+	}
+	if opt.AvroConfluent {
+		templatesv3.UseAvroConfluentWireFormat()
+	}
+
+	if opt.ProtobufGoTypes && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("protobuf Go types are only supported with AsyncAPI v3")
+	}
+	if opt.ProtobufGoTypes {
+		templatesv3.UseProtobufGoTypes()
+	}
+
+	if opt.TraitMixins && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("trait mixins are only supported with AsyncAPI v3")
+	}
+	if opt.TraitMixins {
+		templatesv3.UseTraitMixins()
+	}
+
+	if opt.RandomMessages && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("random messages are only supported with AsyncAPI v3")
+	}
+	if opt.RandomMessages {
+		templatesv3.UseRandomMessages()
+	}
+
+	if opt.FakeControllers && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("fake controllers are only supported with AsyncAPI v3")
+	}
+	if opt.FakeControllers {
+		templatesv3.UseFakeControllers()
+	}
+
+	if opt.SplitFiles && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("split files are only supported with AsyncAPI v3")
+	}
+
+	if opt.TypesPackage != "" && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("types package is only supported with AsyncAPI v3")
+	}
+	if opt.TypesPackage != "" && opt.Generate.Types && (opt.Generate.Application || opt.Generate.User) {
+		return fmt.Errorf("the types package should be generated without the application and user code")
+	}
+	if err := templatesv3.SetTypesPackage(opt.TypesPackage); err != nil {
+		return err
+	}
+
+	if opt.UseNullable && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("nullable wrapper is only supported with AsyncAPI v3")
+	}
+	if opt.UseNullable {
+		templatesv3.UseNullableWrapper()
+	}
+
+	if opt.CloudEvents != "" && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("CloudEvents are only supported with AsyncAPI v3")
+	}
+	if err := templatesv3.SetCloudEventsMode(opt.CloudEvents); err != nil {
+		return err
+	}
+
+	if opt.Examples && cg.Specification.MajorVersion() != 3 {
+		return fmt.Errorf("examples are only supported with AsyncAPI v3")
+	}
+	if opt.Examples && !opt.Generate.Types {
+		return fmt.Errorf("examples can only be generated with the types")
+	}
+
 	// Process Specification
 	if err := cg.Specification.Process(); err != nil {
 		return err
 	}
 
-	// Generate content
-	content, err := cg.generateContent(opt)
+	// Generate content, in one or several files
+	if opt.SplitFiles {
+		if err := cg.generateFiles(opt); err != nil {
+			return err
+		}
+	} else {
+		content, err := cg.generateContent(opt)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(opt.OutputPath, content, opt); err != nil {
+			return err
+		}
+	}
+
+	// Generate examples and their test, if enabled
+	if opt.Examples {
+		return cg.generateExamples(opt)
+	}
+
+	return nil
+}
+
+func (cg CodeGen) generateExamples(opt options.Options) error {
+	spec, err := asyncapiv3.FromUnknownVersion(cg.Specification)
 	if err != nil {
 		return err
 	}
 
-	// Format content if not disabled
-	var fileContent []byte
+	code, test, err := generatorv3.Generator{
+		Specification: *spec,
+		Options:       opt,
+		ModulePath:    cg.modulePath,
+		ModuleVersion: cg.moduleVersion,
+	}.GenerateExamples()
+	if err != nil {
+		return err
+	}
+
+	codePath, testPath := ExamplesOutputPaths(opt.OutputPath)
+	if err := writeFile(codePath, code, opt); err != nil {
+		return err
+	}
+	return writeFile(testPath, test, opt)
+}
+
+func (cg CodeGen) generateFiles(opt options.Options) error {
+	spec, err := asyncapiv3.FromUnknownVersion(cg.Specification)
+	if err != nil {
+		return err
+	}
+
+	files, err := generatorv3.Generator{
+		Specification: *spec,
+		Options:       opt,
+		ModulePath:    cg.modulePath,
+		ModuleVersion: cg.moduleVersion,
+	}.GenerateFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if err := writeFile(SplitOutputPath(opt.OutputPath, f.Name), f.Content, opt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SplitOutputPath returns the path of a part of the generated code when it is
+// split into several files, next to the output path (e.g. 'asyncapi_app.gen.go'
+// for the part 'app' and 'asyncapi.gen.go').
+func SplitOutputPath(outputPath, part string) string {
+	return outputBase(outputPath) + "_" + part + ".gen.go"
+}
+
+func outputBase(outputPath string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(outputPath, ".go"), ".gen")
+}
+
+// ExamplesOutputPaths returns the paths of the generated examples and of their
+// test, next to the generated code (e.g. 'asyncapi_examples.gen.go' and
+// 'asyncapi_examples_test.go' for 'asyncapi.gen.go').
+func ExamplesOutputPaths(outputPath string) (code, test string) {
+	base := outputBase(outputPath)
+	return base + "_examples.gen.go", base + "_examples_test.go"
+}
+
+// writeFile writes the generated content to a file, formatting it if not disabled.
+func writeFile(path, content string, opt options.Options) error {
+	fileContent := []byte(content)
 	if !opt.DisableFormatting {
-		fileContent, err = imports.Process("", []byte(content), &imports.Options{
+		var err error
+		fileContent, err = imports.Process("", fileContent, &imports.Options{
 			TabWidth:  8,
 			TabIndent: true,
 			Comments:  true,
@@ -121,12 +311,9 @@ func (cg CodeGen) Generate(opt options.Options) error {
 		if err != nil {
 			return err
 		}
-	} else {
-		fileContent = []byte(content)
 	}
 
-	// Write to file
-	return os.WriteFile(opt.OutputPath, fileContent, 0644)
+	return os.WriteFile(path, fileContent, 0644)
 }
 
 func (cg CodeGen) generateContent(opt options.Options) (string, error) {
@@ -0,0 +1,45 @@
+package codegen
+
+import (
+	"testing"
+
+	asyncapiv2 "github.com/lerenn/asyncapi-codegen/pkg/asyncapi/v2"
+	"github.com/lerenn/asyncapi-codegen/pkg/codegen/options"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCodeGenSuite(t *testing.T) {
+	suite.Run(t, new(CodeGenSuite))
+}
+
+type CodeGenSuite struct {
+	suite.Suite
+}
+
+func (suite *CodeGenSuite) TestV3OnlyOptionsOnV2() {
+	cases := map[string]options.Options{
+		"random messages":              {RandomMessages: true},
+		"fake controllers":             {FakeControllers: true},
+		"split files":                  {SplitFiles: true},
+		"types package":                {TypesPackage: "github.com/example/types"},
+		"nullable":                     {UseNullable: true},
+		"cloudevents":                  {CloudEvents: "binary"},
+		"examples":                     {Examples: true},
+		"allow unknown enums":          {AllowUnknownEnums: true},
+		"strict additional properties": {StrictAdditionalProperties: true},
+		"plain maps":                   {PlainMaps: true},
+		"escape channel parameters":    {EscapeChannelParameters: true},
+		"avro confluent":               {AvroConfluent: true},
+		"protobuf go types":            {ProtobufGoTypes: true},
+		"trait mixins":                 {TraitMixins: true},
+	}
+
+	cg, err := New(asyncapiv2.NewSpecification())
+	suite.Require().NoError(err)
+
+	for name, opt := range cases {
+		opt.ConvertKeys, opt.NamingScheme = "none", "none"
+		err := cg.Generate(opt)
+		suite.Require().ErrorContains(err, "only supported with AsyncAPI v3", name)
+	}
+}
@@ -76,6 +76,7 @@ func isControllerSendOperation(side generators.Side, op *asyncapi.Operation) boo
 func (asg ControllerGenerator) Generate() (string, error) {
 	tmplt, err := loadTemplate(
 		controllerTemplatePath,
+		fakeTemplatePath,
 		schemaDefinitionTemplatePath,
 		schemaNameTemplatePath,
 		messageTemplatePath,
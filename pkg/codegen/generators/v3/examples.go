@@ -0,0 +1,70 @@
+package generatorv3
+
+import (
+	"bytes"
+	"sort"
+
+	asyncapi "github.com/lerenn/asyncapi-codegen/pkg/asyncapi/v3"
+)
+
+// ExamplesGenerator is a code generator for the examples of the messages from
+// an asyncapi specification, that will generate their golang values and a test
+// checking them against the generated types.
+type ExamplesGenerator struct {
+	Messages []*asyncapi.Message
+}
+
+// NewExamplesGenerator will create a new examples code generator.
+func NewExamplesGenerator(spec asyncapi.Specification) ExamplesGenerator {
+	var gen ExamplesGenerator
+
+	// Get the messages with examples, where their type is generated
+	addMessage := func(msg *asyncapi.Message) {
+		if msg.Reference == "" && len(msg.Examples) > 0 {
+			gen.Messages = append(gen.Messages, msg)
+		}
+	}
+	for _, ch := range spec.Channels {
+		for _, msg := range ch.Messages {
+			addMessage(msg)
+		}
+	}
+	for _, msg := range spec.Components.Messages {
+		addMessage(msg)
+	}
+
+	sort.Slice(gen.Messages, func(i, j int) bool {
+		return gen.Messages[i].Name < gen.Messages[j].Name
+	})
+
+	return gen
+}
+
+// Generate will generate the examples code, and the code of their test.
+func (eg ExamplesGenerator) Generate() (code, test string, err error) {
+	code, err = eg.execute(examplesTemplatePath)
+	if err != nil {
+		return "", "", err
+	}
+
+	test, err = eg.execute(examplesTestTemplatePath)
+	if err != nil {
+		return "", "", err
+	}
+
+	return code, test, nil
+}
+
+func (eg ExamplesGenerator) execute(path string) (string, error) {
+	tmplt, err := loadTemplate(path, schemaNameTemplatePath)
+	if err != nil {
+		return "", err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tmplt.Execute(buf, eg); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
@@ -2,9 +2,12 @@ package generatorv3
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	asyncapi "github.com/lerenn/asyncapi-codegen/pkg/asyncapi/v3"
 	"github.com/lerenn/asyncapi-codegen/pkg/codegen/generators"
+	"github.com/lerenn/asyncapi-codegen/pkg/codegen/generators/v3/templates"
 	"github.com/lerenn/asyncapi-codegen/pkg/codegen/options"
 )
 
@@ -17,43 +20,145 @@ type Generator struct {
 	ModuleVersion string
 }
 
+// File is a part of the generated source code, written in its own file when
+// splitting the generated code.
+type File struct {
+	// Name is the name of the part (e.g. "app", "types"), used to name the file.
+	Name string
+	// Content is the source code of the part, without the package and imports.
+	Content string
+}
+
 // Generate generates the source code from the specification.
 func (g Generator) Generate() (string, error) {
-	content, err := g.generateImports(g.Options)
+	content, err := g.generateImports(g.Options, g.typesPackageImports()...)
 	if err != nil {
 		return "", err
 	}
 
-	for remainingParts, part := true, ""; remainingParts; part = "" {
-		switch {
-		case g.Options.Generate.Application:
-			part, err = g.generateApp()
-			g.Options.Generate.Application = false
-		case g.Options.Generate.User:
-			part, err = g.generateUser()
-			g.Options.Generate.User = false
-		case g.Options.Generate.Types:
-			part, err = g.generateTypes()
-			g.Options.Generate.Types = false
-		default:
-			remainingParts = false
+	parts, err := g.generateParts(false)
+	if err != nil {
+		return "", err
+	}
+
+	for _, p := range parts {
+		content += p.Content
+	}
+
+	return content, nil
+}
+
+// GenerateFiles generates the source code from the specification, split into
+// several files: the application and user code, and each section of the types.
+// Each file contains its package and imports, and the empty ones are omitted.
+func (g Generator) GenerateFiles() ([]File, error) {
+	parts, err := g.generateParts(true)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]File, 0, len(parts))
+	for _, p := range parts {
+		if strings.TrimSpace(p.Content) == "" {
+			continue
 		}
 
+		imps, err := g.generateImports(g.Options, g.typesPackageImports()...)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		content += part
+		files = append(files, File{Name: p.Name, Content: imps + p.Content})
 	}
 
-	return content, nil
+	return files, nil
+}
+
+func (g Generator) generateParts(split bool) ([]File, error) {
+	parts := make([]File, 0)
+
+	if g.Options.Generate.Application {
+		app, err := g.generateApp()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, File{Name: "app", Content: app})
+	}
+
+	if g.Options.Generate.User {
+		user, err := g.generateUser()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, File{Name: "user", Content: user})
+	}
+
+	sections := g.typesSections()
+	if len(sections) == 0 {
+		return parts, nil
+	}
+
+	// Generate all the sections of the types at once if the code is not split,
+	// as they are not generated in the same order than the sections
+	if !split {
+		types, err := g.generateTypes(sections...)
+		if err != nil {
+			return nil, err
+		}
+		return append(parts, File{Name: "types", Content: types}), nil
+	}
+
+	for _, s := range sections {
+		types, err := g.generateTypes(s)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, File{Name: s, Content: types})
+	}
+
+	return parts, nil
+}
+
+// typesSections returns the sections of the types that should be generated.
+// When the types are in their own package, the controller shared by the
+// application and user controllers stays with them.
+func (g Generator) typesSections() []string {
+	switch {
+	case g.Options.TypesPackage == "" && g.Options.Generate.Types:
+		return TypesSections
+	case g.Options.TypesPackage == "":
+		return nil
+	case g.Options.Generate.Types:
+		return slices.DeleteFunc(slices.Clone(TypesSections), func(s string) bool {
+			return s == TypesSectionController
+		})
+	case g.Options.Generate.Application || g.Options.Generate.User:
+		return []string{TypesSectionController}
+	default:
+		return nil
+	}
+}
+
+// typesPackageImports returns the import of the types package, if the types
+// are in their own package and used by the generated code.
+func (g Generator) typesPackageImports() []string {
+	if g.Options.TypesPackage == "" || g.Options.Generate.Types {
+		return nil
+	}
+
+	name := templates.TypesPackageName(g.Options.TypesPackage)
+	return []string{fmt.Sprintf("%s %q", name, g.Options.TypesPackage)}
 }
 
-func (g Generator) generateImports(opts options.Options) (string, error) {
+func (g Generator) generateImports(opts options.Options, additionalImports ...string) (string, error) {
 	imps, err := g.Specification.CustomImports()
 	if err != nil {
 		return "", fmt.Errorf("failed to generate custom imports: %w", err)
 	}
+	imps = append(imps, additionalImports...)
+	if opts.ProtobufGoTypes {
+		imps = append(imps, g.Specification.ProtobufGoImports()...)
+	}
 
 	return ImportsGenerator{
 		PackageName:   opts.PackageName,
@@ -63,8 +168,11 @@ func (g Generator) generateImports(opts options.Options) (string, error) {
 	}.Generate()
 }
 
-func (g Generator) generateTypes() (string, error) {
-	return TypesGenerator{Specification: g.Specification}.Generate()
+func (g Generator) generateTypes(sections ...string) (string, error) {
+	return TypesGenerator{
+		Specification: g.Specification,
+		Sections:      sections,
+	}.Generate()
 }
 
 func (g Generator) generateApp() (string, error) {
@@ -117,3 +225,24 @@ func (g Generator) generateUser() (string, error) {
 
 	return content, nil
 }
+
+// GenerateExamples generates the source code of the messages examples from the
+// specification, and the source code of the test checking them.
+func (g Generator) GenerateExamples() (code, test string, err error) {
+	imps, err := g.generateImports(g.Options)
+	if err != nil {
+		return "", "", err
+	}
+
+	code, test, err = NewExamplesGenerator(g.Specification).Generate()
+	if err != nil {
+		return "", "", err
+	}
+
+	testImps, err := g.generateImports(g.Options, `"bytes"`, `"reflect"`, `"testing"`, `"github.com/go-playground/validator/v10"`)
+	if err != nil {
+		return "", "", err
+	}
+
+	return imps + code, testImps + test, nil
+}
@@ -2,6 +2,7 @@ package generatorv3
 
 import (
 	"bytes"
+	"strings"
 )
 
 // ImportsGenerator is a code generator for imports that will add needed imports
@@ -26,5 +27,24 @@ func (ig ImportsGenerator) Generate() (string, error) {
 		return "", err
 	}
 
-	return buf.String(), nil
+	return removeDuplicateImports(buf.String()), nil
+}
+
+// removeDuplicateImports removes the imports that are given more than once, as
+// the custom imports can also be in the default ones.
+func removeDuplicateImports(code string) string {
+	lines := strings.Split(code, "\n")
+	kept := make([]string, 0, len(lines))
+	seen := make(map[string]bool)
+	for _, l := range lines {
+		imp := strings.TrimSpace(l)
+		if !strings.HasPrefix(imp, "//") && strings.HasSuffix(imp, `"`) {
+			if seen[imp] {
+				continue
+			}
+			seen[imp] = true
+		}
+		kept = append(kept, l)
+	}
+	return strings.Join(kept, "\n")
 }
@@ -17,13 +17,22 @@ const (
 	typesTemplatePath            = templatesDir + "/types.tmpl"
 	schemaDefinitionTemplatePath = templatesDir + "/schema_definition.tmpl"
 	schemaNameTemplatePath       = templatesDir + "/schema_name.tmpl"
+	schemaDefaultsTemplatePath   = templatesDir + "/schema_defaults.tmpl"
+	randomTemplatePath           = templatesDir + "/random.tmpl"
 	messageTemplatePath          = templatesDir + "/message.tmpl"
 	subscriberTemplatePath       = templatesDir + "/subscriber.tmpl"
 	controllerTemplatePath       = templatesDir + "/controller.tmpl"
+	fakeTemplatePath             = templatesDir + "/fake.tmpl"
+	examplesTemplatePath         = templatesDir + "/examples.tmpl"
+	examplesTestTemplatePath     = templatesDir + "/examples_test.tmpl"
 
 	marshalingTemplatesDir                     = templatesDir + "/marshaling"
 	marshalingAdditionalPropertiesTemplatePath = marshalingTemplatesDir + "/additional_properties.tmpl"
 	marshalingTimeTemplatePath                 = marshalingTemplatesDir + "/time.tmpl"
+	marshalingTextTemplatePath                 = marshalingTemplatesDir + "/text.tmpl"
+	marshalingNullableTemplatePath             = marshalingTemplatesDir + "/nullable.tmpl"
+	marshalingEnumTemplatePath                 = marshalingTemplatesDir + "/enum.tmpl"
+	marshalingUnionTemplatePath                = marshalingTemplatesDir + "/union.tmpl"
 )
 
 var (
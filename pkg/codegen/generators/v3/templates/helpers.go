@@ -1,38 +1,58 @@
 package templates
 
 import (
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
+	"unicode"
 
 	asyncapi "github.com/lerenn/asyncapi-codegen/pkg/asyncapi/v3"
 	"github.com/lerenn/asyncapi-codegen/pkg/codegen/generators"
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
 	"github.com/lerenn/asyncapi-codegen/pkg/utils"
 	templateutil "github.com/lerenn/asyncapi-codegen/pkg/utils/template"
 )
 
-// GetChildrenObjectSchemas will return all the children object schemas of a
-// schema, only from first level and without AnyOf, AllOf and OneOf.
+// GetChildrenObjectSchemas will return all the children object, union and enum
+// schemas of a schema, only from first level and without AllOf.
 func GetChildrenObjectSchemas(s asyncapi.Schema) []*asyncapi.Schema {
-	allSchemas := utils.MapToList(s.Properties)
+	allSchemas := make([]*asyncapi.Schema, 0, len(s.Properties))
+	for _, name := range utils.SortedKeys(s.Properties) {
+		// The embedded schemas and the message traits have their own children
+		if !IsEmbeddedProperty(s, name) && !isTraitProperty(s, name) {
+			allSchemas = append(allSchemas, s.Properties[name])
+		}
+	}
 
 	if s.Items != nil {
 		allSchemas = append(allSchemas, s.Items)
 	}
 
-	if s.AdditionalProperties != nil {
+	for _, k := range utils.SortedKeys(s.PatternProperties) {
+		allSchemas = append(allSchemas, s.PatternProperties[k])
+	}
+
+	if s.HasAdditionalProperties() {
 		allSchemas = append(allSchemas, s.AdditionalProperties)
 	}
 
-	// Only keep object schemas
+	if s.IsUnion() {
+		allSchemas = append(allSchemas, s.OneOf...)
+		allSchemas = append(allSchemas, s.AnyOf...)
+	}
+
+	// Only keep object, union and enum schemas
 	filteredSchemas := make([]*asyncapi.Schema, 0, len(allSchemas))
 	for _, schema := range allSchemas {
-		if schema.Type == asyncapi.SchemaTypeIsObject.String() {
+		if isNamedSchema(schema) {
 			filteredSchemas = append(filteredSchemas, schema)
 		} else if schema.Type == asyncapi.SchemaTypeIsArray.String() &&
-			schema.Items != nil &&
-			schema.Items.Type == asyncapi.SchemaTypeIsObject.String() {
+			schema.Items != nil && isNamedSchema(schema.Items) {
 			filteredSchemas = append(filteredSchemas, schema.Items)
 		}
 	}
@@ -40,6 +60,221 @@ func GetChildrenObjectSchemas(s asyncapi.Schema) []*asyncapi.Schema {
 	return filteredSchemas
 }
 
+// isTraitProperty checks if a property of a schema comes unchanged from the
+// headers of a message trait, which are generated on their own when the trait
+// mixins are enabled, even if they are not embedded.
+func isTraitProperty(s asyncapi.Schema, property string) bool {
+	if !traitMixins {
+		return false
+	}
+
+	for _, h := range s.TraitHeaders {
+		if h.Follow().Properties[property] == s.Properties[property] {
+			return true
+		}
+	}
+	return false
+}
+
+// isNamedSchema checks if a schema should be generated as its own named type.
+func isNamedSchema(s *asyncapi.Schema) bool {
+	return s.IsUnion() || s.IsEnum() || s.Type == asyncapi.SchemaTypeIsObject.String()
+}
+
+// AllOfEmbeddedSchemas will return the schemas referenced in allOf that should
+// be embedded in the generated struct, when the 'x-go-embed' extension is set.
+// Only plain objects without custom JSON marshaling can be embedded, and no
+// schema is embedded if some of them share properties, as it would make these
+// properties ambiguous.
+func AllOfEmbeddedSchemas(s asyncapi.Schema) []*asyncapi.Schema {
+	if !s.ExtGoEmbed || HasMapProperties(s) {
+		return nil
+	}
+
+	embedded := make([]*asyncapi.Schema, 0, len(s.AllOf))
+	properties := make(map[string]bool)
+	for _, part := range s.AllOf {
+		target := part.Follow()
+		if part.ReferenceTo == nil || target.Type != asyncapi.SchemaTypeIsObject.String() ||
+			HasMapProperties(*target) || IsStrictObject(*target) || target.ExtGoType != "" ||
+			len(NullableProperties(*target)) > 0 {
+			continue
+		}
+
+		for name := range target.Properties {
+			if properties[name] {
+				return nil
+			}
+			properties[name] = true
+		}
+
+		embedded = append(embedded, part)
+	}
+
+	return embedded
+}
+
+var traitMixins bool
+
+// UseTraitMixins is used to generate the headers of the message traits from
+// the components as types embedded in the headers of the messages using them.
+func UseTraitMixins() {
+	traitMixins = true
+}
+
+// TraitMixins returns true if the headers of the message traits from the
+// components should be generated as embedded types.
+func TraitMixins() bool {
+	return traitMixins
+}
+
+// TraitMixinSchemas will return the headers of the message traits that should
+// be embedded in the generated struct of the message headers, when the trait
+// mixins are enabled. The traits whose headers are overridden by the message,
+// or shared with another trait, are not embedded.
+func TraitMixinSchemas(s asyncapi.Schema) []*asyncapi.Schema {
+	if !traitMixins || HasMapProperties(s) {
+		return nil
+	}
+
+	embedded := make([]*asyncapi.Schema, 0, len(s.TraitHeaders))
+	properties := make(map[string]bool)
+	for _, e := range AllOfEmbeddedSchemas(s) {
+		for name := range e.Follow().Properties {
+			properties[name] = true
+		}
+	}
+
+	for _, h := range s.TraitHeaders {
+		if isTraitMixin(s, h, properties) {
+			for name := range h.Follow().Properties {
+				properties[name] = true
+			}
+			embedded = append(embedded, h)
+		}
+	}
+
+	return embedded
+}
+
+func isTraitMixin(s asyncapi.Schema, h *asyncapi.Schema, properties map[string]bool) bool {
+	target := h.Follow()
+	if target.Type != asyncapi.SchemaTypeIsObject.String() || HasMapProperties(*target) ||
+		IsStrictObject(*target) || target.ExtGoType != "" || len(NullableProperties(*target)) > 0 {
+		return false
+	}
+
+	// Check that the properties are generated the same way in the message
+	for name, p := range target.Properties {
+		if properties[name] || s.Properties[name] != p ||
+			FieldMode(s, name, *p) != FieldMode(*target, name, *p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EmbeddedSchemas will return all the schemas embedded in the generated struct
+// of a schema, from allOf or from the message traits.
+func EmbeddedSchemas(s asyncapi.Schema) []*asyncapi.Schema {
+	return append(AllOfEmbeddedSchemas(s), TraitMixinSchemas(s)...)
+}
+
+// IsEmbeddedProperty checks if a property of a schema is provided by one of
+// the schemas embedded from allOf or from the message traits.
+func IsEmbeddedProperty(s asyncapi.Schema, property string) bool {
+	for _, e := range EmbeddedSchemas(s) {
+		if _, exists := e.Follow().Properties[property]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// UnionVariant is a variant of a union schema, i.e. one of the schemas from
+// its oneOf or anyOf.
+type UnionVariant struct {
+	// Name is the name of the variant, in the form of golang conventional names.
+	Name string
+	// Schema is the schema of the variant.
+	Schema *asyncapi.Schema
+	// DiscriminatorValues are the values of the union discriminator property
+	// that identify the variant.
+	DiscriminatorValues []string
+}
+
+// UnionVariants will return the variants of a union schema.
+func UnionVariants(s asyncapi.Schema) []UnionVariant {
+	schemas, prefix := s.OneOf, "OneOf"
+	if len(schemas) == 0 {
+		schemas, prefix = s.AnyOf, "AnyOf"
+	}
+
+	variants := make([]UnionVariant, 0, len(schemas))
+	for i, schema := range schemas {
+		v := UnionVariant{
+			Name:   fmt.Sprintf("%s%d", prefix, i),
+			Schema: schema,
+		}
+
+		// Name the variant after the referenced schema or its title
+		if schema.Reference != "" {
+			v.Name = templateutil.CutSuffix(templateutil.Namify(schema.Follow().Name), "Schema")
+		} else if schema.Title != "" {
+			v.Name = templateutil.Namify(schema.Title)
+		}
+
+		v.DiscriminatorValues = unionVariantDiscriminatorValues(s.Discriminator, schema)
+		variants = append(variants, v)
+	}
+
+	return variants
+}
+
+// unionVariantDiscriminatorValues will return the values of the discriminator
+// property identifying the variant, based on (in order) the discriminator
+// mapping, the constant or unique enum value of the property in the variant, or
+// the name of the referenced schema.
+func unionVariantDiscriminatorValues(d *asyncapi.SchemaDiscriminator, variant *asyncapi.Schema) []string {
+	if d == nil || d.PropertyName == "" {
+		return nil
+	}
+
+	// Get values from mapping
+	values := make([]string, 0)
+	for _, value := range utils.SortedKeys(d.Mapping) {
+		target := d.Mapping[value]
+		if variant.Reference != "" && (target == variant.Reference || strings.HasSuffix(variant.Reference, "/"+target)) {
+			values = append(values, value)
+		}
+	}
+	if len(values) > 0 {
+		return values
+	}
+
+	// Get value from the property constant or unique enum value
+	if prop, exists := variant.Follow().Properties[d.PropertyName]; exists {
+		prop = prop.Follow()
+		if v, ok := prop.Const.(string); ok {
+			return []string{v}
+		}
+		if len(prop.Enum) == 1 {
+			if v, ok := prop.Enum[0].(string); ok {
+				return []string{v}
+			}
+		}
+	}
+
+	// Get value from the referenced schema name
+	if variant.Reference != "" {
+		path := strings.Split(variant.Reference, "/")
+		return []string{path[len(path)-1]}
+	}
+
+	return nil
+}
+
 // referenceToSlicePath will convert a reference to a slice where each element is a
 // step of the path.
 func referenceToSlicePath(ref string) []string {
@@ -77,8 +312,13 @@ func ChannelToMessageTypeName(ch asyncapi.Channel) string {
 }
 
 // OpToMsgTypeName will convert an operation to a message type name in the
-// form of golang conventional type names.
+// form of golang conventional type names. If the operation has multiple
+// messages, this will be the name of the interface implemented by all of them.
 func OpToMsgTypeName(op asyncapi.Operation) string {
+	if OpHasMultipleMessages(op) {
+		return templateutil.Namify(op.Follow().Name) + "Message"
+	}
+
 	msg, err := op.Follow().GetMessage()
 	if err != nil {
 		panic(err)
@@ -86,6 +326,83 @@ func OpToMsgTypeName(op asyncapi.Operation) string {
 	return templateutil.Namify(msg.Follow().Name)
 }
 
+// OpHasMultipleMessages will check if an operation can carry multiple messages.
+//
+// NOTE: operations with a reply (or which are a reply) only support their first
+// message, as request/reply operations need to create and correlate messages.
+func OpHasMultipleMessages(op asyncapi.Operation) bool {
+	op = *op.Follow()
+	if op.Reply != nil || op.ReplyOf != nil {
+		return false
+	}
+	return len(op.GetMessages()) > 1
+}
+
+// OpToMessages will return all the messages that an operation can carry.
+func OpToMessages(op asyncapi.Operation) []*asyncapi.Message {
+	return op.Follow().GetMessages()
+}
+
+// OpHaveCorrelationID will check if all the messages of an operation have a
+// correlation ID.
+func OpHaveCorrelationID(op asyncapi.Operation) bool {
+	if !OpHasMultipleMessages(op) {
+		msg, err := op.Follow().GetMessage()
+		if err != nil {
+			panic(err)
+		}
+		return msg.HaveCorrelationID()
+	}
+
+	for _, msg := range OpToMessages(op) {
+		if !msg.HaveCorrelationID() {
+			return false
+		}
+	}
+	return true
+}
+
+// MessageID will return the identifier of a message, used to know which message
+// has been received on an operation with multiple messages.
+func MessageID(msg asyncapi.Message) string {
+	return templateutil.CutSuffix(templateutil.Namify(msg.Follow().Name), "Message")
+}
+
+// HaveUniqueContentTypes will check if all messages have a content type that
+// is different from the others, so they can be identified with it. The content
+// types are compared without their parameters.
+func HaveUniqueContentTypes(msgs []*asyncapi.Message) bool {
+	contentTypes := make(map[string]bool, len(msgs))
+	for _, msg := range msgs {
+		ct := extensions.MediaType(msg.Follow().ContentType)
+		if ct == "" || contentTypes[ct] {
+			return false
+		}
+		contentTypes[ct] = true
+	}
+	return true
+}
+
+// MessageDiscriminatorCondition will generate the condition checking that the
+// headers of a broker message have the constant values of the message headers.
+// They can be used to identify the message when there is multiple messages on
+// an operation. If there is no constant header, an empty string is returned.
+func MessageDiscriminatorCondition(msg asyncapi.Message, brokerMsgVar string) string {
+	if msg.Follow().Headers == nil {
+		return ""
+	}
+
+	props := msg.Follow().Headers.Follow().Properties
+	conditions := make([]string, 0, len(props))
+	for _, name := range utils.SortedKeys(props) {
+		if v, ok := props[name].Follow().Const.(string); ok {
+			conditions = append(conditions, fmt.Sprintf("string(%s.Headers[%q]) == %q", brokerMsgVar, name, v))
+		}
+	}
+
+	return strings.Join(conditions, " && ")
+}
+
 // OpToChannelTypeName will convert an operation to a channel type name in the
 // form of golang conventional type names.
 func OpToChannelTypeName(op asyncapi.Operation) string {
@@ -114,28 +431,806 @@ func GenerateChannelAddr(ch *asyncapi.Channel) string {
 		return fmt.Sprintf("%q", ch.Address)
 	}
 
-	parameterRegexp := regexp.MustCompile("{[^{}]*}")
-
-	matches := parameterRegexp.FindAllString(ch.Address, -1)
-	format := parameterRegexp.ReplaceAllString(ch.Address, "%s")
+	matches := channelParameterRegexp.FindAllString(ch.Address, -1)
+	format := channelParameterRegexp.ReplaceAllString(ch.Address, "%s")
 
 	sprint := fmt.Sprintf("fmt.Sprintf(%q, ", format)
 	for _, m := range matches {
-		sprint += fmt.Sprintf("params.%s,", templateutil.Namify(m))
+		if escapeChannelParameters {
+			sprint += fmt.Sprintf("extensions.EscapeChannelParameter(params.%s),", templateutil.Namify(m))
+		} else {
+			sprint += fmt.Sprintf("params.%s,", templateutil.Namify(m))
+		}
 	}
 
 	return sprint[:len(sprint)-1] + ")"
 }
 
-var isFieldPointer = func(parent asyncapi.Schema, field string, schema asyncapi.Schema) bool {
-	return !(IsRequired(parent, field) || schema.IsRequired) && schema.Type != "array"
+var channelParameterRegexp = regexp.MustCompile("{[^{}]*}")
+
+var escapeChannelParameters bool
+
+// UseChannelParametersEscaping is used to escape the separators and wildcards
+// of the brokers in the channel parameters values, when building the channel
+// addresses.
+func UseChannelParametersEscaping() {
+	escapeChannelParameters = true
+}
+
+// EscapeChannelParameters checks if the channel parameters values are escaped
+// in the channel addresses.
+func EscapeChannelParameters() bool {
+	return escapeChannelParameters
+}
+
+// ChannelAddrParameters will return the names of the parameters fields, in
+// the order of their appearance in the channel address.
+func ChannelAddrParameters(ch *asyncapi.Channel) []string {
+	matches := channelParameterRegexp.FindAllString(ch.Follow().Address, -1)
+	fields := make([]string, 0, len(matches))
+	for _, m := range matches {
+		fields = append(fields, templateutil.Namify(m))
+	}
+	return fields
+}
+
+// ChannelAddrRegexp will return a regular expression matching the addresses
+// of the channel, with a capturing group for each parameter.
+func ChannelAddrRegexp(ch *asyncapi.Channel) string {
+	addr := ch.Follow().Address
+	indexes := channelParameterRegexp.FindAllStringIndex(addr, -1)
+
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, i := range indexes {
+		b.WriteString(regexp.QuoteMeta(addr[last:i[0]]))
+		b.WriteString("(.*?)")
+		last = i[1]
+	}
+	b.WriteString(regexp.QuoteMeta(addr[last:]))
+	b.WriteString("$")
+
+	return b.String()
+}
+
+// EnumValue is a value of an enum schema.
+type EnumValue struct {
+	// Name is the name of the value, in the form of golang conventional names.
+	Name string
+	// Value is the value as a golang literal.
+	Value string
+}
+
+// EnumValues will return the values of an enum schema.
+func EnumValues(s asyncapi.Schema) []EnumValue {
+	values := make([]EnumValue, 0, len(s.Enum))
+	names := make(map[string]bool, len(s.Enum))
+	for i, v := range s.Enum {
+		if v == nil {
+			continue
+		}
+
+		// Get a name from the value, or from its position if not possible
+		raw := fmt.Sprint(v)
+		name := templateutil.Namify(raw)
+		if name == "" || (raw[0] >= '0' && raw[0] <= '9') || raw[0] == '-' {
+			name = "Value" + regexp.MustCompile("[^a-zA-Z0-9]").ReplaceAllString(raw, "_")
+		}
+		if names[name] {
+			name = fmt.Sprintf("%s%d", name, i)
+		}
+		names[name] = true
+
+		values = append(values, EnumValue{Name: name, Value: GoLiteral(s, v)})
+	}
+
+	return values
+}
+
+// EnumBaseType will return the golang type underlying an enum schema.
+func EnumBaseType(s asyncapi.Schema) string {
+	switch {
+	case s.Type == asyncapi.SchemaTypeIsInteger.String() && s.Format == "int32":
+		return "int32"
+	case s.Type == asyncapi.SchemaTypeIsInteger.String():
+		return "int64"
+	case s.Type == asyncapi.SchemaTypeIsNumber.String() && s.Format == "float":
+		return "float32"
+	case s.Type == asyncapi.SchemaTypeIsNumber.String():
+		return "float64"
+	default:
+		return "string"
+	}
+}
+
+// GoLiteral will return the golang literal of a value from the specification
+// (i.e. parsed from JSON) corresponding to the schema type.
+func GoLiteral(s asyncapi.Schema, v any) string {
+	switch t := v.(type) {
+	case string:
+		return strconv.Quote(t)
+	case float64:
+		if s.Follow().Type == asyncapi.SchemaTypeIsInteger.String() {
+			return strconv.FormatInt(int64(t), 10)
+		}
+		return strconv.FormatFloat(t, 'g', -1, 64)
+	default:
+		return fmt.Sprintf("%#v", v)
+	}
+}
+
+// ConstProperties will return the properties of a schema that have a constant
+// value.
+func ConstProperties(s asyncapi.Schema) map[string]*asyncapi.Schema {
+	properties := make(map[string]*asyncapi.Schema)
+	for name, p := range s.Follow().Properties {
+		if HasConstValue(*p) {
+			properties[name] = p
+		}
+	}
+	return properties
+}
+
+// HasConstValue checks if a schema has a constant value that can be set with
+// a golang literal, i.e. if it is a scalar without a generated format type.
+func HasConstValue(s asyncapi.Schema) bool {
+	f := s.Follow()
+	if s.Const == nil || f.Format == "date" || f.Format == "date-time" || StringFormatType(*f) != "" {
+		return false
+	}
+
+	switch f.Type {
+	case asyncapi.SchemaTypeIsString.String(), asyncapi.SchemaTypeIsInteger.String(),
+		asyncapi.SchemaTypeIsNumber.String(), "boolean":
+		return true
+	default:
+		return false
+	}
+}
+
+// stringFormatTypes are the golang types generated for string formats, except
+// for date and date-time formats that have their own generation.
+var stringFormatTypes = map[string]string{
+	"uuid":     "uuid.UUID",
+	"byte":     "[]byte",
+	"binary":   "[]byte",
+	"duration": "extensions.Duration",
+	"uri":      "extensions.URL",
+	"ipv4":     "netip.Addr",
+	"ipv6":     "netip.Addr",
+}
+
+// StringFormatType will return the golang type generated for a string schema
+// with a format, or an empty string if the schema should be generated as a string.
+func StringFormatType(s asyncapi.Schema) string {
+	if s.Type != asyncapi.SchemaTypeIsString.String() || s.ExtGoType != "" || s.IsEnum() ||
+		!templateutil.IsStringFormatGenerated(s.Format) {
+		return ""
+	}
+	return stringFormatTypes[s.Format]
+}
+
+const (
+	// StringFormatKindIsText is the kind of string formats generated as types
+	// that can be converted from/to strings with MarshalText/UnmarshalText.
+	StringFormatKindIsText = "text"
+	// StringFormatKindIsBase64 is the kind of string formats generated as
+	// bytes that are base64 encoded when converted from/to strings.
+	StringFormatKindIsBase64 = "base64"
+	// StringFormatKindIsBinary is the kind of string formats generated as
+	// raw bytes.
+	StringFormatKindIsBinary = "binary"
+)
+
+// StringFormatKind will return how the golang type generated for a string
+// schema (or the referenced schema) with a format can be converted from/to
+// raw data (in headers or payloads), or an empty string if the schema is not
+// generated with a type from 'StringFormatType'.
+func StringFormatKind(s asyncapi.Schema) string {
+	s = *s.Follow()
+	switch StringFormatType(s) {
+	case "":
+		return ""
+	case "[]byte":
+		if s.Format == "byte" {
+			return StringFormatKindIsBase64
+		}
+		return StringFormatKindIsBinary
+	default:
+		return StringFormatKindIsText
+	}
+}
+
+// DefaultValue will return the default value of a schema, or of the
+// referenced schema if there is none.
+func DefaultValue(s asyncapi.Schema) any {
+	if s.Default == nil && s.ReferenceTo != nil {
+		return s.ReferenceTo.Default
+	}
+	return s.Default
+}
+
+// HasDefaultValue checks if a schema, or the referenced schema, has a default value.
+func HasDefaultValue(s asyncapi.Schema) bool {
+	return DefaultValue(s) != nil
+}
+
+// IsScalarDefault checks if the default value of a schema can be set with a
+// golang literal, instead of being unmarshaled from JSON.
+func IsScalarDefault(s asyncapi.Schema) bool {
+	if s.Follow().ExtGoType != "" {
+		return false
+	}
+
+	switch DefaultValue(s).(type) {
+	case string, float64, bool:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultJSON will return the default value of a schema as a quoted JSON string.
+func DefaultJSON(s asyncapi.Schema) (string, error) {
+	return QuotedJSON(DefaultValue(s))
+}
+
+// QuotedJSON will return a value as a JSON string, quoted as a golang string literal.
+func QuotedJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.Contains(string(b), "`") {
+		return strconv.Quote(string(b)), nil
+	}
+	return "`" + string(b) + "`", nil
+}
+
+// isDefaultApplied checks if the default value of a field is applied by the
+// SetDefaults method, i.e. if the field has a default value and can be
+// detected as not set: the required fields generated as values are skipped,
+// as their zero value can't be told apart from an explicit one.
+func isDefaultApplied(parent asyncapi.Schema, field string, schema asyncapi.Schema) bool {
+	if !HasDefaultValue(schema) || IsEmbeddedProperty(parent, field) {
+		return false
+	}
+
+	return IsFieldNullable(parent, field, schema) || IsFieldPointer(parent, field, schema) ||
+		schema.Follow().Type == asyncapi.SchemaTypeIsArray.String()
+}
+
+// HasSetDefaults checks if the golang type generated from the schema has a
+// SetDefaults method, i.e. if it is an object or a union with default values
+// in it or in its children.
+func HasSetDefaults(s *asyncapi.Schema) bool {
+	return hasSetDefaults(s, make(map[*asyncapi.Schema]bool))
+}
+
+func hasSetDefaults(s *asyncapi.Schema, visited map[*asyncapi.Schema]bool) bool {
+	if s == nil {
+		return false
+	}
+
+	// Only objects and unions have a SetDefaults method
+	s = s.Follow()
+	if s.ExtGoType != "" || (!s.IsUnion() && s.Type != asyncapi.SchemaTypeIsObject.String()) {
+		return false
+	}
+
+	// Prevent infinite recursion on recursive schemas
+	if visited[s] {
+		return false
+	}
+	visited[s] = true
+
+	children := make([]*asyncapi.Schema, 0)
+	for _, k := range utils.SortedKeys(s.Properties) {
+		p := s.Properties[k]
+		if isDefaultApplied(*s, k, *p) {
+			return true
+		}
+		children = append(children, p)
+		if p.Follow().Type == asyncapi.SchemaTypeIsArray.String() {
+			children = append(children, p.Follow().Items)
+		}
+	}
+	children = append(children, EmbeddedSchemas(*s)...)
+	children = append(children, utils.MapToList(s.PatternProperties)...)
+	children = append(children, s.AdditionalProperties)
+	children = append(children, s.OneOf...)
+	children = append(children, s.AnyOf...)
+
+	for _, c := range children {
+		if hasSetDefaults(c, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var strictEnums = true
+
+// AllowUnknownEnumValues is used to accept unknown values when unmarshaling
+// enums, for forward compatibility.
+func AllowUnknownEnumValues() {
+	strictEnums = false
+}
+
+// StrictEnums returns true if unknown values should be rejected when
+// unmarshaling enums.
+func StrictEnums() bool {
+	return strictEnums
+}
+
+var (
+	forcePointers   bool
+	nullableWrapper bool
+)
+
+// IsFieldPointer checks if a field should be generated as a pointer, i.e. if
+// it is optional or nullable, except for arrays and fields generated with the
+// 'Nullable' wrapper.
+func IsFieldPointer(parent asyncapi.Schema, field string, schema asyncapi.Schema) bool {
+	if schema.Type == "array" || IsFieldNullable(parent, field, schema) {
+		return false
+	}
+
+	return forcePointers || !(IsRequired(parent, field) || schema.IsRequired) || schema.IsNullable()
 }
 
 // ForcePointerOnFields is used to force the generation of all fields as pointers, except for arrays.
 func ForcePointerOnFields() {
-	isFieldPointer = func(parent asyncapi.Schema, field string, schema asyncapi.Schema) bool {
-		return schema.Type != "array"
+	forcePointers = true
+}
+
+// IsFieldNullable checks if a field should be generated with the 'Nullable'
+// wrapper, i.e. if it is optional or nullable and if the wrapper is enabled,
+// except for arrays and recursive schemas (as the wrapper holds the value, they
+// are generated as pointers).
+func IsFieldNullable(parent asyncapi.Schema, field string, schema asyncapi.Schema) bool {
+	if !nullableWrapper || schema.Type == "array" {
+		return false
+	}
+
+	// Use the schema from the parent properties, as the recursion is detected
+	// from the schemas addresses
+	s := &schema
+	if p, ok := parent.Properties[field]; ok {
+		s = p
+	}
+	if isRecursiveSchema(s.Follow()) {
+		return false
+	}
+
+	return !(IsRequired(parent, field) || schema.IsRequired) || schema.IsNullable()
+}
+
+// isRecursiveSchema checks if a schema contains itself through its properties
+// or the schemas from its allOf.
+func isRecursiveSchema(s *asyncapi.Schema) bool {
+	return containsSchema(s, s, make(map[*asyncapi.Schema]bool))
+}
+
+func containsSchema(s, target *asyncapi.Schema, visited map[*asyncapi.Schema]bool) bool {
+	if s.Type != asyncapi.SchemaTypeIsObject.String() || visited[s] {
+		return false
+	}
+	visited[s] = true
+
+	children := utils.MapToList(s.Properties)
+	children = append(children, s.AllOf...)
+	for _, c := range children {
+		if c.Follow() == target || containsSchema(c.Follow(), target, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UseNullableWrapper is used to generate the optional and nullable fields with
+// the 'Nullable' wrapper instead of pointers, in order to distinguish absent
+// fields from null fields.
+func UseNullableWrapper() {
+	nullableWrapper = true
+}
+
+const (
+	// FieldModeIsValue is the mode of fields generated as values.
+	FieldModeIsValue = "value"
+	// FieldModeIsPointer is the mode of fields generated as pointers.
+	FieldModeIsPointer = "pointer"
+	// FieldModeIsNullable is the mode of fields generated with the 'Nullable' wrapper.
+	FieldModeIsNullable = "nullable"
+)
+
+// FieldMode will return how a field is generated: as a value, as a pointer or
+// with the 'Nullable' wrapper.
+func FieldMode(parent asyncapi.Schema, field string, schema asyncapi.Schema) string {
+	switch {
+	case IsFieldNullable(parent, field, schema):
+		return FieldModeIsNullable
+	case IsFieldPointer(parent, field, schema):
+		return FieldModeIsPointer
+	default:
+		return FieldModeIsValue
+	}
+}
+
+// CorrelationIDFieldMode will return how the field holding the correlation ID
+// of a message is generated: as a value, as a pointer or with the 'Nullable' wrapper.
+func CorrelationIDFieldMode(msg asyncapi.Message) string {
+	return schemaFieldMode(msg.CorrelationIDSchema())
+}
+
+// OpLocationFieldMode will return how the field at the given location of the
+// operation message is generated: as a value, as a pointer or with the
+// 'Nullable' wrapper.
+func OpLocationFieldMode(op asyncapi.Operation, location string) string {
+	msg, err := op.Follow().GetMessage()
+	if err != nil {
+		panic(err)
+	}
+	return schemaFieldMode(msg.LocationSchema(location))
+}
+
+func schemaFieldMode(s *asyncapi.Schema) string {
+	if s == nil {
+		return FieldModeIsValue
+	}
+
+	// NOTE: the parent is not needed as the schema knows if it is required
+	return FieldMode(asyncapi.Schema{}, "", *s)
+}
+
+// NullableProperties will return the keys of the properties of a schema that
+// are generated with the 'Nullable' wrapper.
+func NullableProperties(s asyncapi.Schema) []string {
+	keys := make([]string, 0)
+	for _, k := range utils.SortedKeys(s.Properties) {
+		if IsFieldNullable(s, k, *s.Properties[k]) && !IsEmbeddedProperty(s, k) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// PatternProperty is a set of properties of an object schema whose keys match
+// a pattern, generated as a map field.
+type PatternProperty struct {
+	// Field is the name of the generated map field.
+	Field string
+	// Pattern is the regular expression that the keys should match.
+	Pattern string
+	// Regexp is the name of the generated variable holding the compiled pattern.
+	Regexp string
+	// Schema is the schema of the values.
+	Schema *asyncapi.Schema
+}
+
+// PatternProperties will return the pattern properties of an object schema,
+// sorted by pattern. The map field is named after the title of the pattern
+// schema if any, or after the pattern otherwise (e.g. 'PatternPropertiesX'
+// for '^x-'), so it doesn't change when other patterns are added. The fields
+// whose names collide are suffixed with a hash of their pattern.
+func PatternProperties(s asyncapi.Schema) ([]PatternProperty, error) {
+	patterns := utils.SortedKeys(s.PatternProperties)
+	properties := make([]PatternProperty, 0, len(patterns))
+	fields := make(map[string]int, len(patterns))
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern property %q in %q: %w", pattern, s.Name, err)
+		}
+
+		p := PatternProperty{
+			Field:   patternPropertyField(pattern),
+			Pattern: pattern,
+			Schema:  s.PatternProperties[pattern],
+		}
+		if p.Schema.Title != "" {
+			p.Field = templateutil.Namify(p.Schema.Title)
+		}
+		fields[p.Field]++
+
+		properties = append(properties, p)
+	}
+
+	for i, p := range properties {
+		if fields[p.Field] > 1 {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(p.Pattern))
+			properties[i].Field = fmt.Sprintf("%s%08X", p.Field, h.Sum32())
+		}
+		properties[i].Regexp = "regexp" + templateutil.Namify(s.Name) + properties[i].Field
+	}
+
+	return properties, nil
+}
+
+var patternPropertyWordRegexp = regexp.MustCompile("[A-Za-z0-9]+")
+
+// patternPropertyField returns the name of the map field of a pattern
+// property, from the words of its pattern.
+func patternPropertyField(pattern string) string {
+	field := "PatternProperties"
+	for _, w := range patternPropertyWordRegexp.FindAllString(pattern, -1) {
+		field += strings.ToUpper(w[:1]) + w[1:]
+	}
+	return field
+}
+
+// HasMapProperties checks if an object schema has properties that are
+// generated as maps, i.e. pattern properties or additional properties.
+func HasMapProperties(s asyncapi.Schema) bool {
+	return len(s.PatternProperties) > 0 || s.HasAdditionalProperties()
+}
+
+var (
+	strictAdditionalProperties bool
+	plainMaps                  bool
+)
+
+// RejectAdditionalProperties is used to reject the unknown properties when
+// unmarshaling objects that don't allow additional properties.
+func RejectAdditionalProperties() {
+	strictAdditionalProperties = true
+}
+
+// IsStrictObject checks if the unknown properties should be rejected when
+// unmarshaling the object, i.e. if the additional properties are forbidden
+// and the strict decoding is enabled.
+func IsStrictObject(s asyncapi.Schema) bool {
+	return strictAdditionalProperties && s.ForbidsAdditionalProperties()
+}
+
+// UsePlainMaps is used to generate the objects that only have additional
+// properties as plain golang maps, instead of structs with a map field.
+func UsePlainMaps() {
+	plainMaps = true
+}
+
+// IsPlainMap checks if an object schema should be generated as a plain golang
+// map, i.e. if the plain maps are enabled and the object only have additional
+// properties.
+func IsPlainMap(s asyncapi.Schema) bool {
+	return plainMaps && s.Type == asyncapi.SchemaTypeIsObject.String() && s.ExtGoType == "" &&
+		len(s.Properties) == 0 && len(s.PatternProperties) == 0 && s.HasAdditionalProperties()
+}
+
+// IsStruct checks if a schema is generated as a golang struct, i.e. if it is
+// an object that is not generated as a plain map.
+func IsStruct(s *asyncapi.Schema) bool {
+	if s == nil {
+		return false
+	}
+	s = s.Follow()
+	return s.Type == asyncapi.SchemaTypeIsObject.String() && !IsPlainMap(*s)
+}
+
+// IsAvro checks if a schema comes from an Avro schema, and should be
+// (un)marshaled with the Avro binary encoding.
+func IsAvro(s *asyncapi.Schema) bool {
+	return s != nil && s.IsAvro()
+}
+
+var avroConfluentWireFormat bool
+
+// UseAvroConfluentWireFormat is used to (un)marshal the Avro payloads with the
+// Confluent wire format, i.e. prefixed with a magic byte and the schema ID.
+func UseAvroConfluentWireFormat() {
+	avroConfluentWireFormat = true
+}
+
+// AvroConfluent returns true if the Avro payloads should be (un)marshaled with
+// the Confluent wire format.
+func AvroConfluent() bool {
+	return avroConfluentWireFormat
+}
+
+// IsProtobuf checks if a schema comes from a protobuf message, and should be
+// (un)marshaled with the protobuf binary encoding.
+func IsProtobuf(s *asyncapi.Schema) bool {
+	return s != nil && s.IsProtobuf()
+}
+
+var protobufGoTypes bool
+
+// UseProtobufGoTypes is used to reference the types generated by protoc-gen-go
+// for the protobuf messages, instead of generating them.
+func UseProtobufGoTypes() {
+	protobufGoTypes = true
+}
+
+// ProtobufGoType returns the type generated by protoc-gen-go for a schema that
+// comes from a protobuf message, if it should be used instead of a generated
+// type, or an empty string otherwise. The payloads are pointers to this type,
+// as the protobuf messages must not be copied.
+func ProtobufGoType(s asyncapi.Schema) string {
+	if !protobufGoTypes {
+		return ""
+	}
+	return s.Follow().ProtobufGoType
+}
+
+const (
+	// CloudEventsModeIsBinary is the CloudEvents binary content mode, where
+	// CloudEvents attributes are set as message headers.
+	CloudEventsModeIsBinary = "binary"
+	// CloudEventsModeIsStructured is the CloudEvents structured content mode, where
+	// the message payload is wrapped into a CloudEvents JSON envelope.
+	CloudEventsModeIsStructured = "structured"
+)
+
+var cloudEventsMode = ""
+
+// SetCloudEventsMode sets the CloudEvents content mode used by the generated
+// messages. An empty mode disables CloudEvents.
+func SetCloudEventsMode(mode string) error {
+	switch mode {
+	case "", CloudEventsModeIsBinary, CloudEventsModeIsStructured:
+		cloudEventsMode = mode
+		return nil
+	default:
+		return fmt.Errorf("unknown CloudEvents mode %q, supported values: binary, structured", mode)
+	}
+}
+
+// CloudEventsMode returns the CloudEvents content mode used by the generated
+// messages, or an empty string if CloudEvents are disabled.
+func CloudEventsMode() string {
+	return cloudEventsMode
+}
+
+var typesPackage string
+
+// SetTypesPackage sets the import path of the package containing the types,
+// when they are generated in their own package instead of the package of the
+// application and user code. An empty path disables it.
+func SetTypesPackage(importPath string) error {
+	if importPath != "" && TypesPackageName(importPath) == "" {
+		return fmt.Errorf("invalid types package %q", importPath)
+	}
+
+	typesPackage = importPath
+	return nil
+}
+
+// TypesPackage returns the import path of the package containing the types,
+// or an empty string if they are in the package of the application and user code.
+func TypesPackage() string {
+	return typesPackage
+}
+
+// TypesPackageName returns the name used to reference the types package in
+// the generated code, from the last element of its import path. It returns
+// an empty string if no name can be deduced from it.
+func TypesPackageName(importPath string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return -1
+	}, path.Base(importPath))
+
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		return ""
+	}
+	return name
+}
+
+// TypesRef will return the reference to a type or a function generated with
+// the types. When the types are in their own package, the reference is
+// qualified with this package, and exported.
+func TypesRef(name string) string {
+	if typesPackage == "" {
+		return name
+	}
+	return TypesPackageName(typesPackage) + "." + TypesMethod(name)
+}
+
+// TypesMethod will return the name of a method of the types, that is exported
+// when the types are in their own package.
+func TypesMethod(name string) string {
+	if typesPackage == "" || name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+var randomMessages bool
+
+// UseRandomMessages is used to generate the functions creating random
+// messages that are valid against the schemas.
+func UseRandomMessages() {
+	randomMessages = true
+}
+
+// RandomMessages returns true if the functions creating random messages
+// should be generated.
+func RandomMessages() bool {
+	return randomMessages
+}
+
+var fakeControllers bool
+
+// UseFakeControllers is used to generate the interfaces of the controllers
+// and their fake implementations.
+func UseFakeControllers() {
+	fakeControllers = true
+}
+
+// FakeControllers returns true if the interfaces of the controllers and their
+// fake implementations should be generated.
+func FakeControllers() bool {
+	return fakeControllers
+}
+
+// RandomConstraints will return the golang literal of the constraints that
+// the random values of a schema should satisfy, from its validations. The
+// bounds are set with their presence, as zero is a valid bound when it is
+// explicitly given in the specification.
+func RandomConstraints(s asyncapi.Schema) string {
+	fields := make([]string, 0)
+	isSet := func(keyword string, v float64) bool {
+		return v != 0 || utils.IsInSlice(s.ZeroBounds, keyword)
+	}
+	addUint := func(name string, v uint) {
+		if v != 0 {
+			fields = append(fields, fmt.Sprintf("%s: %d", name, v))
+		}
+	}
+	addMaxUint := func(name, keyword string, v uint) {
+		if isSet(keyword, float64(v)) {
+			fields = append(fields, fmt.Sprintf("%s: %d, Has%s: true", name, v, name))
+		}
+	}
+	addFloat := func(name, keyword string, v float64) {
+		if isSet(keyword, v) {
+			fields = append(fields, fmt.Sprintf("%s: %s, Has%s: true", name, strconv.FormatFloat(v, 'g', -1, 64), name))
+		}
+	}
+
+	addUint("MinLength", s.MinLength)
+	addMaxUint("MaxLength", "maxLength", s.MaxLength)
+	if s.Pattern != "" {
+		fields = append(fields, fmt.Sprintf("Pattern: %q", s.Pattern))
+	}
+	if s.Format != "" && s.Type == asyncapi.SchemaTypeIsString.String() {
+		fields = append(fields, fmt.Sprintf("Format: %q", s.Format))
+	}
+	addFloat("Minimum", "minimum", s.Minimum)
+	addFloat("Maximum", "maximum", s.Maximum)
+	addFloat("ExclusiveMinimum", "exclusiveMinimum", s.ExclusiveMinimum)
+	addFloat("ExclusiveMaximum", "exclusiveMaximum", s.ExclusiveMaximum)
+	addUint("MinItems", s.MinItems)
+	addMaxUint("MaxItems", "maxItems", s.MaxItems)
+	if s.UniqueItems {
+		fields = append(fields, "UniqueItems: true")
+	}
+
+	return "extensions.RandomConstraints{" + strings.Join(fields, ", ") + "}"
+}
+
+// RandomUnionVariants will return the variants of a union schema that random
+// values can be created from, i.e. all the variants except those that can't
+// be identified by the discriminator, if any.
+func RandomUnionVariants(s asyncapi.Schema) []UnionVariant {
+	variants := UnionVariants(s)
+	if s.Discriminator == nil || s.Discriminator.PropertyName == "" {
+		return variants
+	}
+
+	identified := make([]UnionVariant, 0, len(variants))
+	for _, v := range variants {
+		if len(v.DiscriminatorValues) > 0 && v.Schema.Follow().Properties[s.Discriminator.PropertyName] != nil {
+			identified = append(identified, v)
+		}
 	}
+	return identified
 }
 
 // HelpersFunctions returns the functions that can be used as helpers
@@ -143,15 +1238,66 @@ func ForcePointerOnFields() {
 func HelpersFunctions() template.FuncMap {
 	return template.FuncMap{
 		"getChildrenObjectSchemas":       GetChildrenObjectSchemas,
+		"allOfEmbeddedSchemas":           AllOfEmbeddedSchemas,
+		"embeddedSchemas":                EmbeddedSchemas,
+		"traitMixins":                    TraitMixins,
+		"isEmbeddedProperty":             IsEmbeddedProperty,
+		"unionVariants":                  UnionVariants,
+		"enumValues":                     EnumValues,
+		"enumBaseType":                   EnumBaseType,
+		"goLiteral":                      GoLiteral,
+		"constProperties":                ConstProperties,
+		"hasConstValue":                  HasConstValue,
+		"strictEnums":                    StrictEnums,
+		"stringFormatType":               StringFormatType,
+		"stringFormatKind":               StringFormatKind,
+		"defaultValue":                   DefaultValue,
+		"hasDefaultValue":                HasDefaultValue,
+		"isScalarDefault":                IsScalarDefault,
+		"defaultJSON":                    DefaultJSON,
+		"quotedJSON":                     QuotedJSON,
+		"hasSetDefaults":                 HasSetDefaults,
 		"channelToMessageTypeName":       ChannelToMessageTypeName,
 		"opToMsgTypeName":                OpToMsgTypeName,
 		"opToChannelTypeName":            OpToChannelTypeName,
+		"opHasMultipleMessages":          OpHasMultipleMessages,
+		"opToMessages":                   OpToMessages,
+		"opHaveCorrelationID":            OpHaveCorrelationID,
+		"messageID":                      MessageID,
+		"mediaType":                      extensions.MediaType,
+		"haveUniqueContentTypes":         HaveUniqueContentTypes,
+		"messageDiscriminatorCondition":  MessageDiscriminatorCondition,
 		"isRequired":                     IsRequired,
-		"isFieldPointer":                 isFieldPointer,
+		"isFieldPointer":                 IsFieldPointer,
+		"isFieldNullable":                IsFieldNullable,
+		"nullableProperties":             NullableProperties,
+		"patternProperties":              PatternProperties,
+		"hasMapProperties":               HasMapProperties,
+		"isStrictObject":                 IsStrictObject,
+		"isPlainMap":                     IsPlainMap,
+		"isStruct":                       IsStruct,
+		"fieldMode":                      FieldMode,
+		"correlationIDFieldMode":         CorrelationIDFieldMode,
+		"opLocationFieldMode":            OpLocationFieldMode,
 		"generateChannelAddr":            GenerateChannelAddr,
 		"generateChannelAddrFromOp":      GenerateChannelAddrFromOp,
+		"channelAddrParameters":          ChannelAddrParameters,
+		"channelAddrRegexp":              ChannelAddrRegexp,
+		"escapeChannelParameters":        EscapeChannelParameters,
 		"referenceToStructAttributePath": ReferenceToStructAttributePath,
 		"generateValidateTags":           generators.GenerateValidateTags[asyncapi.Schema],
 		"generateJSONTags":               generators.GenerateJSONTags[asyncapi.Schema],
+		"cloudEventsMode":                CloudEventsMode,
+		"isAvro":                         IsAvro,
+		"avroConfluent":                  AvroConfluent,
+		"isProtobuf":                     IsProtobuf,
+		"protobufGoType":                 ProtobufGoType,
+		"randomMessages":                 RandomMessages,
+		"randomConstraints":              RandomConstraints,
+		"randomUnionVariants":            RandomUnionVariants,
+		"fakeControllers":                FakeControllers,
+		"typesPackage":                   TypesPackage,
+		"typesRef":                       TypesRef,
+		"typesMethod":                    TypesMethod,
 	}
 }
@@ -49,6 +49,400 @@ func (suite *HelpersSuite) TestIsRequired() {
 	}
 }
 
+func (suite *HelpersSuite) TestHaveUniqueContentTypes() {
+	cases := []struct {
+		ContentTypes []string
+		Result       bool
+	}{
+		{ContentTypes: []string{"application/json", "application/xml"}, Result: true},
+		{ContentTypes: []string{"application/json", "application/json"}, Result: false},
+		{ContentTypes: []string{"application/json", ""}, Result: false},
+		{ContentTypes: []string{"application/json", "Application/JSON; charset=utf-8"}, Result: false},
+	}
+
+	for i, c := range cases {
+		msgs := make([]*asyncapiv3.Message, 0, len(c.ContentTypes))
+		for _, ct := range c.ContentTypes {
+			msgs = append(msgs, &asyncapiv3.Message{ContentType: ct})
+		}
+		suite.Require().Equal(c.Result, HaveUniqueContentTypes(msgs), i)
+	}
+}
+
+func (suite *HelpersSuite) TestMessageDiscriminatorCondition() {
+	msg := asyncapiv3.Message{
+		Headers: &asyncapiv3.Schema{
+			Properties: map[string]*asyncapiv3.Schema{
+				"version":   {Type: "string"},
+				"eventType": {Type: "string", Validations: asyncapi.Validations[asyncapiv3.Schema]{Const: "created"}},
+				"domain":    {Type: "string", Validations: asyncapi.Validations[asyncapiv3.Schema]{Const: "accounts"}},
+			},
+		},
+	}
+
+	suite.Require().Equal(
+		`string(bMsg.Headers["domain"]) == "accounts" && string(bMsg.Headers["eventType"]) == "created"`,
+		MessageDiscriminatorCondition(msg, "bMsg"))
+	suite.Require().Equal("", MessageDiscriminatorCondition(asyncapiv3.Message{}, "bMsg"))
+}
+
 func (suite *HelpersSuite) TestGetChildrenObjectSchemas() {
-	// TODO
+	a := &asyncapiv3.Schema{Name: "ASchema", Type: "object"}
+	b := &asyncapiv3.Schema{Name: "BSchema", Type: "object"}
+	c := &asyncapiv3.Schema{Name: "CSchema", Type: "object"}
+	s := asyncapiv3.Schema{
+		Type: "object",
+		Properties: map[string]*asyncapiv3.Schema{
+			"c":  c,
+			"a":  a,
+			"id": {Type: "string"},
+			"b":  b,
+		},
+	}
+
+	// Always in the order of the properties names, for a stable generation
+	for i := 0; i < 10; i++ {
+		suite.Require().Equal([]*asyncapiv3.Schema{a, b, c}, GetChildrenObjectSchemas(s))
+	}
+}
+
+func (suite *HelpersSuite) TestUnionVariants() {
+	cat := &asyncapiv3.Schema{Name: "CatSchema", Type: "object"}
+	union := asyncapiv3.Schema{
+		OneOf: []*asyncapiv3.Schema{
+			{Reference: "#/components/schemas/Cat", ReferenceTo: cat},
+			{Reference: "#/components/schemas/Dog", ReferenceTo: &asyncapiv3.Schema{Name: "DogSchema"}},
+			{Title: "bird", Properties: map[string]*asyncapiv3.Schema{
+				"petType": {Type: "string", Validations: asyncapi.Validations[asyncapiv3.Schema]{Const: "tweet"}},
+			}},
+			{Type: "object"},
+		},
+		Discriminator: &asyncapiv3.SchemaDiscriminator{
+			PropertyName: "petType",
+			Mapping:      map[string]string{"cat": "#/components/schemas/Cat", "kitten": "Cat"},
+		},
+	}
+
+	variants := UnionVariants(union)
+	suite.Require().Len(variants, 4)
+
+	suite.Require().Equal("Cat", variants[0].Name)
+	suite.Require().Equal([]string{"cat", "kitten"}, variants[0].DiscriminatorValues)
+	suite.Require().Equal("Dog", variants[1].Name)
+	suite.Require().Equal([]string{"Dog"}, variants[1].DiscriminatorValues)
+	suite.Require().Equal("Bird", variants[2].Name)
+	suite.Require().Equal([]string{"tweet"}, variants[2].DiscriminatorValues)
+	suite.Require().Equal("OneOf3", variants[3].Name)
+	suite.Require().Nil(variants[3].DiscriminatorValues)
+}
+
+func (suite *HelpersSuite) TestEnumValues() {
+	cases := []struct {
+		Schema asyncapiv3.Schema
+		Values []EnumValue
+	}{
+		{
+			Schema: asyncapiv3.Schema{Type: "string", Validations: asyncapi.Validations[asyncapiv3.Schema]{
+				Enum: []any{"in-progress", "done", "in_progress", "1st"},
+			}},
+			Values: []EnumValue{
+				{Name: "InProgress", Value: `"in-progress"`},
+				{Name: "Done", Value: `"done"`},
+				{Name: "InProgress2", Value: `"in_progress"`},
+				{Name: "Value1st", Value: `"1st"`},
+			},
+		},
+		{
+			Schema: asyncapiv3.Schema{Type: "integer", Validations: asyncapi.Validations[asyncapiv3.Schema]{
+				Enum: []any{float64(1), float64(-2)},
+			}},
+			Values: []EnumValue{{Name: "Value1", Value: "1"}, {Name: "Value_2", Value: "-2"}},
+		},
+		{
+			Schema: asyncapiv3.Schema{Type: "number", Validations: asyncapi.Validations[asyncapiv3.Schema]{
+				Enum: []any{1.5, nil},
+			}},
+			Values: []EnumValue{{Name: "Value1_5", Value: "1.5"}},
+		},
+	}
+
+	for i, c := range cases {
+		suite.Require().Equal(c.Values, EnumValues(c.Schema), i)
+	}
+}
+
+func (suite *HelpersSuite) TestHasSetDefaults() {
+	withDefault := &asyncapiv3.Schema{Type: "object", Properties: map[string]*asyncapiv3.Schema{
+		"unit": {Type: "string", Default: "piece"},
+	}}
+	withoutDefault := &asyncapiv3.Schema{Type: "object", Properties: map[string]*asyncapiv3.Schema{
+		"name": {Type: "string"},
+	}}
+	nested := &asyncapiv3.Schema{Type: "object", Properties: map[string]*asyncapiv3.Schema{
+		"items": {Type: "array", Items: &asyncapiv3.Schema{ReferenceTo: withDefault}},
+	}}
+	recursive := &asyncapiv3.Schema{Type: "object", Properties: map[string]*asyncapiv3.Schema{}}
+	recursive.Properties["child"] = &asyncapiv3.Schema{ReferenceTo: recursive}
+
+	suite.Require().True(HasSetDefaults(withDefault))
+	suite.Require().False(HasSetDefaults(withoutDefault))
+	suite.Require().True(HasSetDefaults(nested))
+	suite.Require().False(HasSetDefaults(recursive))
+	suite.Require().False(HasSetDefaults(&asyncapiv3.Schema{Type: "string", Default: "value"}))
+	suite.Require().False(HasSetDefaults(nil))
+
+	// The defaults of required fields generated as values can't be applied
+	required := &asyncapiv3.Schema{
+		Type:        "object",
+		Properties:  map[string]*asyncapiv3.Schema{"unit": {Type: "string", Default: "piece"}},
+		Validations: asyncapi.Validations[asyncapiv3.Schema]{Required: []string{"unit"}},
+	}
+	suite.Require().False(HasSetDefaults(required))
+
+	ForcePointerOnFields()
+	defer func() { forcePointers = false }()
+	suite.Require().True(HasSetDefaults(required))
+}
+
+func (suite *HelpersSuite) TestStringFormatType() {
+	cases := []struct {
+		Schema asyncapiv3.Schema
+		Type   string
+		Kind   string
+	}{
+		{Schema: asyncapiv3.Schema{Type: "string", Format: "uuid"}, Type: "uuid.UUID", Kind: "text"},
+		{Schema: asyncapiv3.Schema{Type: "string", Format: "byte"}, Type: "[]byte", Kind: "base64"},
+		{Schema: asyncapiv3.Schema{Type: "string", Format: "binary"}, Type: "[]byte", Kind: "binary"},
+		{Schema: asyncapiv3.Schema{Type: "string", Format: "ipv6"}, Type: "netip.Addr", Kind: "text"},
+		{Schema: asyncapiv3.Schema{Type: "string", Format: "email"}},
+		{Schema: asyncapiv3.Schema{Type: "string", Format: "date-time"}},
+		{Schema: asyncapiv3.Schema{Type: "string", Format: "uuid",
+			Extensions: asyncapiv3.Extensions{ExtGoType: "mypackage.ID"}}},
+		{Schema: asyncapiv3.Schema{Type: "string", Format: "uuid", Validations: asyncapi.Validations[asyncapiv3.Schema]{
+			Enum: []any{"8f14e45f-ceea-467f-a8f7-4e1f0ddd4a3b"},
+		}}},
+		{Schema: asyncapiv3.Schema{Type: "integer", Format: "uuid"}},
+	}
+
+	for i, c := range cases {
+		suite.Require().Equal(c.Type, StringFormatType(c.Schema), i)
+		suite.Require().Equal(c.Kind, StringFormatKind(c.Schema), i)
+	}
+
+	// The kind should follow references, while the type is only for the schema itself
+	ref := asyncapiv3.Schema{ReferenceTo: &asyncapiv3.Schema{Type: "string", Format: "uri"}}
+	suite.Require().Equal("", StringFormatType(ref))
+	suite.Require().Equal("text", StringFormatKind(ref))
+}
+
+func (suite *HelpersSuite) TestFieldMode() {
+	parent := asyncapiv3.Schema{Type: "object", Validations: asyncapi.Validations[asyncapiv3.Schema]{
+		Required: []string{"required", "nullable"},
+	}}
+	required := asyncapiv3.Schema{Type: "string"}
+	optional := asyncapiv3.Schema{Type: "string"}
+	nullable := asyncapiv3.Schema{Type: "string", Nullable: true}
+	array := asyncapiv3.Schema{Type: "array", Items: &asyncapiv3.Schema{Type: "string"}}
+
+	// Without the wrapper
+	suite.Require().Equal(FieldModeIsValue, FieldMode(parent, "required", required))
+	suite.Require().Equal(FieldModeIsPointer, FieldMode(parent, "optional", optional))
+	suite.Require().Equal(FieldModeIsPointer, FieldMode(parent, "nullable", nullable))
+	suite.Require().Equal(FieldModeIsValue, FieldMode(parent, "array", array))
+
+	// With the wrapper
+	UseNullableWrapper()
+	defer func() { nullableWrapper = false }()
+	suite.Require().Equal(FieldModeIsValue, FieldMode(parent, "required", required))
+	suite.Require().Equal(FieldModeIsNullable, FieldMode(parent, "optional", optional))
+	suite.Require().Equal(FieldModeIsNullable, FieldMode(parent, "nullable", nullable))
+	suite.Require().Equal(FieldModeIsValue, FieldMode(parent, "array", array))
+}
+
+func (suite *HelpersSuite) TestFieldModeOnRecursiveSchema() {
+	order := &asyncapiv3.Schema{Name: "Order", Type: "object"}
+	customer := &asyncapiv3.Schema{Name: "Customer", Type: "object"}
+	order.Properties = map[string]*asyncapiv3.Schema{
+		"parent":   {ReferenceTo: order},
+		"customer": customer,
+	}
+
+	// The wrapper holds the value, so recursive fields stay pointers
+	UseNullableWrapper()
+	defer func() { nullableWrapper = false }()
+	suite.Require().Equal(FieldModeIsPointer, FieldMode(*order, "parent", *order.Properties["parent"]))
+	suite.Require().Equal(FieldModeIsNullable, FieldMode(*order, "customer", *customer))
+}
+
+func (suite *HelpersSuite) TestPatternProperties() {
+	s := asyncapiv3.Schema{Name: "Labels", Type: "object", PatternProperties: map[string]*asyncapiv3.Schema{
+		"^x-":   {Type: "string"},
+		"^n_":   {Type: "integer"},
+		"^tag_": {Type: "boolean", Title: "tags"},
+	}}
+
+	properties, err := PatternProperties(s)
+	suite.Require().NoError(err)
+	suite.Require().Len(properties, 3)
+	suite.Require().Equal("PatternPropertiesN", properties[0].Field)
+	suite.Require().Equal("^n_", properties[0].Pattern)
+	suite.Require().Equal("regexpLabelsPatternPropertiesN", properties[0].Regexp)
+	suite.Require().Equal("Tags", properties[1].Field)
+	suite.Require().Equal("PatternPropertiesX", properties[2].Field)
+
+	// The names don't depend on the other patterns
+	s.PatternProperties = map[string]*asyncapiv3.Schema{"^x-": {Type: "string"}}
+	properties, err = PatternProperties(s)
+	suite.Require().NoError(err)
+	suite.Require().Equal("PatternPropertiesX", properties[0].Field)
+
+	// Colliding names are suffixed with a hash of the pattern
+	s.PatternProperties = map[string]*asyncapiv3.Schema{"^x-": {Type: "string"}, "^x_": {Type: "string"}}
+	properties, err = PatternProperties(s)
+	suite.Require().NoError(err)
+	suite.Require().Regexp("^PatternPropertiesX[0-9A-F]{8}$", properties[0].Field)
+	suite.Require().Regexp("^PatternPropertiesX[0-9A-F]{8}$", properties[1].Field)
+	suite.Require().NotEqual(properties[0].Field, properties[1].Field)
+
+	// Patterns should be valid golang regular expressions
+	s.PatternProperties = map[string]*asyncapiv3.Schema{"^(?=x)": {Type: "string"}}
+	_, err = PatternProperties(s)
+	suite.Require().Error(err)
+}
+
+func (suite *HelpersSuite) TestChannelAddrRegexp() {
+	ch := &asyncapiv3.Channel{Address: "users.{region}.{userId}+signup"}
+
+	suite.Require().Equal(`^users\.(.*?)\.(.*?)\+signup$`, ChannelAddrRegexp(ch))
+	suite.Require().Equal([]string{"Region", "UserId"}, ChannelAddrParameters(ch))
+
+	ch.Parameters = map[string]*asyncapiv3.Parameter{"region": {}, "userId": {}}
+	suite.Require().Equal("fmt.Sprintf(\"users.%s.%s+signup\", params.Region,params.UserId)",
+		GenerateChannelAddr(ch))
+
+	UseChannelParametersEscaping()
+	defer func() { escapeChannelParameters = false }()
+	suite.Require().Equal("fmt.Sprintf(\"users.%s.%s+signup\", "+
+		"extensions.EscapeChannelParameter(params.Region),extensions.EscapeChannelParameter(params.UserId))",
+		GenerateChannelAddr(ch))
+}
+
+func (suite *HelpersSuite) TestIsPlainMap() {
+	pure := asyncapiv3.Schema{Type: "object", AdditionalProperties: &asyncapiv3.Schema{Type: "string"}}
+	withProperties := asyncapiv3.Schema{Type: "object", AdditionalProperties: &asyncapiv3.Schema{Type: "string"},
+		Properties: map[string]*asyncapiv3.Schema{"name": {Type: "string"}}}
+	forbidden := asyncapiv3.Schema{Type: "object", AdditionalProperties: &asyncapiv3.Schema{Forbidden: true}}
+
+	suite.Require().False(IsPlainMap(pure))
+
+	UsePlainMaps()
+	defer func() { plainMaps = false }()
+	suite.Require().True(IsPlainMap(pure))
+	suite.Require().False(IsPlainMap(withProperties))
+	suite.Require().False(IsPlainMap(forbidden))
+}
+
+func (suite *HelpersSuite) TestIsStruct() {
+	pure := &asyncapiv3.Schema{Type: "object", AdditionalProperties: &asyncapiv3.Schema{Type: "string"}}
+
+	suite.Require().False(IsStruct(nil))
+	suite.Require().False(IsStruct(&asyncapiv3.Schema{Type: "string"}))
+	suite.Require().True(IsStruct(pure))
+
+	UsePlainMaps()
+	defer func() { plainMaps = false }()
+	suite.Require().False(IsStruct(pure))
+}
+
+func (suite *HelpersSuite) TestTraitMixinSchemas() {
+	tenantID := &asyncapiv3.Schema{Type: "string"}
+	tenant := &asyncapiv3.Schema{Type: "object", Properties: map[string]*asyncapiv3.Schema{"tenantId": tenantID}}
+	traced := &asyncapiv3.Schema{Type: "object", Properties: map[string]*asyncapiv3.Schema{"traceId": {Type: "string"}}}
+	headers := asyncapiv3.Schema{
+		Type: "object",
+		Properties: map[string]*asyncapiv3.Schema{
+			"tenantId": tenantID,
+			// Overridden by the message
+			"traceId": {Type: "string"},
+		},
+		TraitHeaders: []*asyncapiv3.Schema{tenant, traced},
+	}
+
+	suite.Require().Empty(TraitMixinSchemas(headers))
+
+	UseTraitMixins()
+	defer func() { traitMixins = false }()
+	suite.Require().Equal([]*asyncapiv3.Schema{tenant}, TraitMixinSchemas(headers))
+	suite.Require().True(IsEmbeddedProperty(headers, "tenantId"))
+	suite.Require().False(IsEmbeddedProperty(headers, "traceId"))
+
+	// Not embedded if required only by the message
+	headers.Required = []string{"tenantId"}
+	suite.Require().Empty(TraitMixinSchemas(headers))
+}
+
+func (suite *HelpersSuite) TestGetChildrenObjectSchemasWithTraitMixins() {
+	span := &asyncapiv3.Schema{Name: "SpanSchema", Type: "object"}
+	traced := &asyncapiv3.Schema{Type: "object", Properties: map[string]*asyncapiv3.Schema{"span": span}}
+	override := &asyncapiv3.Schema{Name: "OverrideSchema", Type: "object"}
+	headers := asyncapiv3.Schema{
+		Type: "object",
+		Properties: map[string]*asyncapiv3.Schema{
+			"span": span,
+			// Overridden by the message
+			"override": override,
+		},
+		// Required only by the message, so the trait is not embedded
+		Validations:  asyncapi.Validations[asyncapiv3.Schema]{Required: []string{"span"}},
+		TraitHeaders: []*asyncapiv3.Schema{traced},
+	}
+	traced.Properties["override"] = &asyncapiv3.Schema{Name: "OverrideSchema", Type: "object"}
+
+	suite.Require().Equal([]*asyncapiv3.Schema{override, span}, GetChildrenObjectSchemas(headers))
+
+	// The trait children are generated with the trait headers
+	UseTraitMixins()
+	defer func() { traitMixins = false }()
+	suite.Require().Empty(TraitMixinSchemas(headers))
+	suite.Require().Equal([]*asyncapiv3.Schema{override}, GetChildrenObjectSchemas(headers))
+}
+
+func (suite *HelpersSuite) TestRandomConstraints() {
+	suite.Require().Equal("extensions.RandomConstraints{}", RandomConstraints(asyncapiv3.Schema{}))
+
+	s := asyncapiv3.Schema{Type: "string", Format: "email"}
+	s.MinLength, s.MaxLength, s.Pattern = 2, 8, `^a\d$`
+	suite.Require().Equal(
+		`extensions.RandomConstraints{MinLength: 2, MaxLength: 8, HasMaxLength: true, Pattern: "^a\\d$", Format: "email"}`,
+		RandomConstraints(s))
+
+	s = asyncapiv3.Schema{Type: "number", Format: "float"}
+	s.ExclusiveMinimum, s.Maximum = 0.5, 1e6
+	suite.Require().Equal(
+		"extensions.RandomConstraints{Maximum: 1e+06, HasMaximum: true, ExclusiveMinimum: 0.5, HasExclusiveMinimum: true}",
+		RandomConstraints(s))
+
+	s = asyncapiv3.Schema{Type: "array"}
+	s.MinItems, s.UniqueItems = 1, true
+	suite.Require().Equal("extensions.RandomConstraints{MinItems: 1, UniqueItems: true}", RandomConstraints(s))
+
+	// The bounds explicitly set to zero
+	s = asyncapiv3.Schema{Type: "integer", ZeroBounds: []string{"maximum", "maxItems"}}
+	suite.Require().Equal(
+		"extensions.RandomConstraints{Maximum: 0, HasMaximum: true, MaxItems: 0, HasMaxItems: true}",
+		RandomConstraints(s))
+}
+
+func (suite *HelpersSuite) TestTypesRef() {
+	suite.Require().Equal("OrderMessage", TypesRef("OrderMessage"))
+	suite.Require().Equal("toBrokerMessage", TypesMethod("toBrokerMessage"))
+
+	suite.Require().NoError(SetTypesPackage("github.com/me/project/common-types"))
+	defer func() { typesPackage = "" }()
+	suite.Require().Equal("commontypes.OrderMessage", TypesRef("OrderMessage"))
+	suite.Require().Equal("commontypes.BrokerMessageToOrderMessage", TypesRef("brokerMessageToOrderMessage"))
+	suite.Require().Equal("ToBrokerMessage", TypesMethod("toBrokerMessage"))
+
+	// No name can be deduced from the import path
+	suite.Require().Error(SetTypesPackage("github.com/me/project/1"))
 }
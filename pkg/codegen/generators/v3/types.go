@@ -2,6 +2,7 @@ package generatorv3
 
 import (
 	"bytes"
+	"slices"
 
 	asyncapi "github.com/lerenn/asyncapi-codegen/pkg/asyncapi/v3"
 )
@@ -10,6 +11,40 @@ import (
 // contained in an asyncapi specification to golang structures code.
 type TypesGenerator struct {
 	asyncapi.Specification
+
+	// Sections are the sections of the types that will be generated (see
+	// TypesSections). All sections are generated if there is none.
+	Sections []string
+}
+
+const (
+	// TypesSectionTypes is the section of the types containing the schemas.
+	TypesSectionTypes = "types"
+	// TypesSectionController is the section of the types containing the
+	// controller shared by the application and user controllers, and its options.
+	TypesSectionController = "controller"
+	// TypesSectionMessages is the section of the types containing the messages.
+	TypesSectionMessages = "messages"
+	// TypesSectionErrors is the section of the types containing the errors.
+	TypesSectionErrors = "errors"
+	// TypesSectionChannels is the section of the types containing the channels
+	// parameters and paths.
+	TypesSectionChannels = "channels"
+)
+
+// TypesSections are the sections of the types, in the order of the files
+// generated when splitting them.
+var TypesSections = []string{
+	TypesSectionTypes,
+	TypesSectionController,
+	TypesSectionMessages,
+	TypesSectionErrors,
+	TypesSectionChannels,
+}
+
+// HasSection returns true if the section of the types should be generated.
+func (tg TypesGenerator) HasSection(section string) bool {
+	return len(tg.Sections) == 0 || slices.Contains(tg.Sections, section)
 }
 
 // Generate will create a new types code generator.
@@ -18,10 +53,16 @@ func (tg TypesGenerator) Generate() (string, error) {
 		typesTemplatePath,
 		schemaDefinitionTemplatePath,
 		schemaNameTemplatePath,
+		schemaDefaultsTemplatePath,
+		randomTemplatePath,
 		messageTemplatePath,
 
 		marshalingAdditionalPropertiesTemplatePath,
 		marshalingTimeTemplatePath,
+		marshalingTextTemplatePath,
+		marshalingNullableTemplatePath,
+		marshalingEnumTemplatePath,
+		marshalingUnionTemplatePath,
 	)
 	if err != nil {
 		return "", err
@@ -33,9 +33,81 @@ type Options struct {
 	// Supported values: camel, none
 	NamingScheme string
 
-	// IgnoreStringFormat states whether the properties' format (date, date-time) should impact the type in types
+	// IgnoreStringFormat states whether the properties' format (date, date-time,
+	// uuid, etc) should impact the type in types
 	IgnoreStringFormat bool
 
+	// IgnoreStringFormats are the properties' formats that should not impact
+	// the type in types, while the other formats still do.
+	IgnoreStringFormats []string
+
 	// ForcePointers can be used to force all struct fields to be generated as pointers
 	ForcePointers bool
+
+	// UseNullable generates the optional and nullable struct fields with the
+	// 'extensions.Nullable' wrapper instead of pointers (AsyncAPI v3 only), in
+	// order to distinguish absent fields from null fields.
+	UseNullable bool
+
+	// CloudEvents defines the CloudEvents content mode of generated messages
+	// (AsyncAPI v3 only). Supported values: binary, structured, or empty to disable.
+	CloudEvents string
+
+	// AllowUnknownEnums disables the rejection of unknown enum values when
+	// unmarshaling (AsyncAPI v3 only), for forward compatibility.
+	AllowUnknownEnums bool
+
+	// StrictAdditionalProperties rejects the unknown properties when unmarshaling
+	// objects with 'additionalProperties: false' (AsyncAPI v3 only).
+	StrictAdditionalProperties bool
+
+	// PlainMaps generates the objects that only have additional properties as
+	// plain golang maps instead of structs (AsyncAPI v3 only).
+	PlainMaps bool
+
+	// EscapeChannelParameters escapes the separators and wildcards of the
+	// brokers in the channel parameters values, when building the channel
+	// addresses (AsyncAPI v3 only).
+	EscapeChannelParameters bool
+
+	// AvroConfluent (un)marshals the Avro payloads with the Confluent wire
+	// format, i.e. prefixed with a magic byte and the schema ID (AsyncAPI v3 only).
+	AvroConfluent bool
+
+	// ProtobufGoTypes references the types generated by protoc-gen-go in the
+	// 'go_package' of the protobuf files, instead of generating the types of the
+	// protobuf payloads (AsyncAPI v3 only).
+	ProtobufGoTypes bool
+
+	// TraitMixins generates the headers of the message traits from the
+	// components as types embedded in the headers of the messages using them,
+	// instead of copying their fields into each message (AsyncAPI v3 only).
+	TraitMixins bool
+
+	// Examples generates the messages examples from the specification, and a
+	// test checking them against the generated types, in files next to the
+	// generated code (AsyncAPI v3 only).
+	Examples bool
+
+	// RandomMessages generates functions creating random messages that are
+	// valid against the schemas, from a source of randomness that can be
+	// seeded (AsyncAPI v3 only).
+	RandomMessages bool
+
+	// FakeControllers generates the interfaces of the controllers and their
+	// fake implementations, recording the calls and injecting the received
+	// messages, to test the code using them (AsyncAPI v3 only).
+	FakeControllers bool
+
+	// SplitFiles writes the generated code into several files next to the
+	// output path, one for each part of the code (e.g. 'asyncapi_app.gen.go',
+	// 'asyncapi_types.gen.go' for 'asyncapi.gen.go') instead of a single file
+	// (AsyncAPI v3 only).
+	SplitFiles bool
+
+	// TypesPackage is the import path of the package containing the types,
+	// when they are generated in their own package (AsyncAPI v3 only). It
+	// should be set both when generating the types in this package and when
+	// generating the application or user code importing it.
+	TypesPackage string
 }
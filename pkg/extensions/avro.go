@@ -0,0 +1,758 @@
+package extensions
+
+import (
+	"context"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/registry"
+)
+
+const (
+	// AvroConfluentMagicByte is the first byte of a payload in the Confluent
+	// wire format, followed by the schema ID and the Avro binary data.
+	AvroConfluentMagicByte byte = 0
+	// avroConfluentHeaderSize is the size of the magic byte and schema ID in
+	// the Confluent wire format.
+	avroConfluentHeaderSize = 5
+)
+
+// avroAPI encodes the arrays and maps without the size of their blocks in
+// bytes, as it is optional and not supported by all the decoders.
+var avroAPI = avro.Config{DisableBlockSizeHeader: true}.Freeze()
+
+// AvroSchema is a parsed Avro schema, used by the generated code to marshal
+// and unmarshal payloads with the Avro binary encoding.
+type AvroSchema struct {
+	schema avro.Schema
+}
+
+// ParseAvroSchema parses an Avro schema from its JSON representation.
+func ParseAvroSchema(schema string) (*AvroSchema, error) {
+	// Use a cache per schema, as the named types are identified by their full
+	// name only inside the schema where they are defined
+	s, err := avro.ParseWithCache(schema, "", &avro.SchemaCache{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAvro, err)
+	}
+
+	return &AvroSchema{schema: s}, nil
+}
+
+// MustParseAvroSchema parses an Avro schema from its JSON representation and
+// panics if it is invalid. It is used by the generated code, where the schemas
+// come from the specification.
+func MustParseAvroSchema(schema string) *AvroSchema {
+	s, err := ParseAvroSchema(schema)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// FullName returns the full name of the schema (e.g. 'com.example.User') if
+// it is a named type, or the name of its type otherwise.
+func (s *AvroSchema) FullName() string {
+	return avroTypeName(s.schema)
+}
+
+// String returns the canonical form of the schema.
+func (s *AvroSchema) String() string {
+	return s.schema.String()
+}
+
+// MarshalAvro marshals a value with the Avro binary encoding of the given
+// schema. Records are generated structs whose fields are matched with their
+// JSON names, and unions with several non-null types are generated union
+// structs whose fields are the variants in the same order.
+func MarshalAvro(schema *AvroSchema, v any) ([]byte, error) {
+	generic, err := toAvroGeneric(schema.schema, reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := avroAPI.Marshal(schema.schema, generic)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAvro, err)
+	}
+
+	return b, nil
+}
+
+// UnmarshalAvro unmarshals data with the Avro binary encoding of the given
+// schema into the value pointed by v.
+func UnmarshalAvro(schema *AvroSchema, data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("%w: expected a non-nil pointer", ErrInvalidAvro)
+	}
+
+	r := avro.NewReader(nil, 0, avro.WithReaderConfig(avroAPI)).Reset(data)
+	generic := r.ReadNext(schema.schema)
+	if r.Error != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAvro, r.Error)
+	}
+
+	// Check that there is no data left, as it would be read with another schema
+	if r.Peek(); r.Error == nil {
+		return fmt.Errorf("%w: unexpected trailing bytes", ErrInvalidAvro)
+	}
+
+	return fromAvroGeneric(schema.schema, generic, rv.Elem())
+}
+
+// ConfluentWireFormat prefixes Avro binary data with the magic byte and the
+// schema ID of the Confluent wire format.
+func ConfluentWireFormat(schemaID int32, data []byte) []byte {
+	b := make([]byte, avroConfluentHeaderSize, avroConfluentHeaderSize+len(data))
+	b[0] = AvroConfluentMagicByte
+	binary.BigEndian.PutUint32(b[1:], uint32(schemaID))
+	return append(b, data...)
+}
+
+// ParseConfluentWireFormat returns the schema ID and the Avro binary data of
+// a payload in the Confluent wire format.
+func ParseConfluentWireFormat(data []byte) (int32, []byte, error) {
+	if len(data) < avroConfluentHeaderSize || data[0] != AvroConfluentMagicByte {
+		return 0, nil, fmt.Errorf("%w: invalid Confluent wire format", ErrInvalidAvro)
+	}
+	return int32(binary.BigEndian.Uint32(data[1:])), data[avroConfluentHeaderSize:], nil
+}
+
+// AvroSchemaRegistry gives the IDs of the Avro schemas in a schema registry,
+// sent with the payloads in the Confluent wire format.
+type AvroSchemaRegistry interface {
+	AvroSchemaID(schema *AvroSchema) (int32, error)
+}
+
+var (
+	avroSchemaRegistryMutex sync.RWMutex
+	avroSchemaRegistry      AvroSchemaRegistry
+)
+
+// SetAvroSchemaRegistry sets the schema registry used by the generated code to
+// get the ID of the Avro schemas, when the messages are sent in the Confluent
+// wire format without an explicit schema ID.
+func SetAvroSchemaRegistry(registry AvroSchemaRegistry) {
+	avroSchemaRegistryMutex.Lock()
+	defer avroSchemaRegistryMutex.Unlock()
+
+	avroSchemaRegistry = registry
+}
+
+// AvroSchemaIDOf returns the ID of the Avro schema from the schema registry
+// set with SetAvroSchemaRegistry.
+func AvroSchemaIDOf(schema *AvroSchema) (int32, error) {
+	avroSchemaRegistryMutex.RLock()
+	defer avroSchemaRegistryMutex.RUnlock()
+
+	if avroSchemaRegistry == nil {
+		return 0, fmt.Errorf("%w: no schema registry set to get the ID of %q", ErrInvalidAvro, schema.FullName())
+	}
+	return avroSchemaRegistry.AvroSchemaID(schema)
+}
+
+// StaticAvroSchemaRegistry is an AvroSchemaRegistry with fixed IDs, set by
+// full name of the schemas (e.g. 'com.example.User').
+type StaticAvroSchemaRegistry map[string]int32
+
+// AvroSchemaID returns the ID set for the full name of the schema.
+func (r StaticAvroSchemaRegistry) AvroSchemaID(schema *AvroSchema) (int32, error) {
+	id, ok := r[schema.FullName()]
+	if !ok {
+		return 0, fmt.Errorf("%w: no schema ID for %q", ErrInvalidAvro, schema.FullName())
+	}
+	return id, nil
+}
+
+// ConfluentAvroSchemaRegistry is an AvroSchemaRegistry getting the IDs from a
+// Confluent schema registry, registering the schemas if needed. The IDs are
+// cached, so the schema registry is only requested once per schema.
+type ConfluentAvroSchemaRegistry struct {
+	client  registry.Registry
+	subject func(schema *AvroSchema) string
+	timeout time.Duration
+	ids     sync.Map
+}
+
+// ConfluentAvroSchemaRegistryOption is an option of ConfluentAvroSchemaRegistry.
+type ConfluentAvroSchemaRegistryOption func(r *ConfluentAvroSchemaRegistry)
+
+// WithAvroSubject sets the function returning the subject of the schemas in
+// the schema registry. The default is the full name of the schema (i.e. the
+// record name strategy).
+func WithAvroSubject(subject func(schema *AvroSchema) string) ConfluentAvroSchemaRegistryOption {
+	return func(r *ConfluentAvroSchemaRegistry) {
+		r.subject = subject
+	}
+}
+
+// WithAvroRegistryTimeout sets the timeout of the requests to the schema
+// registry. The default is 10 seconds.
+func WithAvroRegistryTimeout(timeout time.Duration) ConfluentAvroSchemaRegistryOption {
+	return func(r *ConfluentAvroSchemaRegistry) {
+		r.timeout = timeout
+	}
+}
+
+// NewConfluentAvroSchemaRegistry creates a new ConfluentAvroSchemaRegistry
+// from a schema registry client (e.g. created with 'registry.NewClient' from
+// 'github.com/hamba/avro/v2/registry').
+func NewConfluentAvroSchemaRegistry(
+	client registry.Registry,
+	options ...ConfluentAvroSchemaRegistryOption,
+) *ConfluentAvroSchemaRegistry {
+	r := &ConfluentAvroSchemaRegistry{
+		client:  client,
+		subject: (*AvroSchema).FullName,
+		timeout: 10 * time.Second,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// AvroSchemaID returns the ID of the schema in the schema registry, registering
+// the schema under its subject if it is not already registered.
+func (r *ConfluentAvroSchemaRegistry) AvroSchemaID(schema *AvroSchema) (int32, error) {
+	subject := r.subject(schema)
+	key := subject + "/" + schema.String()
+	if id, ok := r.ids.Load(key); ok {
+		return id.(int32), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	id, _, err := r.client.CreateSchema(ctx, subject, schema.String())
+	if err != nil {
+		return 0, fmt.Errorf("%w: registering %q: %w", ErrInvalidAvro, subject, err)
+	}
+
+	r.ids.Store(key, int32(id))
+	return int32(id), nil
+}
+
+// nullableReflector is implemented by Nullable, in order to (un)marshal it
+// without knowing its type.
+type nullableReflector interface {
+	reflectValue() (reflect.Value, bool)
+}
+
+// nullableSetReflector is implemented by a pointer to Nullable, in order to
+// unmarshal it without knowing its type.
+type nullableSetReflector interface {
+	reflectSet() reflect.Value
+	SetNull()
+}
+
+var (
+	civilDateType = reflect.TypeOf(civil.Date{})
+	timeType      = reflect.TypeOf(time.Time{})
+	byteType      = reflect.TypeOf(byte(0))
+)
+
+// indirect returns the value pointed by pointers and set in Nullable, and
+// false if it is nil or not set.
+func indirect(v reflect.Value) (reflect.Value, bool) {
+	for {
+		switch {
+		case !v.IsValid():
+			return v, false
+		case v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface:
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		case v.Kind() == reflect.Struct && v.CanInterface():
+			n, ok := v.Interface().(nullableReflector)
+			if !ok {
+				return v, true
+			}
+			value, set := n.reflectValue()
+			if !set {
+				return v, false
+			}
+			v = value
+		default:
+			return v, true
+		}
+	}
+}
+
+// allocate allocates the pointers and sets the Nullable, and returns the
+// value that should be set.
+func allocate(v reflect.Value) reflect.Value {
+	for {
+		switch {
+		case v.Kind() == reflect.Pointer:
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		case v.Kind() == reflect.Struct && v.CanAddr():
+			n, ok := v.Addr().Interface().(nullableSetReflector)
+			if !ok {
+				return v
+			}
+			v = n.reflectSet()
+		default:
+			return v
+		}
+	}
+}
+
+// setNull sets the value to null, resetting the pointers and Nullable.
+func setNull(v reflect.Value) {
+	if v.CanAddr() {
+		if n, ok := v.Addr().Interface().(nullableSetReflector); ok {
+			n.SetNull()
+			return
+		}
+	}
+	v.Set(reflect.Zero(v.Type()))
+}
+
+// toAvroGeneric converts a value into the generic representation of the Avro
+// library for the given schema: maps for records, maps with the name of the
+// branch for unions, and native values for the other types.
+//
+//nolint:cyclop // Not necessary to split the Avro types
+func toAvroGeneric(s avro.Schema, v reflect.Value) (any, error) {
+	if ref, ok := s.(*avro.RefSchema); ok {
+		s = ref.Schema()
+	}
+	if u, ok := s.(*avro.UnionSchema); ok {
+		return toAvroUnion(u, v)
+	}
+
+	v, ok := indirect(v)
+	switch {
+	case s.Type() == avro.Null:
+		return nil, nil
+	case !ok:
+		return nil, fmt.Errorf("%w: missing value for %s", ErrInvalidAvro, avroTypeName(s))
+	}
+
+	switch s := s.(type) {
+	case *avro.RecordSchema:
+		if v.Kind() == reflect.Struct {
+			return toAvroRecord(s, v)
+		}
+	case *avro.EnumSchema:
+		if v.Kind() == reflect.String {
+			for _, sym := range s.Symbols() {
+				if sym == v.String() {
+					return sym, nil
+				}
+			}
+			return nil, fmt.Errorf("%w: %q is not a symbol of %s", ErrInvalidEnumValue, v.String(), s.FullName())
+		}
+	case *avro.ArraySchema:
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			items := make([]any, v.Len())
+			for i := range items {
+				item, err := toAvroGeneric(s.Items(), v.Index(i))
+				if err != nil {
+					return nil, err
+				}
+				items[i] = item
+			}
+			return items, nil
+		}
+	case *avro.MapSchema:
+		if v.Kind() == reflect.Struct {
+			v = v.FieldByName("AdditionalProperties")
+		}
+		if v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+			values := make(map[string]any, v.Len())
+			for it := v.MapRange(); it.Next(); {
+				value, err := toAvroGeneric(s.Values(), it.Value())
+				if err != nil {
+					return nil, err
+				}
+				values[it.Key().String()] = value
+			}
+			return values, nil
+		}
+	case *avro.FixedSchema:
+		if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.Len() != s.Size() {
+				return nil, fmt.Errorf("%w: expected %d bytes for %s, got %d", ErrInvalidAvro, s.Size(), s.FullName(), v.Len())
+			}
+			fixed := reflect.New(reflect.ArrayOf(s.Size(), byteType)).Elem()
+			reflect.Copy(fixed, v)
+			return fixed.Interface(), nil
+		}
+	default:
+		if generic, ok, err := toAvroPrimitive(s.Type(), v); ok || err != nil {
+			return generic, err
+		}
+	}
+
+	return nil, fmt.Errorf("%w: cannot encode %s as %s", ErrInvalidAvro, v.Type(), avroTypeName(s))
+}
+
+func toAvroUnion(s *avro.UnionSchema, v reflect.Value) (any, error) {
+	// Encode the null value
+	v, ok := indirect(v)
+	if !ok {
+		if !avroHasNull(s) {
+			return nil, fmt.Errorf("%w: missing value for %s", ErrInvalidAvro, avroTypeName(s))
+		}
+		return nil, nil
+	}
+
+	// Encode the only non-null branch
+	branches := avroBranches(s)
+	if len(branches) == 1 {
+		return toAvroBranch(branches[0], v)
+	}
+
+	// Encode the variant set in the union struct
+	if v.Kind() != reflect.Struct || v.NumField() != len(branches) {
+		return nil, fmt.Errorf("%w: cannot encode %s as %s", ErrInvalidAvro, v.Type(), avroTypeName(s))
+	}
+	for i, b := range branches {
+		if f, ok := indirect(v.Field(i)); ok {
+			return toAvroBranch(b, f)
+		}
+	}
+	return nil, fmt.Errorf("%w: no variant set for %s", ErrInvalidAvro, avroTypeName(s))
+}
+
+func toAvroBranch(s avro.Schema, v reflect.Value) (any, error) {
+	generic, err := toAvroGeneric(s, v)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{avroTypeName(s): generic}, nil
+}
+
+func toAvroRecord(s *avro.RecordSchema, v reflect.Value) (any, error) {
+	indexes := jsonFieldIndexes(v.Type())
+
+	record := make(map[string]any, len(s.Fields()))
+	for _, f := range s.Fields() {
+		i, ok := indexes[f.Name()]
+		if !ok {
+			return nil, fmt.Errorf("%w: no field %q in %s", ErrInvalidAvro, f.Name(), v.Type())
+		}
+
+		value, err := toAvroGeneric(f.Type(), v.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		record[f.Name()] = value
+	}
+
+	return record, nil
+}
+
+//nolint:cyclop // Not necessary to split the Avro types
+func toAvroPrimitive(typ avro.Type, v reflect.Value) (any, bool, error) {
+	switch {
+	case v.Type() == civilDateType:
+		return v.Interface().(civil.Date).In(time.UTC), true, nil
+	case v.Type() == timeType:
+		return v.Interface(), true, nil
+	}
+
+	switch typ {
+	case avro.Boolean:
+		return v.Bool(), v.Kind() == reflect.Bool, nil
+	case avro.Int:
+		switch {
+		case v.CanInt():
+			return int(v.Int()), true, nil
+		case v.CanUint():
+			return int(v.Uint()), true, nil
+		}
+	case avro.Long:
+		switch {
+		case v.CanInt():
+			return v.Int(), true, nil
+		case v.CanUint():
+			return int64(v.Uint()), true, nil
+		}
+	case avro.Float:
+		if v.CanFloat() {
+			return float32(v.Float()), true, nil
+		}
+	case avro.Double:
+		if v.CanFloat() {
+			return v.Float(), true, nil
+		}
+	case avro.String, avro.Bytes:
+		text, ok, err := textOf(v)
+		if !ok || err != nil {
+			return nil, false, err
+		}
+		if typ == avro.String {
+			return string(text), true, nil
+		}
+		return text, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// fromAvroGeneric sets a value from the generic representation of the Avro
+// library for the given schema.
+//
+//nolint:cyclop,gocognit // Not necessary to split the Avro types
+func fromAvroGeneric(s avro.Schema, generic any, v reflect.Value) error {
+	if ref, ok := s.(*avro.RefSchema); ok {
+		s = ref.Schema()
+	}
+	if generic == nil {
+		setNull(v)
+		return nil
+	}
+	if u, ok := s.(*avro.UnionSchema); ok {
+		return fromAvroUnion(u, generic, v)
+	}
+	v = allocate(v)
+
+	switch s := s.(type) {
+	case *avro.RecordSchema:
+		record, ok := generic.(map[string]any)
+		if !ok || v.Kind() != reflect.Struct {
+			break
+		}
+		indexes := jsonFieldIndexes(v.Type())
+		for _, f := range s.Fields() {
+			i, ok := indexes[f.Name()]
+			if !ok {
+				return fmt.Errorf("%w: no field %q in %s", ErrInvalidAvro, f.Name(), v.Type())
+			}
+			if err := fromAvroGeneric(f.Type(), record[f.Name()], v.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *avro.EnumSchema:
+		if sym, ok := generic.(string); ok && v.Kind() == reflect.String {
+			v.SetString(sym)
+			return nil
+		}
+	case *avro.ArraySchema:
+		items, ok := generic.([]any)
+		if !ok || v.Kind() != reflect.Slice {
+			break
+		}
+		v.Set(reflect.MakeSlice(v.Type(), len(items), len(items)))
+		for i, item := range items {
+			if err := fromAvroGeneric(s.Items(), item, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *avro.MapSchema:
+		if v.Kind() == reflect.Struct {
+			v = v.FieldByName("AdditionalProperties")
+		}
+		values, ok := generic.(map[string]any)
+		if !ok || v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		v.Set(reflect.MakeMapWithSize(v.Type(), len(values)))
+		for k, value := range values {
+			e := reflect.New(v.Type().Elem()).Elem()
+			if err := fromAvroGeneric(s.Values(), value, e); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), e)
+		}
+		return nil
+	case *avro.FixedSchema:
+		fixed := reflect.ValueOf(generic)
+		switch {
+		case fixed.Kind() != reflect.Array:
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			v.Set(reflect.MakeSlice(v.Type(), fixed.Len(), fixed.Len()))
+			reflect.Copy(v, fixed)
+			return nil
+		case v.Kind() == reflect.Array && v.Len() == fixed.Len() && v.Type().Elem().Kind() == reflect.Uint8:
+			reflect.Copy(v, fixed)
+			return nil
+		}
+	default:
+		if ok, err := fromAvroPrimitive(generic, v); ok || err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: cannot decode %s into %s", ErrInvalidAvro, avroTypeName(s), v.Type())
+}
+
+func fromAvroUnion(s *avro.UnionSchema, generic any, v reflect.Value) error {
+	// Get the branch from its name
+	m, ok := generic.(map[string]any)
+	if !ok || len(m) != 1 {
+		return fmt.Errorf("%w: cannot decode %s into %s", ErrInvalidAvro, avroTypeName(s), v.Type())
+	}
+	var name string
+	var value any
+	for k, val := range m {
+		name, value = k, val
+	}
+
+	branches := avroBranches(s)
+	for i, b := range branches {
+		if avroTypeName(b) != name {
+			continue
+		}
+
+		// Decode the only non-null branch
+		if len(branches) == 1 {
+			return fromAvroGeneric(b, value, v)
+		}
+
+		// Decode the variant in the union struct
+		v = allocate(v)
+		if v.Kind() != reflect.Struct || v.NumField() != len(branches) {
+			break
+		}
+		v.Set(reflect.Zero(v.Type()))
+		return fromAvroGeneric(b, value, v.Field(i))
+	}
+
+	return fmt.Errorf("%w: cannot decode %s into %s", ErrInvalidAvro, name, v.Type())
+}
+
+//nolint:cyclop // Not necessary to split the native types
+func fromAvroPrimitive(generic any, v reflect.Value) (bool, error) {
+	g := reflect.ValueOf(generic)
+	switch {
+	case g.Type() == timeType && v.Type() == civilDateType:
+		v.Set(reflect.ValueOf(civil.DateOf(generic.(time.Time))))
+	case g.Type() == timeType && v.Type() == timeType:
+		v.Set(g)
+	case g.Kind() == reflect.Bool && v.Kind() == reflect.Bool:
+		v.SetBool(g.Bool())
+	case g.CanInt() && v.CanInt():
+		v.SetInt(g.Int())
+	case g.CanInt() && v.CanUint():
+		v.SetUint(uint64(g.Int()))
+	case g.CanFloat() && v.CanFloat():
+		v.SetFloat(g.Float())
+	case g.Kind() == reflect.String:
+		return setText([]byte(g.String()), v)
+	case g.Kind() == reflect.Slice && g.Type().Elem().Kind() == reflect.Uint8:
+		return setText(g.Bytes(), v)
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// textOf returns the text of strings, byte slices and text marshalers, and
+// false for the other values.
+func textOf(v reflect.Value) ([]byte, bool, error) {
+	switch {
+	case v.Kind() == reflect.String:
+		return []byte(v.String()), true, nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return v.Bytes(), true, nil
+	case v.CanInterface():
+		m, ok := v.Interface().(encoding.TextMarshaler)
+		if !ok {
+			return nil, false, nil
+		}
+		text, err := m.MarshalText()
+		return text, true, err
+	default:
+		return nil, false, nil
+	}
+}
+
+// setText sets strings, byte slices and text unmarshalers from a text, and
+// returns false for the other values.
+func setText(text []byte, v reflect.Value) (bool, error) {
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(string(text))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		v.SetBytes(append([]byte(nil), text...))
+	case v.CanAddr():
+		u, ok := v.Addr().Interface().(encoding.TextUnmarshaler)
+		if !ok {
+			return false, nil
+		}
+		return true, u.UnmarshalText(text)
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// avroTypeName returns the name identifying a type in a union: the full name
+// of the named types, and the type followed by the logical type if there is
+// one for the other types.
+func avroTypeName(s avro.Schema) string {
+	if ref, ok := s.(*avro.RefSchema); ok {
+		s = ref.Schema()
+	}
+	if n, ok := s.(avro.NamedSchema); ok {
+		return n.FullName()
+	}
+
+	name := string(s.Type())
+	if l, ok := s.(avro.LogicalTypeSchema); ok && l.Logical() != nil {
+		name += "." + string(l.Logical().Type())
+	}
+	return name
+}
+
+// avroHasNull checks if the union has a null branch.
+func avroHasNull(s *avro.UnionSchema) bool {
+	for _, t := range s.Types() {
+		if t.Type() == avro.Null {
+			return true
+		}
+	}
+	return false
+}
+
+// avroBranches returns the non-null branches of a union.
+func avroBranches(s *avro.UnionSchema) []avro.Schema {
+	branches := make([]avro.Schema, 0, len(s.Types()))
+	for _, t := range s.Types() {
+		if t.Type() != avro.Null {
+			branches = append(branches, t)
+		}
+	}
+	return branches
+}
+
+var jsonFieldIndexesCache sync.Map
+
+// jsonFieldIndexes returns the indexes of the struct fields by their JSON name.
+func jsonFieldIndexes(t reflect.Type) map[string]int {
+	if indexes, ok := jsonFieldIndexesCache.Load(t); ok {
+		return indexes.(map[string]int)
+	}
+
+	indexes := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		if name != "-" {
+			indexes[name] = i
+		}
+	}
+
+	jsonFieldIndexesCache.Store(t, indexes)
+	return indexes
+}
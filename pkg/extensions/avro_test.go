@@ -0,0 +1,247 @@
+package extensions
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/google/uuid"
+	"github.com/hamba/avro/v2/registry"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestAvroSuite(t *testing.T) {
+	suite.Run(t, new(AvroSuite))
+}
+
+type AvroSuite struct {
+	suite.Suite
+}
+
+const avroTestSchema = `{
+	"type": "record",
+	"name": "User",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "id", "type": {"type": "string", "logicalType": "uuid"}},
+		{"name": "name", "type": "string"},
+		{"name": "age", "type": "int"},
+		{"name": "score", "type": "double"},
+		{"name": "active", "type": "boolean"},
+		{"name": "email", "type": ["null", "string"]},
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["ACTIVE", "BANNED"]}},
+		{"name": "tags", "type": {"type": "array", "items": "string"}},
+		{"name": "counters", "type": {"type": "map", "values": "long"}},
+		{"name": "birthday", "type": {"type": "int", "logicalType": "date"}},
+		{"name": "createdAt", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "hash", "type": {"type": "fixed", "name": "Hash", "size": 2}},
+		{"name": "contact", "type": ["null", "string", "long"]},
+		{"name": "friend", "type": ["null", "User"]}
+	]
+}`
+
+type avroTestStatus string
+
+type avroTestContact struct {
+	String *string
+	Long   *int64
+}
+
+type avroTestUser struct {
+	Id        uuid.UUID         `json:"id"`
+	Name      string            `json:"name"`
+	Age       int32             `json:"age"`
+	Score     float64           `json:"score"`
+	Active    bool              `json:"active"`
+	Email     *string           `json:"email,omitempty"`
+	Status    avroTestStatus    `json:"status"`
+	Tags      []string          `json:"tags,omitempty"`
+	Counters  map[string]int64  `json:"counters"`
+	Birthday  civil.Date        `json:"birthday"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Hash      []byte            `json:"hash"`
+	Contact   *avroTestContact  `json:"contact,omitempty"`
+	Friend    *avroTestUser     `json:"friend,omitempty"`
+	Ignored   map[string]string `json:"-"`
+}
+
+func (suite *AvroSuite) TestRoundTrip() {
+	schema, err := ParseAvroSchema(avroTestSchema)
+	suite.Require().NoError(err)
+
+	email, phone := "bob@example.com", int64(123456)
+	friend := avroTestUser{
+		Id:        uuid.New(),
+		Name:      "alice",
+		Status:    "ACTIVE",
+		Tags:      []string{},
+		Counters:  map[string]int64{},
+		Birthday:  civil.Date{Year: 1965, Month: time.March, Day: 3},
+		CreatedAt: time.UnixMilli(0).UTC(),
+		Hash:      []byte{0, 0},
+	}
+	user := avroTestUser{
+		Id:        uuid.New(),
+		Name:      "bob",
+		Age:       42,
+		Score:     -1.5,
+		Active:    true,
+		Email:     &email,
+		Status:    "BANNED",
+		Tags:      []string{"a", "b"},
+		Counters:  map[string]int64{"x": 1, "y": -300},
+		Birthday:  civil.Date{Year: 2000, Month: time.February, Day: 29},
+		CreatedAt: time.UnixMilli(1700000000123).UTC(),
+		Hash:      []byte{0xca, 0xfe},
+		Contact:   &avroTestContact{Long: &phone},
+		Friend:    &friend,
+	}
+
+	b, err := MarshalAvro(schema, user)
+	suite.Require().NoError(err)
+
+	var decoded avroTestUser
+	suite.Require().NoError(UnmarshalAvro(schema, b, &decoded))
+	suite.Require().Equal(user, decoded)
+}
+
+func (suite *AvroSuite) TestEncoding() {
+	// Values from the Avro specification
+	schema := MustParseAvroSchema(`{"type": "record", "name": "test", "fields": [
+		{"name": "a", "type": "long"},
+		{"name": "b", "type": "string"},
+		{"name": "c", "type": ["null", "string"]},
+		{"name": "d", "type": {"type": "array", "items": "long"}}
+	]}`)
+	v := struct {
+		A int64   `json:"a"`
+		B string  `json:"b"`
+		C *string `json:"c"`
+		D []int64 `json:"d"`
+	}{A: -64, B: "foo", D: []int64{3, 27}}
+
+	b, err := MarshalAvro(schema, v)
+	suite.Require().NoError(err)
+	suite.Require().Equal([]byte{0x7f, 0x06, 0x66, 0x6f, 0x6f, 0x00, 0x04, 0x06, 0x36, 0x00}, b)
+}
+
+func (suite *AvroSuite) TestNullable() {
+	schema := MustParseAvroSchema(`{"type": "record", "name": "test", "fields": [
+		{"name": "a", "type": ["null", "string"]},
+		{"name": "b", "type": ["string", "null"]}
+	]}`)
+	type record struct {
+		A Nullable[string] `json:"a"`
+		B Nullable[string] `json:"b"`
+	}
+
+	b, err := MarshalAvro(schema, record{A: NewNullable("x")})
+	suite.Require().NoError(err)
+
+	var decoded record
+	suite.Require().NoError(UnmarshalAvro(schema, b, &decoded))
+	suite.Require().Equal(NewNullable("x"), decoded.A)
+	suite.Require().True(decoded.B.IsNull())
+}
+
+func (suite *AvroSuite) TestErrors() {
+	_, err := ParseAvroSchema(`{"type": "record", "name": "test", "fields": [{"name": "a", "type": "Unknown"}]}`)
+	suite.Require().ErrorIs(err, ErrInvalidAvro)
+
+	schema := MustParseAvroSchema(`{"type": "enum", "name": "Status", "symbols": ["A", "B"]}`)
+	_, err = MarshalAvro(schema, "C")
+	suite.Require().ErrorIs(err, ErrInvalidEnumValue)
+
+	var s string
+	suite.Require().ErrorIs(UnmarshalAvro(schema, []byte{0x02, 0x00}, &s), ErrInvalidAvro)
+	suite.Require().ErrorIs(UnmarshalAvro(MustParseAvroSchema(`"string"`), []byte{0x08, 'a'}, &s), ErrInvalidAvro)
+
+	var n int64
+	suite.Require().ErrorIs(UnmarshalAvro(MustParseAvroSchema(`"long"`), []byte{0x02}, n), ErrInvalidAvro)
+}
+
+func (suite *AvroSuite) TestConfluentWireFormat() {
+	b := ConfluentWireFormat(42, []byte{0x01, 0x02})
+	suite.Require().Equal([]byte{0x00, 0x00, 0x00, 0x00, 0x2a, 0x01, 0x02}, b)
+
+	id, data, err := ParseConfluentWireFormat(b)
+	suite.Require().NoError(err)
+	suite.Require().Equal(int32(42), id)
+	suite.Require().Equal([]byte{0x01, 0x02}, data)
+
+	_, _, err = ParseConfluentWireFormat([]byte{0x01, 0x00})
+	suite.Require().ErrorIs(err, ErrInvalidAvro)
+}
+
+func (suite *AvroSuite) TestNamespaces() {
+	// Types with the same name in different namespaces are different types
+	schema := MustParseAvroSchema(`{"type": "record", "name": "Order", "namespace": "com.shop", "fields": [
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["OPEN", "CLOSED"]}},
+		{"name": "payment", "type": {"type": "record", "name": "Payment", "namespace": "com.bank", "fields": [
+			{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["PAID"]}}
+		]}},
+		{"name": "previous", "type": ["null", "com.shop.Status"]}
+	]}`)
+	suite.Require().Equal("com.shop.Order", schema.FullName())
+
+	type payment struct {
+		Status string `json:"status"`
+	}
+	type order struct {
+		Status   string  `json:"status"`
+		Payment  payment `json:"payment"`
+		Previous *string `json:"previous"`
+	}
+	previous := "OPEN"
+	sent := order{Status: "CLOSED", Payment: payment{Status: "PAID"}, Previous: &previous}
+
+	b, err := MarshalAvro(schema, sent)
+	suite.Require().NoError(err)
+
+	var decoded order
+	suite.Require().NoError(UnmarshalAvro(schema, b, &decoded))
+	suite.Require().Equal(sent, decoded)
+}
+
+func (suite *AvroSuite) TestStaticSchemaRegistry() {
+	schema := MustParseAvroSchema(avroTestSchema)
+	defer SetAvroSchemaRegistry(nil)
+
+	_, err := AvroSchemaIDOf(schema)
+	suite.Require().ErrorIs(err, ErrInvalidAvro)
+
+	SetAvroSchemaRegistry(StaticAvroSchemaRegistry{"com.example.User": 42})
+	id, err := AvroSchemaIDOf(schema)
+	suite.Require().NoError(err)
+	suite.Require().Equal(int32(42), id)
+
+	_, err = AvroSchemaIDOf(MustParseAvroSchema(`{"type": "enum", "name": "Status", "symbols": ["A"]}`))
+	suite.Require().ErrorIs(err, ErrInvalidAvro)
+}
+
+func (suite *AvroSuite) TestConfluentSchemaRegistry() {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]int{"id": 7})
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(server.URL)
+	suite.Require().NoError(err)
+	r := NewConfluentAvroSchemaRegistry(client, WithAvroSubject(func(s *AvroSchema) string {
+		return "users-" + s.FullName()
+	}))
+
+	// The ID should be requested only once
+	schema := MustParseAvroSchema(avroTestSchema)
+	for i := 0; i < 2; i++ {
+		id, err := r.AvroSchemaID(schema)
+		suite.Require().NoError(err)
+		suite.Require().Equal(int32(7), id)
+	}
+	suite.Require().Equal([]string{"POST /subjects/users-com.example.User/versions"}, requests)
+}
@@ -3,6 +3,8 @@ package extensions
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
+	"time"
 )
 
 // BrokerChannelSubscription is a struct that contains every returned structures
@@ -68,10 +70,42 @@ func (bcs BrokerChannelSubscription) Cancel(ctx context.Context) {
 	}
 }
 
+const (
+	// MessageIDHeader is the header used by generated code to identify the message
+	// that has been sent on an operation that can carry multiple messages.
+	MessageIDHeader = Prefix + "message-id"
+	// ContentTypeHeader is the header containing the content type of the message.
+	ContentTypeHeader = "content-type"
+)
+
 // BrokerMessage is a wrapper that will contain all information regarding a message.
 type BrokerMessage struct {
 	Headers map[string][]byte
 	Payload []byte
+
+	// Metadata is set by the broker controller on received messages, and is
+	// ignored when publishing.
+	Metadata BrokerMessageMetadata
+}
+
+// BrokerMessageMetadata contains the information given by the broker about a
+// received message. The fields that are not supported by the broker are left
+// at their zero value.
+type BrokerMessageMetadata struct {
+	// Channel is the address on which the message has been received (e.g. the
+	// NATS subject or the Kafka topic), that can differ from the subscribed one
+	// when subscribing with wildcards.
+	Channel string
+	// Partition is the partition of the message (Kafka).
+	Partition int
+	// Offset is the position of the message in its partition (Kafka) or in
+	// its stream (NATS JetStream).
+	Offset int64
+	// Timestamp is the time at which the message has been stored by the broker.
+	Timestamp time.Time
+	// Redelivered indicates that the message has already been delivered, but
+	// has not been acknowledged (NATS JetStream).
+	Redelivered bool
 }
 
 // IsUninitialized check if the BrokerMessage is at zero value, i.e. the
@@ -98,11 +132,12 @@ func (bm BrokerMessage) String() string {
 // AcknowledgeableBrokerMessage is the struct that embeds BrokerMessage and
 // provide a BrokerAcknowledgment to acknowledge a message to the broker
 // depending on the implementation. AcknowledgeableBrokerMessage make sure that
-// only one acknowledgement is sent to the broker.
+// only one acknowledgement is sent to the broker, even if it is copied or
+// acknowledged concurrently (e.g. by a handler that timed out).
 type AcknowledgeableBrokerMessage struct {
 	BrokerMessage
 
-	acked          bool
+	acked          *atomic.Bool
 	acknowledgment BrokerAcknowledgment
 }
 
@@ -112,27 +147,40 @@ func NewAcknowledgeableBrokerMessage(
 	bm BrokerMessage,
 	acknowledgment BrokerAcknowledgment,
 ) AcknowledgeableBrokerMessage {
-	return AcknowledgeableBrokerMessage{BrokerMessage: bm, acknowledgment: acknowledgment}
+	return AcknowledgeableBrokerMessage{
+		BrokerMessage:  bm,
+		acked:          new(atomic.Bool),
+		acknowledgment: acknowledgment,
+	}
 }
 
 // Ack will call the AckMessage of the underlying BrokerAcknowledgment
 // implementation if the message was not already acked.
 func (bm *AcknowledgeableBrokerMessage) Ack() {
-	if !bm.acked {
+	if bm.setAcked() {
 		bm.acknowledgment.AckMessage()
-		bm.acked = true
 	}
 }
 
 // Nak will call the NakMessage of the underlying BrokerAcknowledgment
 // implementation if the message was not already acked.
 func (bm *AcknowledgeableBrokerMessage) Nak() {
-	if !bm.acked {
+	if bm.setAcked() {
 		bm.acknowledgment.NakMessage()
-		bm.acked = true
 	}
 }
 
+// setAcked marks the message as acked and returns true if it was not already.
+// The acknowledgment state is allocated by the constructor, so it is shared by
+// the copies: it panics on a message that has not been created with it.
+func (bm *AcknowledgeableBrokerMessage) setAcked() bool {
+	if bm.acked == nil {
+		panic("asyncapi: AcknowledgeableBrokerMessage should be created with NewAcknowledgeableBrokerMessage")
+	}
+
+	return bm.acked.CompareAndSwap(false, true)
+}
+
 // BrokerController represents the functions that should be implemented to connect
 // the broker to the application or the user.
 type BrokerController interface {
@@ -143,6 +191,17 @@ type BrokerController interface {
 	Subscribe(ctx context.Context, channel string) (BrokerChannelSubscription, error)
 }
 
+// WildcardBrokerController is a broker controller that can subscribe to all
+// the addresses of a channel with parameters, using the broker wildcards.
+type WildcardBrokerController interface {
+	BrokerController
+
+	// SubscribeWithWildcards subscribes to messages from all the addresses
+	// matching the channel address, where the parameters are between braces
+	// (e.g. 'users.{userId}.signup').
+	SubscribeWithWildcards(ctx context.Context, channel string) (BrokerChannelSubscription, error)
+}
+
 // BrokerAcknowledgment represents the function that should be implemented to acknowledge a
 // message from subscriber to the broker.
 // Some brokers may do not support naks so is it up to the broker implementation to handle naks correctly.
@@ -1,6 +1,8 @@
 package extensions
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -21,3 +23,53 @@ func (suite *BrokerSuite) TestIsUninitialized() {
 		Headers: make(map[string][]byte),
 	}.IsUninitialized())
 }
+
+type countAcknowledgment struct {
+	acks, naks int
+}
+
+func (ca *countAcknowledgment) AckMessage() { ca.acks++ }
+func (ca *countAcknowledgment) NakMessage() { ca.naks++ }
+
+func (suite *BrokerSuite) TestAcknowledgeOnlyOnce() {
+	var ack countAcknowledgment
+	msg := NewAcknowledgeableBrokerMessage(BrokerMessage{}, &ack)
+
+	// A copy should share the acknowledgment state
+	cp := msg
+	cp.Nak()
+	msg.Ack()
+	msg.Nak()
+
+	suite.Require().Equal(0, ack.acks)
+	suite.Require().Equal(1, ack.naks)
+}
+
+func (suite *BrokerSuite) TestAcknowledgeConcurrently() {
+	var acks atomic.Int32
+	msg := NewAcknowledgeableBrokerMessage(BrokerMessage{}, atomicAcknowledgment{acks: &acks})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(cp AcknowledgeableBrokerMessage) {
+			defer wg.Done()
+			cp.Ack()
+		}(msg)
+	}
+	wg.Wait()
+	suite.Require().Equal(int32(1), acks.Load())
+
+	// A message not created with the constructor can't be acknowledged
+	var zero AcknowledgeableBrokerMessage
+	suite.Require().PanicsWithValue(
+		"asyncapi: AcknowledgeableBrokerMessage should be created with NewAcknowledgeableBrokerMessage",
+		zero.Ack)
+}
+
+type atomicAcknowledgment struct {
+	acks *atomic.Int32
+}
+
+func (aa atomicAcknowledgment) AckMessage() { aa.acks.Add(1) }
+func (aa atomicAcknowledgment) NakMessage() {}
@@ -6,6 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"regexp"
+	"sync"
+	"time"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions/brokers"
@@ -14,7 +17,7 @@ import (
 )
 
 // Check that it still fills the interface.
-var _ extensions.BrokerController = (*Controller)(nil)
+var _ extensions.WildcardBrokerController = (*Controller)(nil)
 
 // Controller is the Kafka implementation for asyncapi-codegen.
 type Controller struct {
@@ -30,6 +33,9 @@ type Controller struct {
 
 	connectionTest bool
 
+	// Wildcard subscriptions only
+	topicsRefreshInterval time.Duration
+
 	logger extensions.Logger
 }
 
@@ -56,6 +62,8 @@ func NewController(hosts []string, options ...ControllerOption) (*Controller, er
 		maxBytes:       10e6, // 10MB
 		autoCommit:     true,
 		connectionTest: true,
+
+		topicsRefreshInterval: 10 * time.Second,
 	}
 
 	// Execute options
@@ -140,6 +148,14 @@ func WithConnectionTest(enabled bool) ControllerOption {
 	}
 }
 
+// WithTopicsRefreshInterval set the interval at which the wildcard subscriptions
+// look for the new topics matching their address.
+func WithTopicsRefreshInterval(interval time.Duration) ControllerOption {
+	return func(controller *Controller) {
+		controller.topicsRefreshInterval = interval
+	}
+}
+
 // Publish a message to the broker.
 func (c *Controller) Publish(ctx context.Context, channel string, um extensions.BrokerMessage) error {
 	// Create new writer
@@ -162,9 +178,14 @@ func (c *Controller) Publish(ctx context.Context, channel string, um extensions.
 		Headers: make([]kafka.Header, 0),
 	}
 
-	// Set message content and headers
+	// Set message content and headers, with the CloudEvents headers prefix
+	// of the Kafka binding if the message is a CloudEvent in binary mode
 	msg.Value = um.Payload
+	cloudEvents := usesCloudEventsHeaders(ctx)
 	for k, v := range um.Headers {
+		if cloudEvents {
+			k = extensions.CloudEventsHeaderToKafka(k)
+		}
 		msg.Headers = append(msg.Headers, kafka.Header{Key: k, Value: v})
 	}
 
@@ -210,19 +231,166 @@ func (c *Controller) Subscribe(ctx context.Context, channel string) (extensions.
 		Dialer:    c.dialer,
 	})
 
+	return c.subscribe(ctx, r), nil
+}
+
+// SubscribeWithWildcards subscribes to messages from all the existing topics
+// matching the channel address, converted to a regular expression.
+//
+// NOTE: this requires a group ID, and at least one matching topic. The topics
+// created after the subscription are looked up at the interval set with
+// WithTopicsRefreshInterval.
+func (c *Controller) SubscribeWithWildcards(
+	ctx context.Context,
+	channel string,
+) (extensions.BrokerChannelSubscription, error) {
+	if c.groupID == "" {
+		return extensions.BrokerChannelSubscription{},
+			fmt.Errorf("%w: a group ID is required to subscribe to multiple topics", extensions.ErrWildcardsNotSupported)
+	}
+
+	// Get the topics matching the channel address
+	r := regexp.MustCompile(extensions.ChannelAddressRegexp(channel))
+	topics, err := c.matchingTopics(r)
+	if err != nil {
+		return extensions.BrokerChannelSubscription{}, err
+	} else if len(topics) == 0 {
+		return extensions.BrokerChannelSubscription{},
+			fmt.Errorf("%w: no existing topic matching %q", extensions.ErrAsyncAPI, channel)
+	}
+
 	// Create subscription
 	sub := extensions.NewBrokerChannelSubscription(
 		make(chan extensions.AcknowledgeableBrokerMessage, brokers.BrokerMessagesQueueSize),
 		make(chan any, 1),
 	)
 
-	// Handle events
-	if c.autoCommit {
-		go autoCommitMessagesHandler(&c.logger)(ctx, r, sub)
-	} else {
-		go manualCommitMessagesHandler(&c.logger)(ctx, r, sub)
+	// Read the matching topics, then the new ones until the cancellation
+	readers := wildcardReaders{subscribed: make(map[string]bool)}
+	readers.add(topics, func(topics []string) *kafka.Reader {
+		return c.readGroupTopics(ctx, topics, sub)
+	})
+	stop := make(chan any)
+	go c.refreshMatchingTopics(ctx, r, &readers, sub, stop)
+
+	// Wait for cancellation and stop the kafka listeners when it happens
+	sub.WaitForCancellationAsync(func() {
+		close(stop)
+		for _, err := range readers.close() {
+			c.logger.Error(ctx, err.Error())
+		}
+	})
+
+	return sub, nil
+}
+
+// readGroupTopics creates a reader on the given topics, with the group ID, and
+// transmits its messages to the subscription.
+func (c *Controller) readGroupTopics(
+	ctx context.Context,
+	topics []string,
+	sub extensions.BrokerChannelSubscription,
+) *kafka.Reader {
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     c.hosts,
+		GroupTopics: topics,
+		MaxBytes:    c.maxBytes,
+		GroupID:     c.groupID,
+		Dialer:      c.dialer,
+	})
+	c.handleMessages(ctx, r, sub)
+	return r
+}
+
+// refreshMatchingTopics periodically adds a reader on the new topics matching
+// the regular expression, until the stop channel is closed.
+func (c *Controller) refreshMatchingTopics(
+	ctx context.Context,
+	r *regexp.Regexp,
+	readers *wildcardReaders,
+	sub extensions.BrokerChannelSubscription,
+	stop chan any,
+) {
+	ticker := time.NewTicker(c.topicsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		topics, err := c.matchingTopics(r)
+		if err != nil {
+			c.logger.Warning(ctx, fmt.Sprintf("Error when refreshing the topics matching %q: %q", r, err.Error()))
+			continue
+		}
+
+		readers.add(topics, func(topics []string) *kafka.Reader {
+			return c.readGroupTopics(ctx, topics, sub)
+		})
+	}
+}
+
+// wildcardReaders are the readers of a wildcard subscription, with the topics
+// that are already read.
+type wildcardReaders struct {
+	mutex      sync.Mutex
+	readers    []*kafka.Reader
+	subscribed map[string]bool
+	closed     bool
+}
+
+// add creates a reader for the topics that are not read yet, if any.
+func (wr *wildcardReaders) add(topics []string, newReader func(topics []string) *kafka.Reader) {
+	wr.mutex.Lock()
+	defer wr.mutex.Unlock()
+
+	if wr.closed {
+		return
 	}
 
+	var newTopics []string
+	for _, t := range topics {
+		if !wr.subscribed[t] {
+			wr.subscribed[t] = true
+			newTopics = append(newTopics, t)
+		}
+	}
+	if len(newTopics) == 0 {
+		return
+	}
+
+	wr.readers = append(wr.readers, newReader(newTopics))
+}
+
+// close closes all the readers and prevents new ones to be added.
+func (wr *wildcardReaders) close() []error {
+	wr.mutex.Lock()
+	defer wr.mutex.Unlock()
+
+	wr.closed = true
+
+	var errs []error
+	for _, r := range wr.readers {
+		if err := r.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (c *Controller) subscribe(ctx context.Context, r *kafka.Reader) extensions.BrokerChannelSubscription {
+	// Create subscription
+	sub := extensions.NewBrokerChannelSubscription(
+		make(chan extensions.AcknowledgeableBrokerMessage, brokers.BrokerMessagesQueueSize),
+		make(chan any, 1),
+	)
+
+	// Handle events
+	c.handleMessages(ctx, r, sub)
+
 	// Wait for cancellation and stop the kafka listener when it happens
 	sub.WaitForCancellationAsync(func() {
 		if err := r.Close(); err != nil {
@@ -230,7 +398,50 @@ func (c *Controller) Subscribe(ctx context.Context, channel string) (extensions.
 		}
 	})
 
-	return sub, nil
+	return sub
+}
+
+// handleMessages transmits the messages of the reader to the subscription, in
+// the background.
+func (c *Controller) handleMessages(ctx context.Context, r *kafka.Reader, sub extensions.BrokerChannelSubscription) {
+	if c.autoCommit {
+		go autoCommitMessagesHandler(&c.logger)(ctx, r, sub)
+	} else {
+		go manualCommitMessagesHandler(&c.logger)(ctx, r, sub)
+	}
+}
+
+func (c *Controller) matchingTopics(r *regexp.Regexp) ([]string, error) {
+	// Get connection to the first available host
+	var conn *kafka.Conn
+	var err error
+	for _, host := range c.hosts {
+		if conn, err = c.dialer.Dial("tcp", host); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Read partitions of all topics
+	partitions, err := conn.ReadPartitions()
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the topics matching, once each
+	var topics []string
+	found := make(map[string]bool)
+	for _, p := range partitions {
+		if !found[p.Topic] && r.MatchString(p.Topic) {
+			found[p.Topic] = true
+			topics = append(topics, p.Topic)
+		}
+	}
+
+	return topics, nil
 }
 
 func (c *Controller) checkTopicExistOrCreateIt(ctx context.Context, topic string) error {
@@ -295,22 +506,42 @@ func autoCommitMessagesHandler(
 			}
 
 			// Get headers
-			headers := make(map[string][]byte, len(msg.Headers))
-			for _, header := range msg.Headers {
-				headers[header.Key] = header.Value
-			}
+			headers := messageHeaders(ctx, msg)
 
 			// Send received message
 			sub.TransmitReceivedMessage(extensions.NewAcknowledgeableBrokerMessage(
 				extensions.BrokerMessage{
-					Headers: headers,
-					Payload: msg.Value,
+					Headers:  headers,
+					Payload:  msg.Value,
+					Metadata: messageMetadata(msg),
 				},
 				BrokerAcknowledgment{NoopCommit}))
 		}
 	}
 }
 
+// usesCloudEventsHeaders checks if the generated code expects the CloudEvents
+// attributes in the headers (binary mode), that are prefixed differently by the
+// Kafka binding.
+func usesCloudEventsHeaders(ctx context.Context) bool {
+	return ctx.Value(extensions.ContextKeyIsCloudEventsMode) == "binary"
+}
+
+// messageHeaders returns the headers of a received message, with the
+// CloudEvents headers prefix used by the generated code if it expects them.
+func messageHeaders(ctx context.Context, msg kafka.Message) map[string][]byte {
+	cloudEvents := usesCloudEventsHeaders(ctx)
+	headers := make(map[string][]byte, len(msg.Headers))
+	for _, header := range msg.Headers {
+		key := header.Key
+		if cloudEvents {
+			key = extensions.CloudEventsHeaderFromKafka(key)
+		}
+		headers[key] = header.Value
+	}
+	return headers
+}
+
 // manualCommitMessagesHandler provides a MessagesHandler with manual commit
 // the message is committed by user via the AcknowledgementHandler.
 func manualCommitMessagesHandler(
@@ -329,16 +560,14 @@ func manualCommitMessagesHandler(
 			}
 
 			// Get headers
-			headers := make(map[string][]byte, len(msg.Headers))
-			for _, header := range msg.Headers {
-				headers[header.Key] = header.Value
-			}
+			headers := messageHeaders(ctx, msg)
 
 			// Send received message
 			sub.TransmitReceivedMessage(extensions.NewAcknowledgeableBrokerMessage(
 				extensions.BrokerMessage{
-					Headers: headers,
-					Payload: msg.Value,
+					Headers:  headers,
+					Payload:  msg.Value,
+					Metadata: messageMetadata(msg),
 				},
 				BrokerAcknowledgment{doCommit: func() {
 					if err := r.CommitMessages(ctx, msg); err != nil {
@@ -350,6 +579,15 @@ func manualCommitMessagesHandler(
 	}
 }
 
+func messageMetadata(msg kafka.Message) extensions.BrokerMessageMetadata {
+	return extensions.BrokerMessageMetadata{
+		Channel:   msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Timestamp: msg.Time,
+	}
+}
+
 var _ extensions.BrokerAcknowledgment = (*BrokerAcknowledgment)(nil)
 
 // BrokerAcknowledgment for kafka broker.
@@ -1,10 +1,13 @@
 package kafka
 
 import (
+	"context"
 	"crypto/tls"
 	"testing"
 
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
 	testutil "github.com/lerenn/asyncapi-codegen/pkg/utils/test"
+	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/kafka-go/sasl/scram"
 	"github.com/stretchr/testify/assert"
 )
@@ -78,3 +81,21 @@ func TestSecureConnectionToKafka(t *testing.T) {
 			assert.NoError(t, err, "new connection to TLS secured kafka broker with TLS config and basic credentials should return no error") //nolint:lll
 		})
 }
+
+func TestMessageHeaders(t *testing.T) {
+	msg := kafka.Message{Headers: []kafka.Header{
+		{Key: "ce_id", Value: []byte("1234")},
+		{Key: "ce_x", Value: []byte("x")},
+	}}
+
+	t.Run("headers are unchanged without CloudEvents", func(t *testing.T) {
+		headers := messageHeaders(context.Background(), msg)
+		assert.Equal(t, map[string][]byte{"ce_id": []byte("1234"), "ce_x": []byte("x")}, headers)
+	})
+
+	t.Run("only CloudEvents attributes are mapped in binary mode", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), extensions.ContextKeyIsCloudEventsMode, "binary")
+		headers := messageHeaders(ctx, msg)
+		assert.Equal(t, map[string][]byte{extensions.CloudEventsHeaderID: []byte("1234"), "ce_x": []byte("x")}, headers)
+	})
+}
@@ -10,7 +10,7 @@ import (
 )
 
 // Check that it still fills the interface.
-var _ extensions.BrokerController = (*Controller)(nil)
+var _ extensions.WildcardBrokerController = (*Controller)(nil)
 
 // Controller is the Controller implementation for asyncapi-codegen.
 type Controller struct {
@@ -124,6 +124,15 @@ func (c *Controller) Subscribe(ctx context.Context, channel string) (extensions.
 	return sub, nil
 }
 
+// SubscribeWithWildcards subscribes to messages from all the addresses of the
+// channel, replacing the parameters by the '*' wildcard.
+func (c *Controller) SubscribeWithWildcards(
+	ctx context.Context,
+	channel string,
+) (extensions.BrokerChannelSubscription, error) {
+	return c.Subscribe(ctx, extensions.ChannelAddressWithWildcards(channel, ".", "*"))
+}
+
 func (c *Controller) messagesHandler(_ context.Context, sub extensions.BrokerChannelSubscription) nats.MsgHandler {
 	return func(msg *nats.Msg) {
 		// Get headers
@@ -139,6 +148,9 @@ func (c *Controller) messagesHandler(_ context.Context, sub extensions.BrokerCha
 			extensions.BrokerMessage{
 				Headers: headers,
 				Payload: msg.Data,
+				Metadata: extensions.BrokerMessageMetadata{
+					Channel: msg.Subject,
+				},
 			},
 			NoopAcknowledgementHandler{},
 		))
@@ -14,7 +14,7 @@ import (
 )
 
 // Check that it still fills the interface.
-var _ extensions.BrokerController = (*Controller)(nil)
+var _ extensions.WildcardBrokerController = (*Controller)(nil)
 
 // Controller is the Controller implementation for asyncapi-codegen.
 type Controller struct {
@@ -257,6 +257,15 @@ func (c *Controller) Subscribe(ctx context.Context, channel string) (extensions.
 	return sub, nil
 }
 
+// SubscribeWithWildcards subscribes to messages from all the addresses of the
+// channel, replacing the parameters by the '*' wildcard.
+func (c *Controller) SubscribeWithWildcards(
+	ctx context.Context,
+	channel string,
+) (extensions.BrokerChannelSubscription, error) {
+	return c.Subscribe(ctx, extensions.ChannelAddressWithWildcards(channel, ".", "*"))
+}
+
 // HandleMessage handles a message received from a stream.
 func (c *Controller) HandleMessage(ctx context.Context, msg jetstream.Msg, sub extensions.BrokerChannelSubscription) {
 	// Get headers
@@ -267,11 +276,22 @@ func (c *Controller) HandleMessage(ctx context.Context, msg jetstream.Msg, sub e
 		}
 	}
 
+	// Get metadata
+	metadata := extensions.BrokerMessageMetadata{
+		Channel: msg.Subject(),
+	}
+	if md, err := msg.Metadata(); err == nil {
+		metadata.Offset = int64(md.Sequence.Stream)
+		metadata.Timestamp = md.Timestamp
+		metadata.Redelivered = md.NumDelivered > 1
+	}
+
 	// Create and transmit message to user
 	sub.TransmitReceivedMessage(extensions.NewAcknowledgeableBrokerMessage(
 		extensions.BrokerMessage{
-			Headers: headers,
-			Payload: msg.Data(),
+			Headers:  headers,
+			Payload:  msg.Data(),
+			Metadata: metadata,
 		},
 		AcknowledgementHandler{
 			doAck: func() {
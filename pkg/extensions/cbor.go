@@ -0,0 +1,325 @@
+package extensions
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	cborUnsigned byte = iota << 5
+	cborNegative
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+// cborIndefinite is the additional information of the items with an
+// indefinite length, that end with cborBreak.
+const (
+	cborIndefinite = 31
+	cborBreak      = 0xff
+)
+
+// CBORCodec is the codec of CBOR payloads. The values are encoded with the
+// same representation as in JSON (e.g. the byte slices are base64 strings), so
+// the generated types don't need specific marshalers.
+type CBORCodec struct{}
+
+// Marshal marshals the value into CBOR.
+func (CBORCodec) Marshal(v any) ([]byte, error) {
+	generic, err := toGenericValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return appendCBOR(nil, generic)
+}
+
+// Unmarshal unmarshals the CBOR data into the value.
+func (CBORCodec) Unmarshal(data []byte, v any) error {
+	r := cborReader{data: data}
+	generic, err := r.value()
+	if err != nil {
+		return err
+	}
+	if r.pos != len(data) {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidCBOR, len(data)-r.pos)
+	}
+	return fromGenericValue(generic, v)
+}
+
+func appendCBOR(b []byte, v any) ([]byte, error) {
+	var err error
+	switch t := v.(type) {
+	case nil:
+		b = append(b, cborSimple|22)
+	case bool:
+		if t {
+			b = append(b, cborSimple|21)
+		} else {
+			b = append(b, cborSimple|20)
+		}
+	case json.Number:
+		b = appendCBORNumber(b, t)
+	case string:
+		b = append(appendCBORHead(b, cborText, uint64(len(t))), t...)
+	case []any:
+		b = appendCBORHead(b, cborArray, uint64(len(t)))
+		for _, e := range t {
+			if b, err = appendCBOR(b, e); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]any:
+		b = appendCBORHead(b, cborMap, uint64(len(t)))
+		for _, k := range SortedKeys(t) {
+			b = append(appendCBORHead(b, cborText, uint64(len(k))), k...)
+			if b, err = appendCBOR(b, t[k]); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: unexpected value %T", ErrInvalidCBOR, v)
+	}
+
+	return b, nil
+}
+
+func appendCBORNumber(b []byte, n json.Number) []byte {
+	if i, err := n.Int64(); err == nil {
+		if i < 0 {
+			return appendCBORHead(b, cborNegative, uint64(-1-i))
+		}
+		return appendCBORHead(b, cborUnsigned, uint64(i))
+	}
+
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return appendCBORHead(b, cborUnsigned, u)
+	}
+
+	f, _ := n.Float64()
+	return binary.BigEndian.AppendUint64(append(b, cborSimple|27), math.Float64bits(f))
+}
+
+// appendCBORHead appends the head of an item, with its major type and its
+// argument encoded on the smallest size.
+func appendCBORHead(b []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, major|27), n)
+	}
+}
+
+type cborReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *cborReader) read(n uint64) ([]byte, error) {
+	if n > uint64(len(r.data)-r.pos) {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidCBOR)
+	}
+	b := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+// head reads the head of an item and returns its major type, its additional
+// information and its argument.
+func (r *cborReader) head() (major, info byte, n uint64, err error) {
+	b, err := r.read(1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	major, info = b[0]&0xe0, b[0]&0x1f
+
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		arg, err := r.read(1 << (info - 24))
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		for _, c := range arg {
+			n = n<<8 | uint64(c)
+		}
+		return major, info, n, nil
+	case info == cborIndefinite && major != cborUnsigned && major != cborNegative && major != cborTag:
+		return major, info, 0, nil
+	default:
+		return 0, 0, 0, fmt.Errorf("%w: invalid additional information %d", ErrInvalidCBOR, info)
+	}
+}
+
+//nolint:cyclop // Not necessary to split the major types
+func (r *cborReader) value() (any, error) {
+	major, info, n, err := r.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUnsigned:
+		return n, nil
+	case cborNegative:
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: negative integer overflow", ErrInvalidCBOR)
+		}
+		return -1 - int64(n), nil
+	case cborBytes, cborText:
+		b, err := r.string(major, info, n)
+		if err != nil || major == cborBytes {
+			return b, err
+		}
+		return string(b), nil
+	case cborArray:
+		return r.array(info, n)
+	case cborMap:
+		return r.object(info, n)
+	case cborTag:
+		// Keep the content of the tagged items (e.g. dates are kept as strings)
+		return r.value()
+	default:
+		return r.simple(info, n)
+	}
+}
+
+// string reads the content of a byte or text string, concatenating the chunks
+// of the strings with an indefinite length.
+func (r *cborReader) string(major, info byte, n uint64) ([]byte, error) {
+	if info != cborIndefinite {
+		b, err := r.read(n)
+		return append([]byte(nil), b...), err
+	}
+
+	var b []byte
+	for !r.atBreak() {
+		chunkMajor, chunkInfo, chunkLen, err := r.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || chunkInfo == cborIndefinite {
+			return nil, fmt.Errorf("%w: invalid string chunk", ErrInvalidCBOR)
+		}
+		chunk, err := r.read(chunkLen)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, chunk...)
+	}
+	return b, nil
+}
+
+func (r *cborReader) array(info byte, n uint64) ([]any, error) {
+	if info != cborIndefinite && n > uint64(len(r.data)-r.pos) {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidCBOR)
+	}
+
+	a := make([]any, 0, n)
+	for i := uint64(0); info == cborIndefinite || i < n; i++ {
+		if info == cborIndefinite && r.atBreak() {
+			break
+		}
+		v, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+	return a, nil
+}
+
+func (r *cborReader) object(info byte, n uint64) (map[any]any, error) {
+	if info != cborIndefinite && n > uint64(len(r.data)-r.pos) {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidCBOR)
+	}
+
+	m := make(map[any]any, n)
+	for i := uint64(0); info == cborIndefinite || i < n; i++ {
+		if info == cborIndefinite && r.atBreak() {
+			break
+		}
+		k, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		switch t := k.(type) {
+		case []byte:
+			k = string(t)
+		case []any, map[any]any:
+			return nil, fmt.Errorf("%w: unsupported map key %v", ErrInvalidCBOR, k)
+		}
+		v, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+func (r *cborReader) simple(info byte, n uint64) (any, error) {
+	switch info {
+	case 20, 21:
+		return info == 21, nil
+	case 22, 23:
+		// Null and undefined
+		return nil, nil
+	case 25:
+		return halfToFloat64(uint16(n)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(n))), nil
+	case 27:
+		return math.Float64frombits(n), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported simple value %d", ErrInvalidCBOR, n)
+	}
+}
+
+// atBreak checks if the next byte is the end of an item with an indefinite
+// length, and consumes it if so.
+func (r *cborReader) atBreak() bool {
+	if r.pos < len(r.data) && r.data[r.pos] == cborBreak {
+		r.pos++
+		return true
+	}
+	return false
+}
+
+// halfToFloat64 converts an IEEE 754 half-precision float.
+func halfToFloat64(h uint16) float64 {
+	exp, mant := int(h>>10)&0x1f, float64(h&0x3ff)
+
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,250 @@
+package extensions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// CloudEventsSpecVersion is the CloudEvents specification version used by
+	// default on generated messages.
+	CloudEventsSpecVersion = "1.0"
+
+	// CloudEventsStructuredContentType is the content type of messages sent
+	// with CloudEvents structured mode.
+	CloudEventsStructuredContentType = "application/cloudevents+json"
+
+	// CloudEventsHeaderPrefix is the prefix of the headers holding the
+	// CloudEvents attributes in binary mode, as in the HTTP and NATS bindings.
+	CloudEventsHeaderPrefix = "ce-"
+	// CloudEventsKafkaHeaderPrefix is the prefix of the headers holding the
+	// CloudEvents attributes in binary mode, as required by the Kafka binding.
+	CloudEventsKafkaHeaderPrefix = "ce_"
+
+	// CloudEventsHeaderID is the header holding the event ID in binary mode.
+	CloudEventsHeaderID = "ce-id"
+	// CloudEventsHeaderSource is the header holding the event source in binary mode.
+	CloudEventsHeaderSource = "ce-source"
+	// CloudEventsHeaderSpecVersion is the header holding the event spec version in binary mode.
+	CloudEventsHeaderSpecVersion = "ce-specversion"
+	// CloudEventsHeaderType is the header holding the event type in binary mode.
+	CloudEventsHeaderType = "ce-type"
+	// CloudEventsHeaderSubject is the header holding the event subject in binary mode.
+	CloudEventsHeaderSubject = "ce-subject"
+	// CloudEventsHeaderTime is the header holding the event time in binary mode.
+	CloudEventsHeaderTime = "ce-time"
+	// CloudEventsHeaderContentType is the header holding the data content type
+	// in binary mode, or the event content type in structured mode.
+	CloudEventsHeaderContentType = ContentTypeHeader
+)
+
+// cloudEventsAttributesHeaders are the headers holding the CloudEvents
+// attributes in binary mode, except the content type that is not prefixed.
+var cloudEventsAttributesHeaders = map[string]bool{
+	CloudEventsHeaderID:          true,
+	CloudEventsHeaderSource:      true,
+	CloudEventsHeaderSpecVersion: true,
+	CloudEventsHeaderType:        true,
+	CloudEventsHeaderSubject:     true,
+	CloudEventsHeaderTime:        true,
+}
+
+// CloudEventsHeaderToKafka returns the Kafka header key corresponding to a
+// header key, replacing the CloudEvents prefix by the one of the Kafka binding
+// for the CloudEvents attributes headers only.
+func CloudEventsHeaderToKafka(key string) string {
+	if cloudEventsAttributesHeaders[key] {
+		return CloudEventsKafkaHeaderPrefix + strings.TrimPrefix(key, CloudEventsHeaderPrefix)
+	}
+	return key
+}
+
+// CloudEventsHeaderFromKafka returns the header key corresponding to a Kafka
+// header key, replacing the CloudEvents prefix of the Kafka binding for the
+// CloudEvents attributes headers only.
+func CloudEventsHeaderFromKafka(key string) string {
+	if !strings.HasPrefix(key, CloudEventsKafkaHeaderPrefix) {
+		return key
+	}
+
+	header := CloudEventsHeaderPrefix + strings.TrimPrefix(key, CloudEventsKafkaHeaderPrefix)
+	if cloudEventsAttributesHeaders[header] {
+		return header
+	}
+	return key
+}
+
+// CloudEvent contains the CloudEvents context attributes of a message.
+// Source: https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/spec.md
+type CloudEvent struct {
+	// ID identifies the event (required).
+	ID string
+	// Source identifies the context in which an event happened (required).
+	Source string
+	// SpecVersion is the version of the CloudEvents specification (required).
+	SpecVersion string
+	// Type describes the type of event (required).
+	Type string
+	// Subject describes the subject of the event in the context of the source.
+	Subject string
+	// Time is the time at which the event happened.
+	Time time.Time
+	// DataContentType is the content type of the event data.
+	DataContentType string
+}
+
+// SetDefaults fills the missing required attributes of the CloudEvent, with
+// a new ID, the default spec version, the current time and the given source
+// and type.
+func (ce *CloudEvent) SetDefaults(source, eventType string) {
+	if ce.ID == "" {
+		ce.ID = uuid.New().String()
+	}
+	if ce.Source == "" {
+		ce.Source = source
+	}
+	if ce.SpecVersion == "" {
+		ce.SpecVersion = CloudEventsSpecVersion
+	}
+	if ce.Type == "" {
+		ce.Type = eventType
+	}
+	if ce.Time.IsZero() {
+		ce.Time = time.Now()
+	}
+}
+
+// Validate checks that the required attributes of the CloudEvent are set.
+func (ce CloudEvent) Validate() error {
+	missing := make([]string, 0)
+	if ce.ID == "" {
+		missing = append(missing, "id")
+	}
+	if ce.Source == "" {
+		missing = append(missing, "source")
+	}
+	if ce.SpecVersion == "" {
+		missing = append(missing, "specversion")
+	}
+	if ce.Type == "" {
+		missing = append(missing, "type")
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing required attributes %q", ErrInvalidCloudEvent, missing)
+	}
+
+	return nil
+}
+
+// ToBinaryHeaders sets the CloudEvent attributes into the headers, as
+// specified by the CloudEvents binary content mode.
+func (ce CloudEvent) ToBinaryHeaders(headers map[string][]byte) {
+	headers[CloudEventsHeaderID] = []byte(ce.ID)
+	headers[CloudEventsHeaderSource] = []byte(ce.Source)
+	headers[CloudEventsHeaderSpecVersion] = []byte(ce.SpecVersion)
+	headers[CloudEventsHeaderType] = []byte(ce.Type)
+	if ce.Subject != "" {
+		headers[CloudEventsHeaderSubject] = []byte(ce.Subject)
+	}
+	if !ce.Time.IsZero() {
+		headers[CloudEventsHeaderTime] = []byte(ce.Time.Format(time.RFC3339Nano))
+	}
+	if ce.DataContentType != "" {
+		headers[CloudEventsHeaderContentType] = []byte(ce.DataContentType)
+	}
+}
+
+// CloudEventFromBinaryHeaders gets the CloudEvent attributes from the headers,
+// as specified by the CloudEvents binary content mode.
+func CloudEventFromBinaryHeaders(headers map[string][]byte) (CloudEvent, error) {
+	ce := CloudEvent{
+		ID:              string(headers[CloudEventsHeaderID]),
+		Source:          string(headers[CloudEventsHeaderSource]),
+		SpecVersion:     string(headers[CloudEventsHeaderSpecVersion]),
+		Type:            string(headers[CloudEventsHeaderType]),
+		Subject:         string(headers[CloudEventsHeaderSubject]),
+		DataContentType: string(headers[CloudEventsHeaderContentType]),
+	}
+
+	if t, ok := headers[CloudEventsHeaderTime]; ok {
+		parsed, err := time.Parse(time.RFC3339Nano, string(t))
+		if err != nil {
+			return CloudEvent{}, fmt.Errorf("%w: invalid time: %w", ErrInvalidCloudEvent, err)
+		}
+		ce.Time = parsed
+	}
+
+	return ce, ce.Validate()
+}
+
+type cloudEventEnvelope struct {
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	SpecVersion     string          `json:"specversion"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            *time.Time      `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+	DataBase64      []byte          `json:"data_base64,omitempty"`
+}
+
+// ToStructuredPayload wraps the data into a CloudEvents JSON envelope, as
+// specified by the CloudEvents structured content mode. The data is set as JSON
+// if the data content type is JSON, and as base64 encoded data otherwise.
+func (ce CloudEvent) ToStructuredPayload(data []byte) ([]byte, error) {
+	env := cloudEventEnvelope{
+		ID:              ce.ID,
+		Source:          ce.Source,
+		SpecVersion:     ce.SpecVersion,
+		Type:            ce.Type,
+		Subject:         ce.Subject,
+		DataContentType: ce.DataContentType,
+	}
+
+	if !ce.Time.IsZero() {
+		env.Time = &ce.Time
+	}
+
+	if IsJSONContentType(ce.DataContentType) {
+		env.Data = data
+	} else {
+		env.DataBase64 = data
+	}
+
+	return json.Marshal(env)
+}
+
+// CloudEventFromStructuredPayload gets the CloudEvent attributes and the data
+// from a CloudEvents JSON envelope, as specified by the CloudEvents structured
+// content mode.
+func CloudEventFromStructuredPayload(payload []byte) (CloudEvent, []byte, error) {
+	var env cloudEventEnvelope
+	if err := json.Unmarshal(payload, &env); err != nil {
+		return CloudEvent{}, nil, fmt.Errorf("%w: %w", ErrInvalidCloudEvent, err)
+	}
+
+	ce := CloudEvent{
+		ID:              env.ID,
+		Source:          env.Source,
+		SpecVersion:     env.SpecVersion,
+		Type:            env.Type,
+		Subject:         env.Subject,
+		DataContentType: env.DataContentType,
+	}
+	if env.Time != nil {
+		ce.Time = *env.Time
+	}
+
+	data := []byte(env.Data)
+	if env.DataBase64 != nil {
+		data = env.DataBase64
+	}
+
+	return ce, data, ce.Validate()
+}
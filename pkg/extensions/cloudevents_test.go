@@ -0,0 +1,88 @@
+package extensions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCloudEventsSuite(t *testing.T) {
+	suite.Run(t, new(CloudEventsSuite))
+}
+
+type CloudEventsSuite struct {
+	suite.Suite
+}
+
+func (suite *CloudEventsSuite) event() CloudEvent {
+	return CloudEvent{
+		ID:          "1234",
+		Source:      "urn:test",
+		SpecVersion: CloudEventsSpecVersion,
+		Type:        "test.event",
+		Subject:     "subject",
+		Time:        time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+	}
+}
+
+func (suite *CloudEventsSuite) TestBinaryRoundTrip() {
+	headers := make(map[string][]byte)
+	suite.event().ToBinaryHeaders(headers)
+	suite.Require().Equal("1234", string(headers[CloudEventsHeaderID]))
+
+	ce, err := CloudEventFromBinaryHeaders(headers)
+	suite.Require().NoError(err)
+	suite.Require().Equal(suite.event(), ce)
+}
+
+func (suite *CloudEventsSuite) TestStructuredRoundTrip() {
+	cases := []struct {
+		contentType string
+		data        string
+		field       string
+	}{
+		{contentType: "application/json", data: `{"hello":"world"}`, field: `"data":{"hello":"world"}`},
+		{contentType: "application/cloudevents+json; charset=utf-8", data: `"hello"`, field: `"data":"hello"`},
+		{contentType: "text/plain", data: `{"hello":"world"}`, field: `"data_base64":"eyJoZWxsbyI6IndvcmxkIn0="`},
+		{contentType: "", data: "not json", field: `"data_base64":"bm90IGpzb24="`},
+	}
+
+	for _, c := range cases {
+		event := suite.event()
+		event.DataContentType = c.contentType
+
+		payload, err := event.ToStructuredPayload([]byte(c.data))
+		suite.Require().NoError(err)
+		suite.Require().Contains(string(payload), c.field)
+
+		// The data content type is kept as is, even when it is empty
+		ce, resData, err := CloudEventFromStructuredPayload(payload)
+		suite.Require().NoError(err)
+		suite.Require().Equal(c.data, string(resData))
+		suite.Require().Equal(event, ce)
+	}
+}
+
+func (suite *CloudEventsSuite) TestKafkaHeaders() {
+	suite.Require().Equal("ce_id", CloudEventsHeaderToKafka(CloudEventsHeaderID))
+	suite.Require().Equal(CloudEventsHeaderID, CloudEventsHeaderFromKafka("ce_id"))
+
+	// Other headers are left untouched, even with the CloudEvents prefixes
+	for _, key := range []string{ContentTypeHeader, "messageId", "ce_x", "ce-x"} {
+		suite.Require().Equal(key, CloudEventsHeaderToKafka(key))
+		suite.Require().Equal(key, CloudEventsHeaderFromKafka(key))
+	}
+}
+
+func (suite *CloudEventsSuite) TestMissingAttributes() {
+	_, err := CloudEventFromBinaryHeaders(map[string][]byte{CloudEventsHeaderID: []byte("1234")})
+	suite.Require().ErrorIs(err, ErrInvalidCloudEvent)
+}
+
+func (suite *CloudEventsSuite) TestSetDefaults() {
+	var ce CloudEvent
+	ce.SetDefaults("urn:test", "test.event")
+	suite.Require().NoError(ce.Validate())
+	suite.Require().False(ce.Time.IsZero())
+}
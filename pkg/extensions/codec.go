@@ -0,0 +1,352 @@
+package extensions
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// JSONContentType is the content type of JSON payloads.
+	JSONContentType = "application/json"
+	// MessagePackContentType is the content type of MessagePack payloads.
+	MessagePackContentType = "application/msgpack"
+	// CBORContentType is the content type of CBOR payloads.
+	CBORContentType = "application/cbor"
+	// TextContentType is the content type of plain text payloads.
+	TextContentType = "text/plain"
+	// OctetStreamContentType is the content type of raw binary payloads.
+	OctetStreamContentType = "application/octet-stream"
+)
+
+// Codec marshals and unmarshals the payloads of a content type.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	codecsMutex sync.RWMutex
+	codecs      = map[string]Codec{
+		JSONContentType:            JSONCodec{},
+		MessagePackContentType:     MessagePackCodec{},
+		"application/x-msgpack":    MessagePackCodec{},
+		"application/vnd.msgpack":  MessagePackCodec{},
+		CBORContentType:            CBORCodec{},
+		TextContentType:            TextCodec{},
+		OctetStreamContentType:     OctetStreamCodec{},
+		"application/x-binary":     OctetStreamCodec{},
+		"application/vnd.x-binary": OctetStreamCodec{},
+	}
+)
+
+// RegisterCodec registers the codec used by the generated code for the
+// payloads of the content type, replacing the existing one if any.
+func RegisterCodec(contentType string, codec Codec) {
+	codecsMutex.Lock()
+	defer codecsMutex.Unlock()
+
+	codecs[MediaType(contentType)] = codec
+}
+
+// CodecFor returns the codec registered for the content type. The parameters
+// of the content type are ignored (e.g. 'text/plain; charset=utf-8'), and the
+// content types with a structured syntax suffix (e.g. 'application/vnd.user+json')
+// use the codec of the suffix if there is none registered for them.
+func CodecFor(contentType string) (Codec, error) {
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+
+	contentType = MediaType(contentType)
+	if c, ok := codecs[contentType]; ok {
+		return c, nil
+	}
+
+	if i := strings.LastIndex(contentType, "+"); i >= 0 {
+		if c, ok := codecs["application/"+contentType[i+1:]]; ok {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+}
+
+// MarshalWithContentType marshals the value with the codec of the content type.
+func MarshalWithContentType(contentType string, v any) ([]byte, error) {
+	c, err := CodecFor(contentType)
+	if err != nil {
+		return nil, err
+	}
+	return c.Marshal(v)
+}
+
+// UnmarshalWithContentType unmarshals the data into the value with the codec
+// of the content type.
+func UnmarshalWithContentType(contentType string, data []byte, v any) error {
+	c, err := CodecFor(contentType)
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal(data, v)
+}
+
+// IsJSONContentType checks if the content type is JSON, including the content
+// types with a JSON structured syntax suffix (e.g. 'application/vnd.user+json').
+func IsJSONContentType(contentType string) bool {
+	contentType = MediaType(contentType)
+	return contentType == JSONContentType || strings.HasSuffix(contentType, "+json")
+}
+
+// MediaType returns the media type of the content type, in lower case and
+// without its parameters (e.g. 'text/plain' for 'Text/Plain; charset=utf-8').
+func MediaType(contentType string) string {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// JSONCodec is the codec of JSON payloads.
+type JSONCodec struct{}
+
+// Marshal marshals the value into JSON.
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal unmarshals the JSON data into the value.
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// TextCodec is the codec of plain text payloads. It supports the strings,
+// booleans, numbers, byte slices and the types implementing encoding.TextMarshaler
+// and encoding.TextUnmarshaler.
+type TextCodec struct{}
+
+// Marshal marshals the value into text.
+func (TextCodec) Marshal(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
+		return nil, nil
+	}
+
+	if m, ok := rv.Interface().(encoding.TextMarshaler); ok {
+		return m.MarshalText()
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return []byte(rv.String()), nil
+	case reflect.Bool:
+		return strconv.AppendBool(nil, rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(nil, rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.AppendUint(nil, rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(nil, rv.Float(), 'g', -1, rv.Type().Bits()), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return bytes.Clone(rv.Bytes()), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s can't be marshaled into text", ErrInvalidCodecValue, rv.Type())
+}
+
+// Unmarshal unmarshals the text into the value.
+//
+//nolint:cyclop // Not necessary to split the kinds
+func (TextCodec) Unmarshal(data []byte, v any) error {
+	rv, err := codecTarget(v)
+	if err != nil {
+		return err
+	}
+
+	if u, ok := rv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText(data)
+	}
+
+	s := string(data)
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidCodecValue, err)
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidCodecValue, err)
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidCodecValue, err)
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidCodecValue, err)
+		}
+		rv.SetFloat(f)
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("%w: text can't be unmarshaled into %s", ErrInvalidCodecValue, rv.Type())
+		}
+		rv.SetBytes(bytes.Clone(data))
+	default:
+		return fmt.Errorf("%w: text can't be unmarshaled into %s", ErrInvalidCodecValue, rv.Type())
+	}
+
+	return nil
+}
+
+// OctetStreamCodec is the codec of raw binary payloads. It supports the byte
+// slices, the strings and the types implementing encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler.
+type OctetStreamCodec struct{}
+
+// Marshal marshals the value into bytes.
+func (OctetStreamCodec) Marshal(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
+		return nil, nil
+	}
+
+	if m, ok := rv.Interface().(encoding.BinaryMarshaler); ok {
+		return m.MarshalBinary()
+	}
+
+	switch {
+	case rv.Kind() == reflect.String:
+		return []byte(rv.String()), nil
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		return bytes.Clone(rv.Bytes()), nil
+	default:
+		return nil, fmt.Errorf("%w: %s can't be marshaled into bytes", ErrInvalidCodecValue, rv.Type())
+	}
+}
+
+// Unmarshal unmarshals the bytes into the value.
+func (OctetStreamCodec) Unmarshal(data []byte, v any) error {
+	rv, err := codecTarget(v)
+	if err != nil {
+		return err
+	}
+
+	if u, ok := rv.Addr().Interface().(encoding.BinaryUnmarshaler); ok {
+		return u.UnmarshalBinary(data)
+	}
+
+	switch {
+	case rv.Kind() == reflect.String:
+		rv.SetString(string(data))
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		rv.SetBytes(bytes.Clone(data))
+	default:
+		return fmt.Errorf("%w: bytes can't be unmarshaled into %s", ErrInvalidCodecValue, rv.Type())
+	}
+
+	return nil
+}
+
+// codecTarget returns the value pointed by v, allocating the intermediate
+// pointers if needed.
+func codecTarget(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return reflect.Value{}, fmt.Errorf("%w: expected a non-nil pointer, got %T", ErrInvalidCodecValue, v)
+	}
+
+	rv = rv.Elem()
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+
+	return rv, nil
+}
+
+// toGenericValue converts a value to its JSON representation made of nil,
+// booleans, json.Number, strings, slices and maps, so the binary codecs use
+// the same representation as JSON (including the custom JSON marshalers).
+func toGenericValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return generic, nil
+}
+
+// fromGenericValue sets the value from its generic representation, decoded by
+// a binary codec. The byte strings are converted to base64, as they would be
+// in JSON.
+func fromGenericValue(generic, v any) error {
+	data, err := json.Marshal(jsonCompatible(generic))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCodecValue, err)
+	}
+	return json.Unmarshal(data, v)
+}
+
+func jsonCompatible(v any) any {
+	switch t := v.(type) {
+	case []byte:
+		return base64.StdEncoding.EncodeToString(t)
+	case []any:
+		for i := range t {
+			t[i] = jsonCompatible(t[i])
+		}
+		return t
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			if b, ok := k.([]byte); ok {
+				k = string(b)
+			}
+			m[fmt.Sprint(k)] = jsonCompatible(e)
+		}
+		return m
+	default:
+		return v
+	}
+}
+
+// SortedKeys returns the keys of a map, sorted so the encodings of the maps
+// (e.g. the binary codecs or the additional properties) are deterministic.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,214 @@
+package extensions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCodecSuite(t *testing.T) {
+	suite.Run(t, new(CodecSuite))
+}
+
+type CodecSuite struct {
+	suite.Suite
+}
+
+type codecTestAddress struct {
+	City string `json:"city"`
+}
+
+type codecTestUser struct {
+	ID        int64             `json:"id"`
+	Name      string            `json:"name"`
+	Email     *string           `json:"email,omitempty"`
+	Ratio     float64           `json:"ratio"`
+	Hash      []byte            `json:"hash"`
+	Tags      []string          `json:"tags"`
+	Counters  map[string]uint64 `json:"counters"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Address   *codecTestAddress `json:"address"`
+}
+
+type codecTestCodec struct {
+	JSONCodec
+}
+
+func (suite *CodecSuite) TestCodecFor() {
+	cases := []struct {
+		ContentType string
+		Codec       Codec
+	}{
+		{ContentType: "application/json", Codec: JSONCodec{}},
+		{ContentType: "Application/JSON; charset=utf-8", Codec: JSONCodec{}},
+		{ContentType: "application/vnd.user-signed-up+json", Codec: JSONCodec{}},
+		{ContentType: "application/x-msgpack", Codec: MessagePackCodec{}},
+		{ContentType: "application/cbor", Codec: CBORCodec{}},
+		{ContentType: "text/plain; charset=utf-8", Codec: TextCodec{}},
+		{ContentType: "application/octet-stream", Codec: OctetStreamCodec{}},
+	}
+
+	for _, c := range cases {
+		codec, err := CodecFor(c.ContentType)
+		suite.Require().NoError(err, c.ContentType)
+		suite.Require().Equal(c.Codec, codec, c.ContentType)
+	}
+
+	_, err := CodecFor("application/xml")
+	suite.Require().ErrorIs(err, ErrUnsupportedContentType)
+
+	// Register a new codec
+	RegisterCodec("application/x-test", codecTestCodec{})
+	codec, err := CodecFor("application/x-test")
+	suite.Require().NoError(err)
+	suite.Require().Equal(codecTestCodec{}, codec)
+}
+
+func (suite *CodecSuite) TestIsJSONContentType() {
+	suite.Require().True(IsJSONContentType("application/json"))
+	suite.Require().True(IsJSONContentType("Application/JSON; charset=utf-8"))
+	suite.Require().True(IsJSONContentType("application/vnd.user-signed-up+json"))
+	suite.Require().False(IsJSONContentType("application/cbor"))
+	suite.Require().False(IsJSONContentType(""))
+}
+
+func (suite *CodecSuite) TestRoundTrip() {
+	sent := codecTestUser{
+		ID:        -1234567890123,
+		Name:      "bob",
+		Email:     func() *string { s := "bob@example.com"; return &s }(),
+		Ratio:     0.25,
+		Hash:      []byte{0xde, 0xad, 0xbe, 0xef},
+		Tags:      []string{"a", "b"},
+		Counters:  map[string]uint64{"max": 18446744073709551615, "zero": 0},
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		Address:   &codecTestAddress{City: "Paris"},
+	}
+
+	for _, contentType := range []string{JSONContentType, MessagePackContentType, CBORContentType} {
+		data, err := MarshalWithContentType(contentType, sent)
+		suite.Require().NoError(err, contentType)
+
+		var received codecTestUser
+		suite.Require().NoError(UnmarshalWithContentType(contentType, data, &received), contentType)
+		suite.Require().Equal(sent, received, contentType)
+	}
+}
+
+func (suite *CodecSuite) TestMessagePackEncoding() {
+	// Example from the MessagePack specification
+	data, err := MessagePackCodec{}.Marshal(map[string]any{"compact": true, "schema": 0})
+	suite.Require().NoError(err)
+	suite.Require().Equal([]byte("\x82\xa7compact\xc3\xa6schema\x00"), data)
+
+	cases := []struct {
+		Value any
+		Data  []byte
+	}{
+		{Value: -33, Data: []byte{0xd0, 0xdf}},
+		{Value: 300, Data: []byte{0xd1, 0x01, 0x2c}},
+		{Value: uint64(18446744073709551615), Data: []byte{0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{Value: 1.5, Data: []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{Value: []any{nil, false}, Data: []byte{0x92, 0xc0, 0xc2}},
+	}
+	for _, c := range cases {
+		data, err := MessagePackCodec{}.Marshal(c.Value)
+		suite.Require().NoError(err, c.Value)
+		suite.Require().Equal(c.Data, data, c.Value)
+	}
+
+	// Decode the formats that are not produced by the encoder
+	var v struct {
+		F float64 `json:"f"`
+		B []byte  `json:"b"`
+	}
+	suite.Require().NoError(MessagePackCodec{}.Unmarshal([]byte{
+		0x82, 0xa1, 'f', 0xca, 0x3f, 0xc0, 0, 0, 0xa1, 'b', 0xc4, 0x02, 0x01, 0x02,
+	}, &v))
+	suite.Require().Equal(1.5, v.F)
+	suite.Require().Equal([]byte{1, 2}, v.B)
+}
+
+func (suite *CodecSuite) TestCBOREncoding() {
+	// Examples from the appendix A of the RFC 8949
+	cases := []struct {
+		Value any
+		Data  []byte
+	}{
+		{Value: 24, Data: []byte{0x18, 0x18}},
+		{Value: -1000, Data: []byte{0x39, 0x03, 0xe7}},
+		{Value: uint64(18446744073709551615), Data: []byte{0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{Value: "a", Data: []byte{0x61, 0x61}},
+		{Value: map[string]any{"a": 1, "b": []int{2, 3}}, Data: []byte{0xa2, 0x61, 0x61, 0x01, 0x61, 0x62, 0x82, 0x02, 0x03}},
+		{Value: []any{nil, true}, Data: []byte{0x82, 0xf6, 0xf5}},
+	}
+	for _, c := range cases {
+		data, err := CBORCodec{}.Marshal(c.Value)
+		suite.Require().NoError(err, c.Value)
+		suite.Require().Equal(c.Data, data, c.Value)
+	}
+
+	// Decode the indefinite lengths, half-precision floats and tags
+	var a []any
+	suite.Require().NoError(CBORCodec{}.Unmarshal([]byte{0x9f, 0x01, 0x82, 0x02, 0x03, 0x9f, 0x04, 0x05, 0xff, 0xff}, &a))
+	suite.Require().Equal([]any{float64(1), []any{float64(2), float64(3)}, []any{float64(4), float64(5)}}, a)
+
+	var b []byte
+	suite.Require().NoError(CBORCodec{}.Unmarshal([]byte{0x5f, 0x42, 0x01, 0x02, 0x43, 0x03, 0x04, 0x05, 0xff}, &b))
+	suite.Require().Equal([]byte{1, 2, 3, 4, 5}, b)
+
+	var f float64
+	suite.Require().NoError(CBORCodec{}.Unmarshal([]byte{0xf9, 0xc4, 0x00}, &f))
+	suite.Require().Equal(-4.0, f)
+
+	var t time.Time
+	suite.Require().NoError(CBORCodec{}.Unmarshal(append([]byte{0xc0, 0x74}, "2013-03-21T20:04:00Z"...), &t))
+	suite.Require().Equal(time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC), t)
+}
+
+func (suite *CodecSuite) TestText() {
+	data, err := TextCodec{}.Marshal(int32(-42))
+	suite.Require().NoError(err)
+	suite.Require().Equal("-42", string(data))
+
+	var i int32
+	suite.Require().NoError(TextCodec{}.Unmarshal(data, &i))
+	suite.Require().Equal(int32(-42), i)
+
+	// Types implementing encoding.TextMarshaler
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	data, err = TextCodec{}.Marshal(&date)
+	suite.Require().NoError(err)
+	suite.Require().Equal("2024-01-02T03:04:05Z", string(data))
+
+	var ptr *time.Time
+	suite.Require().NoError(TextCodec{}.Unmarshal(data, &ptr))
+	suite.Require().Equal(date, *ptr)
+
+	_, err = TextCodec{}.Marshal(codecTestAddress{})
+	suite.Require().ErrorIs(err, ErrInvalidCodecValue)
+	suite.Require().ErrorIs(TextCodec{}.Unmarshal([]byte("abc"), &i), ErrInvalidCodecValue)
+}
+
+func (suite *CodecSuite) TestOctetStream() {
+	data, err := OctetStreamCodec{}.Marshal([]byte{1, 2, 3})
+	suite.Require().NoError(err)
+	suite.Require().Equal([]byte{1, 2, 3}, data)
+
+	var s string
+	suite.Require().NoError(OctetStreamCodec{}.Unmarshal(data, &s))
+	suite.Require().Equal("\x01\x02\x03", s)
+
+	_, err = OctetStreamCodec{}.Marshal(42)
+	suite.Require().ErrorIs(err, ErrInvalidCodecValue)
+	suite.Require().ErrorIs(OctetStreamCodec{}.Unmarshal(data, s), ErrInvalidCodecValue)
+}
+
+func (suite *CodecSuite) TestInvalidData() {
+	var v any
+	suite.Require().ErrorIs(MessagePackCodec{}.Unmarshal([]byte{0x92, 0x01}, &v), ErrInvalidMessagePack)
+	suite.Require().ErrorIs(MessagePackCodec{}.Unmarshal([]byte{0x01, 0x02}, &v), ErrInvalidMessagePack)
+	suite.Require().ErrorIs(CBORCodec{}.Unmarshal([]byte{0x82, 0x01}, &v), ErrInvalidCBOR)
+	suite.Require().ErrorIs(CBORCodec{}.Unmarshal([]byte{0x1c}, &v), ErrInvalidCBOR)
+}
@@ -17,11 +17,27 @@ const (
 	ContextKeyIsProvider ContextKey = Prefix + "provider"
 	// ContextKeyIsChannel is the name of the channel this data is coming from.
 	ContextKeyIsChannel ContextKey = Prefix + "channel"
+	// ContextKeyIsSubscribedChannel is the address of the subscription that
+	// received the data, that can differ from the channel it is coming from
+	// (e.g. with wildcard subscriptions). It is only set on reception.
+	ContextKeyIsSubscribedChannel ContextKey = Prefix + "subscribed-channel"
+	// ContextKeyIsChannelParameters is the parameters of the channel this data
+	// is coming from, as the generated '<Channel>Parameters' structure.
+	ContextKeyIsChannelParameters ContextKey = Prefix + "channel-parameters"
 	// ContextKeyIsDirection is the direction this data is coming from.
-	// It can be either "publication" or "reception".
+	// It can be either "publication", "reception" or "wait-for" (for the reply
+	// received after a request).
 	ContextKeyIsDirection ContextKey = Prefix + "operation"
+	// ContextKeyIsCloudEventsMode is the CloudEvents content mode of the
+	// generated messages ("binary" or "structured"), when it is enabled. It is
+	// set on publication and subscription so that the brokers can map the
+	// CloudEvents headers to their binding (e.g. the 'ce_' prefix on Kafka).
+	ContextKeyIsCloudEventsMode ContextKey = Prefix + "cloudevents-mode"
 	// ContextKeyIsBrokerMessage is the message that has been sent or received from/to the broker.
 	ContextKeyIsBrokerMessage ContextKey = Prefix + "broker-message"
+	// ContextKeyIsBrokerMessageMetadata is the metadata of the message that has
+	// been received from the broker, as BrokerMessageMetadata.
+	ContextKeyIsBrokerMessageMetadata ContextKey = Prefix + "broker-message-metadata"
 	// ContextKeyIsCorrelationID is the correlation ID of the message.
 	ContextKeyIsCorrelationID ContextKey = Prefix + "correlationID"
 )
@@ -31,6 +47,13 @@ func (k ContextKey) String() string {
 	return string(k)
 }
 
+// BrokerMessageMetadataFromContext returns the metadata of the message that
+// has been received from the broker, if it is set in the context.
+func BrokerMessageMetadataFromContext(ctx context.Context) (BrokerMessageMetadata, bool) {
+	metadata, ok := ctx.Value(ContextKeyIsBrokerMessageMetadata).(BrokerMessageMetadata)
+	return metadata, ok
+}
+
 // IfContextSetWith executes the function if the key is set in the context.
 func IfContextSetWith[T any](ctx context.Context, key ContextKey, fn func(value T)) {
 	// Get value
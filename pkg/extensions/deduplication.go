@@ -0,0 +1,20 @@
+package extensions
+
+import "context"
+
+// DeduplicationStore is the interface that must be implemented by a store
+// keeping track of already processed messages IDs. It is used by the
+// deduplication middleware and can be backed by any storage (memory, Redis,
+// database, etc).
+type DeduplicationStore interface {
+	// Has checks if the ID has already been recorded in the store.
+	Has(ctx context.Context, id string) (bool, error)
+
+	// Add records the ID in the store if it is not already recorded, and
+	// returns false if it was. The check and the insertion should be atomic,
+	// so that concurrent duplicates can't both be recorded.
+	Add(ctx context.Context, id string) (bool, error)
+
+	// Remove removes the ID from the store.
+	Remove(ctx context.Context, id string) error
+}
@@ -0,0 +1,145 @@
+package deduplicationstores
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+)
+
+// Check that it still fills the interface.
+var _ extensions.DeduplicationStore = (*Memory)(nil)
+
+const (
+	// DefaultMemoryCapacity is the default maximum number of IDs kept by the
+	// memory store.
+	DefaultMemoryCapacity = 10000
+
+	// DefaultMemoryTTL is the default duration during which an ID is kept by
+	// the memory store.
+	DefaultMemoryTTL = time.Hour
+)
+
+type memoryEntry struct {
+	id        string
+	expiresAt time.Time
+}
+
+// Memory is an in-memory deduplication store that will keep a limited number
+// of IDs for a limited duration: when the capacity is reached, the least
+// recently used IDs are evicted first.
+type Memory struct {
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// MemoryOption is a function that can be used to configure a memory store.
+// Examples: WithCapacity(), WithTTL().
+type MemoryOption func(store *Memory)
+
+// NewMemory creates a new in-memory deduplication store.
+func NewMemory(options ...MemoryOption) *Memory {
+	// Creates default store
+	store := &Memory{
+		capacity: DefaultMemoryCapacity,
+		ttl:      DefaultMemoryTTL,
+		now:      time.Now,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+
+	// Execute options
+	for _, option := range options {
+		option(store)
+	}
+
+	return store
+}
+
+// WithCapacity sets the maximum number of IDs kept by the store.
+func WithCapacity(capacity int) MemoryOption {
+	return func(store *Memory) {
+		store.capacity = capacity
+	}
+}
+
+// WithTTL sets the duration during which an ID is kept by the store.
+func WithTTL(ttl time.Duration) MemoryOption {
+	return func(store *Memory) {
+		store.ttl = ttl
+	}
+}
+
+// Has checks if the ID has already been recorded in the store and is not expired.
+func (m *Memory) Has(_ context.Context, id string) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Check if the ID exists
+	elem, exists := m.entries[id]
+	if !exists {
+		return false, nil
+	}
+
+	// Remove it if it is expired
+	if m.now().After(elem.Value.(memoryEntry).expiresAt) {
+		m.remove(elem)
+		return false, nil
+	}
+
+	// Set it as the most recently used
+	m.order.MoveToFront(elem)
+
+	return true, nil
+}
+
+// Add records the ID in the store if it is not already recorded and not
+// expired, evicting the least recently used IDs if the capacity is reached.
+// It returns false if the ID was already recorded.
+func (m *Memory) Add(_ context.Context, id string) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Check if the ID already exists and is not expired
+	if elem, exists := m.entries[id]; exists {
+		if !m.now().After(elem.Value.(memoryEntry).expiresAt) {
+			m.order.MoveToFront(elem)
+			return false, nil
+		}
+		m.remove(elem)
+	}
+
+	// Add the new ID
+	m.entries[id] = m.order.PushFront(memoryEntry{id: id, expiresAt: m.now().Add(m.ttl)})
+
+	// Evict the least recently used IDs if there is too many of them
+	for m.capacity > 0 && m.order.Len() > m.capacity {
+		m.remove(m.order.Back())
+	}
+
+	return true, nil
+}
+
+// Remove removes the ID from the store, if it is recorded.
+func (m *Memory) Remove(_ context.Context, id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if elem, exists := m.entries[id]; exists {
+		m.remove(elem)
+	}
+
+	return nil
+}
+
+func (m *Memory) remove(elem *list.Element) {
+	m.order.Remove(elem)
+	delete(m.entries, elem.Value.(memoryEntry).id)
+}
@@ -0,0 +1,83 @@
+package deduplicationstores
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMemorySuite(t *testing.T) {
+	suite.Run(t, new(MemorySuite))
+}
+
+type MemorySuite struct {
+	suite.Suite
+}
+
+func (suite *MemorySuite) add(store *Memory, id string) {
+	added, err := store.Add(context.Background(), id)
+	suite.Require().NoError(err)
+	suite.Require().True(added, id)
+}
+
+func (suite *MemorySuite) TestHasAfterAdd() {
+	store := NewMemory()
+
+	has, err := store.Has(context.Background(), "id")
+	suite.Require().NoError(err)
+	suite.Require().False(has)
+
+	suite.add(store, "id")
+
+	has, err = store.Has(context.Background(), "id")
+	suite.Require().NoError(err)
+	suite.Require().True(has)
+}
+
+func (suite *MemorySuite) TestEvictLeastRecentlyUsed() {
+	store := NewMemory(WithCapacity(2))
+
+	suite.add(store, "1")
+	suite.add(store, "2")
+
+	// Use the first one to make the second one the least recently used
+	has, err := store.Has(context.Background(), "1")
+	suite.Require().NoError(err)
+	suite.Require().True(has)
+
+	suite.add(store, "3")
+
+	for id, expected := range map[string]bool{"1": true, "2": false, "3": true} {
+		has, err := store.Has(context.Background(), id)
+		suite.Require().NoError(err)
+		suite.Require().Equal(expected, has, id)
+	}
+}
+
+func (suite *MemorySuite) TestExpiration() {
+	now := time.Now()
+	store := NewMemory(WithTTL(time.Minute))
+	store.now = func() time.Time { return now }
+
+	suite.add(store, "id")
+
+	now = now.Add(2 * time.Minute)
+	has, err := store.Has(context.Background(), "id")
+	suite.Require().NoError(err)
+	suite.Require().False(has)
+}
+
+func (suite *MemorySuite) TestAddOnlyOnce() {
+	store := NewMemory()
+	suite.add(store, "id")
+
+	added, err := store.Add(context.Background(), "id")
+	suite.Require().NoError(err)
+	suite.Require().False(added)
+
+	// Can be added again once removed
+	suite.Require().NoError(store.Remove(context.Background(), "id"))
+	suite.add(store, "id")
+}
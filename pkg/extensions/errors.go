@@ -34,4 +34,107 @@ var (
 	// ErrChannelAddressEmpty is raised when a given channel address is empty,
 	// when dynamically set from message.
 	ErrChannelAddressEmpty = fmt.Errorf("%w: channel address empty", ErrAsyncAPI)
+
+	// ErrSkipMessage can be returned by a middleware to stop the processing of a
+	// received message without considering it as a failure: the message will be
+	// acknowledged and the error handler will not be called.
+	ErrSkipMessage = fmt.Errorf("%w: message skipped", ErrAsyncAPI)
+
+	// ErrInvalidCloudEvent is raised when a message does not contain a valid
+	// CloudEvent while it is expected.
+	ErrInvalidCloudEvent = fmt.Errorf("%w: invalid cloud event", ErrAsyncAPI)
+
+	// ErrUnknownMessage is raised when a message can't be identified as one of
+	// the messages expected on an operation.
+	ErrUnknownMessage = fmt.Errorf("%w: unknown message", ErrAsyncAPI)
+
+	// ErrMultipleVariantsSet is raised when marshaling a 'oneOf' union that
+	// has more than one variant set.
+	ErrMultipleVariantsSet = fmt.Errorf("%w: multiple variants set on oneOf union", ErrAsyncAPI)
+
+	// ErrUnknownVariant is raised when unmarshaling a union whose value does
+	// not correspond to any of its variants.
+	ErrUnknownVariant = fmt.Errorf("%w: unknown union variant", ErrAsyncAPI)
+
+	// ErrAmbiguousVariant is raised when unmarshaling a 'oneOf' union without
+	// discriminator whose value corresponds to more than one of its variants.
+	ErrAmbiguousVariant = fmt.Errorf("%w: ambiguous oneOf union variant", ErrAsyncAPI)
+
+	// ErrInvalidEnumValue is raised when unmarshaling a value that is not one
+	// of the possible values of an enum.
+	ErrInvalidEnumValue = fmt.Errorf("%w: invalid enum value", ErrAsyncAPI)
+
+	// ErrInvalidDuration is raised when parsing a duration that is not a valid
+	// ISO 8601 duration.
+	ErrInvalidDuration = fmt.Errorf("%w: invalid duration", ErrAsyncAPI)
+
+	// ErrInvalidURL is raised when parsing an invalid URL.
+	ErrInvalidURL = fmt.Errorf("%w: invalid URL", ErrAsyncAPI)
+
+	// ErrInvalidPropertyKey is raised when marshaling a pattern property whose
+	// key doesn't match its pattern.
+	ErrInvalidPropertyKey = fmt.Errorf("%w: invalid property key", ErrAsyncAPI)
+
+	// ErrUnknownProperty is raised when unmarshaling an object with a property
+	// that is not allowed, as additional properties are forbidden.
+	ErrUnknownProperty = fmt.Errorf("%w: unknown property", ErrAsyncAPI)
+
+	// ErrInvalidAvro is raised when an Avro schema or Avro encoded data is
+	// invalid, or doesn't correspond to the marshaled or unmarshaled value.
+	ErrInvalidAvro = fmt.Errorf("%w: invalid avro", ErrAsyncAPI)
+
+	// ErrInvalidProtobuf is raised when a protobuf file or protobuf encoded
+	// data is invalid, or doesn't correspond to the marshaled or unmarshaled value.
+	ErrInvalidProtobuf = fmt.Errorf("%w: invalid protobuf", ErrAsyncAPI)
+
+	// ErrUnsupportedContentType is raised when there is no codec registered
+	// for the content type of a message.
+	ErrUnsupportedContentType = fmt.Errorf("%w: unsupported content type", ErrAsyncAPI)
+
+	// ErrInvalidCodecValue is raised when a value can't be marshaled or
+	// unmarshaled by the codec of a content type.
+	ErrInvalidCodecValue = fmt.Errorf("%w: invalid value for codec", ErrAsyncAPI)
+
+	// ErrInvalidMessagePack is raised when MessagePack encoded data is invalid.
+	ErrInvalidMessagePack = fmt.Errorf("%w: invalid msgpack", ErrAsyncAPI)
+
+	// ErrInvalidCBOR is raised when CBOR encoded data is invalid.
+	ErrInvalidCBOR = fmt.Errorf("%w: invalid cbor", ErrAsyncAPI)
+
+	// ErrInvalidChannelParameter is raised when a channel parameter value is
+	// not one of its possible values, or can't be extracted from an address.
+	ErrInvalidChannelParameter = fmt.Errorf("%w: invalid channel parameter", ErrAsyncAPI)
+
+	// ErrWildcardsNotSupported is raised when subscribing with wildcards on a
+	// broker controller that doesn't implement WildcardBrokerController.
+	ErrWildcardsNotSupported = fmt.Errorf("%w: wildcard subscriptions not supported by broker", ErrAsyncAPI)
+
+	// ErrInvalidExample is raised when an example from the specification can't
+	// be decoded into the generated types.
+	ErrInvalidExample = fmt.Errorf("%w: invalid example", ErrAsyncAPI)
+
+	// ErrNoSubscription is raised when a message is injected into a fake
+	// controller on a channel that has not been subscribed.
+	ErrNoSubscription = fmt.Errorf("%w: no subscription on the channel", ErrAsyncAPI)
+
+	// ErrNoFakeReply is raised when a request is sent with a fake controller
+	// that has not been given any reply for it.
+	ErrNoFakeReply = fmt.Errorf("%w: no reply set on the fake controller", ErrAsyncAPI)
 )
+
+// BrokerError is the error returned by the generated code when the broker
+// controller fails to publish a message, in order to distinguish it from the
+// errors of the middlewares (e.g. with errors.As).
+type BrokerError struct {
+	Err error
+}
+
+// Error returns the error message of the broker error.
+func (e *BrokerError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error returned by the broker controller.
+func (e *BrokerError) Unwrap() error {
+	return e.Err
+}
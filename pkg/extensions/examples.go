@@ -0,0 +1,19 @@
+package extensions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DecodeExample decodes the JSON value of an example from the specification
+// into a generated type. The properties that don't exist in the type are
+// rejected, in order to detect the examples that don't match the types anymore.
+func DecodeExample(data string, v any) error {
+	d := json.NewDecoder(strings.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidExample, err)
+	}
+	return nil
+}
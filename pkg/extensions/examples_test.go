@@ -0,0 +1,35 @@
+package extensions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestExamplesSuite(t *testing.T) {
+	suite.Run(t, new(ExamplesSuite))
+}
+
+type ExamplesSuite struct {
+	suite.Suite
+}
+
+func (suite *ExamplesSuite) TestDecodeExample() {
+	var user struct {
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+	suite.Require().NoError(DecodeExample(`{"name":"bob","createdAt":"2024-01-02T03:04:05Z"}`, &user))
+	suite.Require().Equal("bob", user.Name)
+	suite.Require().Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), user.CreatedAt)
+
+	// Scalar values
+	var s string
+	suite.Require().NoError(DecodeExample(`"hello"`, &s))
+	suite.Require().Equal("hello", s)
+
+	// Unknown properties and invalid types
+	suite.Require().ErrorIs(DecodeExample(`{"name":"bob","email":"bob@example.com"}`, &user), ErrInvalidExample)
+	suite.Require().ErrorIs(DecodeExample(`{"name":42}`, &user), ErrInvalidExample)
+}
@@ -0,0 +1,165 @@
+package extensions
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// FakeCall is a call made on a fake controller.
+type FakeCall struct {
+	// Method is the name of the called method (e.g. "SendToUserSignup").
+	Method string
+	// Params are the channel parameters given to the method, if any.
+	Params any
+	// Address is the channel address given to the method, if any.
+	Address string
+	// Message is the message given to the method, if any.
+	Message any
+}
+
+// FakeTestingT is the interface of the test used to report the unmet
+// expectations of a fake controller (e.g. *testing.T).
+type FakeTestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// FakeController records the calls made on a fake controller generated from
+// the specification, gives the errors that its methods should return and
+// checks the expected calls. It is safe for concurrent use.
+type FakeController struct {
+	mutex    sync.Mutex
+	methods  []string
+	calls    []FakeCall
+	errors   map[string]error
+	expected map[string]int
+}
+
+// NewFakeController creates a new FakeController for a fake controller having
+// the given methods.
+func NewFakeController(methods ...string) *FakeController {
+	return &FakeController{
+		methods:  methods,
+		errors:   make(map[string]error),
+		expected: make(map[string]int),
+	}
+}
+
+func (f *FakeController) checkMethod(method string) {
+	if !slices.Contains(f.methods, method) {
+		panic(fmt.Sprintf("%q is not a method of the fake controller (expected one of %q)", method, f.methods))
+	}
+}
+
+// Record records a call and returns the error set for its method, if any.
+func (f *FakeController) Record(call FakeCall) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.checkMethod(call.Method)
+	f.calls = append(f.calls, call)
+	return f.errors[call.Method]
+}
+
+// Calls returns the calls made on the fake controller, in order.
+func (f *FakeController) Calls() []FakeCall {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return slices.Clone(f.calls)
+}
+
+// CallsTo returns the calls made to a method of the fake controller, in order.
+func (f *FakeController) CallsTo(method string) []FakeCall {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.checkMethod(method)
+	calls := make([]FakeCall, 0)
+	for _, c := range f.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// SetError sets the error that a method of the fake controller returns from
+// now on. A nil error makes it succeed again.
+func (f *FakeController) SetError(method string, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.checkMethod(method)
+	if err == nil {
+		delete(f.errors, method)
+		return
+	}
+	f.errors[method] = err
+}
+
+// Expect sets the number of times a method of the fake controller is expected
+// to be called, checked with AssertExpectations.
+func (f *FakeController) Expect(method string, times int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.checkMethod(method)
+	f.expected[method] = times
+}
+
+// AssertExpectations reports an error to the test for each method of the fake
+// controller that has not been called the expected number of times, and
+// returns true if all expectations are met.
+func (f *FakeController) AssertExpectations(t FakeTestingT) bool {
+	t.Helper()
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	ok := true
+	for _, method := range f.methods {
+		times, exists := f.expected[method]
+		if !exists {
+			continue
+		}
+
+		var calls int
+		for _, c := range f.calls {
+			if c.Method == method {
+				calls++
+			}
+		}
+
+		if calls != times {
+			t.Errorf("expected %d call(s) to %s, got %d", times, method, calls)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// Reset forgets the calls, errors and expectations of the fake controller.
+func (f *FakeController) Reset() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.calls = nil
+	f.errors = make(map[string]error)
+	f.expected = make(map[string]int)
+}
+
+// FakeMessages returns the messages given to a method of the fake controller,
+// in order.
+func FakeMessages[T any](f *FakeController, method string) []T {
+	calls := f.CallsTo(method)
+	msgs := make([]T, 0, len(calls))
+	for _, c := range calls {
+		if msg, ok := c.Message.(T); ok {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
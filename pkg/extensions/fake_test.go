@@ -0,0 +1,84 @@
+package extensions
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestFakeControllerSuite(t *testing.T) {
+	suite.Run(t, new(FakeControllerSuite))
+}
+
+type FakeControllerSuite struct {
+	suite.Suite
+}
+
+type fakeTestingT struct {
+	errors []string
+}
+
+func (t *fakeTestingT) Helper() {}
+
+func (t *fakeTestingT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (suite *FakeControllerSuite) TestRecord() {
+	f := NewFakeController("SendToA", "SendToB")
+
+	suite.Require().NoError(f.Record(FakeCall{Method: "SendToA", Message: "a1"}))
+	suite.Require().NoError(f.Record(FakeCall{Method: "SendToB", Message: 2}))
+	suite.Require().NoError(f.Record(FakeCall{Method: "SendToA", Message: "a2"}))
+
+	suite.Require().Len(f.Calls(), 3)
+	suite.Require().Equal([]FakeCall{
+		{Method: "SendToA", Message: "a1"},
+		{Method: "SendToA", Message: "a2"},
+	}, f.CallsTo("SendToA"))
+	suite.Require().Equal([]string{"a1", "a2"}, FakeMessages[string](f, "SendToA"))
+	suite.Require().Empty(FakeMessages[string](f, "SendToB"))
+
+	suite.Require().Panics(func() { _ = f.Record(FakeCall{Method: "SendToC"}) })
+	suite.Require().Panics(func() { f.CallsTo("SendToC") })
+}
+
+func (suite *FakeControllerSuite) TestSetError() {
+	f := NewFakeController("SendToA")
+	errSend := errors.New("send")
+
+	f.SetError("SendToA", errSend)
+	suite.Require().ErrorIs(f.Record(FakeCall{Method: "SendToA"}), errSend)
+
+	f.SetError("SendToA", nil)
+	suite.Require().NoError(f.Record(FakeCall{Method: "SendToA"}))
+	suite.Require().Len(f.CallsTo("SendToA"), 2)
+}
+
+func (suite *FakeControllerSuite) TestExpectations() {
+	f := NewFakeController("SendToA", "SendToB", "Close")
+	f.Expect("SendToA", 2)
+	f.Expect("Close", 0)
+
+	t := &fakeTestingT{}
+	suite.Require().False(f.AssertExpectations(t))
+	suite.Require().Equal([]string{"expected 2 call(s) to SendToA, got 0"}, t.errors)
+
+	_ = f.Record(FakeCall{Method: "SendToA"})
+	_ = f.Record(FakeCall{Method: "SendToA"})
+	_ = f.Record(FakeCall{Method: "SendToB"})
+	t = &fakeTestingT{}
+	suite.Require().True(f.AssertExpectations(t))
+	suite.Require().Empty(t.errors)
+
+	// Everything is forgotten on reset
+	f.SetError("SendToB", errors.New("send"))
+	f.Reset()
+	suite.Require().Empty(f.Calls())
+	suite.Require().NoError(f.Record(FakeCall{Method: "SendToB"}))
+	suite.Require().True(f.AssertExpectations(t))
+
+	suite.Require().Panics(func() { f.Expect("SendToC", 1) })
+}
@@ -0,0 +1,141 @@
+package extensions
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a duration that is (un)marshaled with the ISO 8601 duration
+// format (for example "PT1H30M"), used for strings with the 'duration' format.
+//
+// As years and months don't have a fixed duration, only weeks, days (of 24
+// hours), hours, minutes and seconds are supported.
+type Duration time.Duration
+
+var isoDurationRegexp = regexp.MustCompile(
+	`^(-)?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// ParseDuration parses an ISO 8601 duration.
+func ParseDuration(s string) (Duration, error) {
+	matches := isoDurationRegexp.FindStringSubmatch(s)
+	if matches == nil || s == "P" || s == "-P" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+	}
+
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute} {
+		if matches[i+2] == "" {
+			continue
+		}
+
+		v, err := strconv.ParseInt(matches[i+2], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q: %w", ErrInvalidDuration, s, err)
+		}
+		d += time.Duration(v) * unit
+	}
+
+	if matches[6] != "" {
+		v, err := strconv.ParseFloat(strings.Replace(matches[6], ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q: %w", ErrInvalidDuration, s, err)
+		}
+		d += time.Duration(v * float64(time.Second))
+	}
+
+	if matches[1] != "" {
+		d = -d
+	}
+
+	return Duration(d), nil
+}
+
+// String returns the ISO 8601 representation of the duration.
+func (d Duration) String() string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	v := time.Duration(d)
+	if v < 0 {
+		b.WriteString("-")
+		v = -v
+	}
+	b.WriteString("P")
+
+	if days := v / (24 * time.Hour); days > 0 {
+		b.WriteString(strconv.FormatInt(int64(days), 10) + "D")
+		v -= days * 24 * time.Hour
+	}
+	if v == 0 {
+		return b.String()
+	}
+
+	b.WriteString("T")
+	if hours := v / time.Hour; hours > 0 {
+		b.WriteString(strconv.FormatInt(int64(hours), 10) + "H")
+		v -= hours * time.Hour
+	}
+	if minutes := v / time.Minute; minutes > 0 {
+		b.WriteString(strconv.FormatInt(int64(minutes), 10) + "M")
+		v -= minutes * time.Minute
+	}
+	if v > 0 {
+		b.WriteString(strconv.FormatFloat(v.Seconds(), 'f', -1, 64) + "S")
+	}
+
+	return b.String()
+}
+
+// MarshalText will marshal the duration with the ISO 8601 format.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText will unmarshal a duration with the ISO 8601 format.
+func (d *Duration) UnmarshalText(data []byte) error {
+	parsed, err := ParseDuration(string(data))
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+// URL is an URL that is (un)marshaled as a string, used for strings with the
+// 'uri' format. The methods of 'url.URL' can be used on it directly.
+type URL struct {
+	url.URL
+}
+
+// ParseURL parses an URL.
+func ParseURL(s string) (URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return URL{}, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+
+	return URL{URL: *u}, nil
+}
+
+// MarshalText will marshal the URL as a string.
+func (u URL) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText will unmarshal the URL from a string.
+func (u *URL) UnmarshalText(data []byte) error {
+	parsed, err := ParseURL(string(data))
+	if err != nil {
+		return err
+	}
+
+	*u = parsed
+	return nil
+}
@@ -0,0 +1,111 @@
+package extensions
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestFormatsSuite(t *testing.T) {
+	suite.Run(t, new(FormatsSuite))
+}
+
+type FormatsSuite struct {
+	suite.Suite
+}
+
+func (suite *FormatsSuite) TestParseDuration() {
+	cases := map[string]time.Duration{
+		"PT0S":        0,
+		"P1W":         7 * 24 * time.Hour,
+		"P2D":         48 * time.Hour,
+		"PT1H30M":     90 * time.Minute,
+		"P1DT2H3M4S":  26*time.Hour + 3*time.Minute + 4*time.Second,
+		"PT1.5S":      1500 * time.Millisecond,
+		"PT0,25S":     250 * time.Millisecond,
+		"-PT10M":      -10 * time.Minute,
+		"P1DT0.001S":  24*time.Hour + time.Millisecond,
+		"PT100M":      100 * time.Minute,
+		"P0D":         0,
+		"PT36H":       36 * time.Hour,
+		"P3W2D":       23 * 24 * time.Hour,
+		"PT1H0M0.5S":  time.Hour + 500*time.Millisecond,
+		"-P1DT12H":    -36 * time.Hour,
+		"PT59M59.75S": 59*time.Minute + 59750*time.Millisecond,
+	}
+
+	for in, expected := range cases {
+		d, err := ParseDuration(in)
+		suite.Require().NoError(err, in)
+		suite.Require().Equal(expected, time.Duration(d), in)
+	}
+
+	for _, in := range []string{"", "P", "PT", "P1Y", "P1M", "1H", "PT1H2", "P1DT"} {
+		_, err := ParseDuration(in)
+		suite.Require().ErrorIs(err, ErrInvalidDuration, in)
+	}
+}
+
+func (suite *FormatsSuite) TestDurationString() {
+	cases := map[time.Duration]string{
+		0:                                  "PT0S",
+		48 * time.Hour:                     "P2D",
+		90 * time.Minute:                   "PT1H30M",
+		26*time.Hour + 4*time.Second:       "P1DT2H4S",
+		1500 * time.Millisecond:            "PT1.5S",
+		-10 * time.Minute:                  "-PT10M",
+		time.Hour + 500*time.Millisecond:   "PT1H0.5S",
+		59*time.Minute + 59*time.Second:    "PT59M59S",
+		7*24*time.Hour + 30*time.Second:    "P7DT30S",
+		-(36*time.Hour + 15*time.Minute):   "-P1DT12H15M",
+		250 * time.Millisecond:             "PT0.25S",
+		24*time.Hour + time.Millisecond:    "P1DT0.001S",
+		100 * time.Hour:                    "P4DT4H",
+		3*time.Minute + 20*time.Second:     "PT3M20S",
+		2*time.Hour + 5*time.Millisecond:   "PT2H0.005S",
+		time.Duration(1):                   "PT0.000000001S",
+		12*time.Hour + 12*time.Millisecond: "PT12H0.012S",
+	}
+
+	for in, expected := range cases {
+		suite.Require().Equal(expected, Duration(in).String())
+
+		// Check that the string can be parsed back
+		d, err := ParseDuration(expected)
+		suite.Require().NoError(err)
+		suite.Require().Equal(in, time.Duration(d), expected)
+	}
+}
+
+func (suite *FormatsSuite) TestDurationJSON() {
+	var v struct {
+		D Duration `json:"d"`
+	}
+
+	suite.Require().NoError(json.Unmarshal([]byte(`{"d":"PT1H"}`), &v))
+	suite.Require().Equal(Duration(time.Hour), v.D)
+
+	b, err := json.Marshal(v)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(`{"d":"PT1H"}`, string(b))
+
+	suite.Require().ErrorIs(json.Unmarshal([]byte(`{"d":"1h"}`), &v), ErrInvalidDuration)
+}
+
+func (suite *FormatsSuite) TestURLJSON() {
+	var v struct {
+		U URL `json:"u"`
+	}
+
+	suite.Require().NoError(json.Unmarshal([]byte(`{"u":"https://example.com/path?q=1"}`), &v))
+	suite.Require().Equal("example.com", v.U.Host)
+	suite.Require().Equal("1", v.U.Query().Get("q"))
+
+	b, err := json.Marshal(v)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(`{"u":"https://example.com/path?q=1"}`, string(b))
+
+	suite.Require().ErrorIs(json.Unmarshal([]byte(`{"u":":invalid"}`), &v), ErrInvalidURL)
+}
@@ -0,0 +1,158 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+)
+
+// ErrCircuitBreakerOpen is returned on publication when the circuit breaker is
+// open, i.e. when the message has not been sent because of previous failures.
+var ErrCircuitBreakerOpen = fmt.Errorf("%w: circuit breaker is open", extensions.ErrAsyncAPI)
+
+// CircuitBreakerState is the state of a circuit breaker.
+type CircuitBreakerState string
+
+const (
+	// CircuitBreakerStateIsClosed is the state where messages are published normally.
+	CircuitBreakerStateIsClosed CircuitBreakerState = "closed"
+	// CircuitBreakerStateIsOpen is the state where messages publication fails
+	// immediately, without reaching the broker.
+	CircuitBreakerStateIsOpen CircuitBreakerState = "open"
+	// CircuitBreakerStateIsHalfOpen is the state where a message publication
+	// is tried after the cooldown, in order to check if the broker is back.
+	CircuitBreakerStateIsHalfOpen CircuitBreakerState = "half-open"
+)
+
+// CircuitBreaker is a middleware that fails fast on publication after
+// 'maxFailures' consecutive errors when publishing messages. Once 'cooldown' is
+// elapsed, one publication is tried: the circuit is closed again if it succeeds,
+// or re-opened if it fails.
+//
+// Only the errors of the broker (i.e. extensions.BrokerError) are considered
+// as failures: the errors of the following middlewares (e.g. a validation)
+// don't change the state of the circuit breaker.
+//
+// The logger will be notified each time the circuit breaker state changes.
+func CircuitBreaker(logger extensions.Logger, maxFailures uint, cooldown time.Duration) extensions.Middleware {
+	cb := newCircuitBreaker(maxFailures, cooldown)
+
+	return func(ctx context.Context, _ *extensions.BrokerMessage, next extensions.NextMiddleware) error {
+		// Only apply to published messages
+		if ctx.Value(extensions.ContextKeyIsDirection) != "publication" {
+			return next(ctx)
+		}
+
+		// Check if the publication is allowed
+		allowed, state, changed := cb.allow()
+		if changed {
+			logCircuitBreakerState(ctx, logger, state)
+		}
+		if !allowed {
+			return ErrCircuitBreakerOpen
+		}
+
+		// Publish and record the result
+		err := next(ctx)
+		if state, changed := cb.record(err); changed {
+			logCircuitBreakerState(ctx, logger, state)
+		}
+
+		return err
+	}
+}
+
+func logCircuitBreakerState(ctx context.Context, logger extensions.Logger, state CircuitBreakerState) {
+	info := extensions.LogInfo{Key: "state", Value: state}
+	switch state {
+	case CircuitBreakerStateIsOpen:
+		logger.Error(ctx, "Circuit breaker opened", info)
+	case CircuitBreakerStateIsHalfOpen:
+		logger.Warning(ctx, "Circuit breaker half-opened", info)
+	default:
+		logger.Info(ctx, "Circuit breaker closed", info)
+	}
+}
+
+type circuitBreaker struct {
+	maxFailures uint
+	cooldown    time.Duration
+	now         func() time.Time
+
+	mutex    sync.Mutex
+	state    CircuitBreakerState
+	failures uint
+	openedAt time.Time
+	trying   bool
+}
+
+func newCircuitBreaker(maxFailures uint, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		maxFailures: maxFailures,
+		cooldown:    cooldown,
+		now:         time.Now,
+		state:       CircuitBreakerStateIsClosed,
+	}
+}
+
+// allow checks if an operation can be executed and returns the state of the
+// circuit breaker and if it has changed.
+func (cb *circuitBreaker) allow() (allowed bool, state CircuitBreakerState, changed bool) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	// Half-open the circuit if the cooldown is elapsed
+	if cb.state == CircuitBreakerStateIsOpen && cb.now().Sub(cb.openedAt) >= cb.cooldown {
+		cb.state = CircuitBreakerStateIsHalfOpen
+		changed = true
+	}
+
+	switch cb.state {
+	case CircuitBreakerStateIsOpen:
+		return false, cb.state, changed
+	case CircuitBreakerStateIsHalfOpen:
+		// Only let one operation try at a time
+		if cb.trying {
+			return false, cb.state, changed
+		}
+		cb.trying = true
+		return true, cb.state, changed
+	default:
+		return true, cb.state, changed
+	}
+}
+
+// record records the result of an operation and returns the state of the
+// circuit breaker and if it has changed. Only the broker errors are failures,
+// the other errors leave the state unchanged.
+func (cb *circuitBreaker) record(err error) (state CircuitBreakerState, changed bool) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	previous := cb.state
+	cb.trying = false
+
+	var brokerErr *extensions.BrokerError
+	switch {
+	case err != nil && !errors.As(err, &brokerErr):
+		return cb.state, false
+	case err == nil:
+		cb.failures = 0
+		cb.state = CircuitBreakerStateIsClosed
+	case cb.state == CircuitBreakerStateIsHalfOpen:
+		cb.state = CircuitBreakerStateIsOpen
+		cb.openedAt = cb.now()
+	default:
+		cb.failures++
+		if cb.failures >= cb.maxFailures {
+			cb.state = CircuitBreakerStateIsOpen
+			cb.openedAt = cb.now()
+		}
+	}
+
+	return cb.state, previous != cb.state
+}
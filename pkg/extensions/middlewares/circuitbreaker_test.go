@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/stretchr/testify/suite"
+)
+
+var errBroker = &extensions.BrokerError{Err: errors.New("broker error")}
+
+func TestCircuitBreakerSuite(t *testing.T) {
+	suite.Run(t, new(CircuitBreakerSuite))
+}
+
+type CircuitBreakerSuite struct {
+	suite.Suite
+}
+
+func (suite *CircuitBreakerSuite) TestOpenAfterConsecutiveFailures() {
+	cb := newCircuitBreaker(2, time.Minute)
+
+	for _, err := range []error{errBroker, nil, errBroker} {
+		allowed, _, _ := cb.allow()
+		suite.Require().True(allowed)
+		cb.record(err)
+	}
+
+	allowed, _, _ := cb.allow()
+	suite.Require().True(allowed)
+	state, changed := cb.record(errBroker)
+	suite.Require().Equal(CircuitBreakerStateIsOpen, state)
+	suite.Require().True(changed)
+
+	allowed, _, _ = cb.allow()
+	suite.Require().False(allowed)
+}
+
+func (suite *CircuitBreakerSuite) TestHalfOpenAfterCooldown() {
+	now := time.Now()
+	cb := newCircuitBreaker(1, time.Minute)
+	cb.now = func() time.Time { return now }
+
+	cb.allow()
+	cb.record(errBroker)
+
+	// Failed try should re-open the circuit
+	now = now.Add(time.Minute)
+	allowed, state, changed := cb.allow()
+	suite.Require().True(allowed)
+	suite.Require().Equal(CircuitBreakerStateIsHalfOpen, state)
+	suite.Require().True(changed)
+
+	// Only one try at a time is allowed
+	allowed, _, _ = cb.allow()
+	suite.Require().False(allowed)
+
+	state, _ = cb.record(errBroker)
+	suite.Require().Equal(CircuitBreakerStateIsOpen, state)
+
+	// Successful try should close the circuit
+	now = now.Add(time.Minute)
+	allowed, _, _ = cb.allow()
+	suite.Require().True(allowed)
+	state, changed = cb.record(nil)
+	suite.Require().Equal(CircuitBreakerStateIsClosed, state)
+	suite.Require().True(changed)
+}
+
+func (suite *CircuitBreakerSuite) TestIgnoreNonBrokerErrors() {
+	mw := CircuitBreaker(extensions.DummyLogger{}, 1, time.Minute)
+	ctx := context.WithValue(context.Background(), extensions.ContextKeyIsDirection, "publication")
+
+	// An error from a middleware or the message doesn't open the circuit
+	errValidation := errors.New("validation error")
+	suite.Require().ErrorIs(mw(ctx, nil, func(context.Context) error { return errValidation }), errValidation)
+	suite.Require().NoError(mw(ctx, nil, func(context.Context) error { return nil }))
+
+	// An error from the broker opens it
+	suite.Require().ErrorIs(mw(ctx, nil, func(context.Context) error { return errBroker }), errBroker)
+	suite.Require().ErrorIs(mw(ctx, nil, func(context.Context) error { return nil }), ErrCircuitBreakerOpen)
+}
@@ -0,0 +1,118 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+)
+
+// DeduplicationKeyFn is the function that extracts the key identifying a
+// message for deduplication. An empty key means that the message can't be
+// deduplicated and will always be processed.
+type DeduplicationKeyFn func(ctx context.Context, msg *extensions.BrokerMessage) string
+
+// DeduplicationKeyFromHeaders returns a key function that will use the value
+// of the first non-empty header among the given ones (for example a message ID
+// header, then a correlation ID header).
+func DeduplicationKeyFromHeaders(headers ...string) DeduplicationKeyFn {
+	return func(_ context.Context, msg *extensions.BrokerMessage) string {
+		for _, h := range headers {
+			if v := msg.Headers[h]; len(v) > 0 {
+				return string(v)
+			}
+		}
+
+		return ""
+	}
+}
+
+// Deduplication is a middleware that skips received messages that have already
+// been processed successfully. Skipped messages are acknowledged without calling
+// the subscription callback.
+//
+// The message key is recorded in the store only once the following middlewares
+// and the subscription callback have succeeded, so a failed message will be
+// processed again on redelivery. While a message is processed, its duplicates
+// received by the same middleware wait for the end of its processing, then are
+// skipped if it succeeded or processed if it failed.
+func Deduplication(store extensions.DeduplicationStore, key DeduplicationKeyFn) extensions.Middleware {
+	var inFlight deduplicationInFlight
+	return func(ctx context.Context, msg *extensions.BrokerMessage, next extensions.NextMiddleware) error {
+		// Only deduplicate received messages
+		if !isReceived(ctx) {
+			return next(ctx)
+		}
+
+		// Get the message key, or process it if it can't be deduplicated
+		id := key(ctx, msg)
+		if id == "" {
+			return next(ctx)
+		}
+
+		// Wait for the processing of the duplicates in progress
+		done, err := inFlight.start(ctx, id)
+		if err != nil {
+			return err
+		}
+		defer done()
+
+		// Skip the message if it has already been processed
+		processed, err := store.Has(ctx, id)
+		if err != nil {
+			return err
+		} else if processed {
+			return fmt.Errorf("%w: message %q has already been processed", extensions.ErrSkipMessage, id)
+		}
+
+		// Process the message
+		if err := next(ctx); err != nil {
+			return err
+		}
+
+		// Record the message as processed
+		_, err = store.Add(ctx, id)
+		return err
+	}
+}
+
+// deduplicationInFlight are the keys of the messages being processed.
+type deduplicationInFlight struct {
+	mutex sync.Mutex
+	keys  map[string]chan any
+}
+
+// start waits until no message with the key is processed, then marks the key
+// as being processed until the returned function is called.
+func (f *deduplicationInFlight) start(ctx context.Context, id string) (done func(), err error) {
+	for {
+		f.mutex.Lock()
+		processing, ok := f.keys[id]
+		if !ok {
+			break
+		}
+		f.mutex.Unlock()
+
+		select {
+		case <-processing:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	defer f.mutex.Unlock()
+
+	if f.keys == nil {
+		f.keys = make(map[string]chan any)
+	}
+	processing := make(chan any)
+	f.keys[id] = processing
+
+	return func() {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+
+		delete(f.keys, id)
+		close(processing)
+	}, nil
+}
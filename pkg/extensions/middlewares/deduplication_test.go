@@ -0,0 +1,142 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions/deduplicationstores"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDeduplicationSuite(t *testing.T) {
+	suite.Run(t, new(DeduplicationSuite))
+}
+
+type DeduplicationSuite struct {
+	suite.Suite
+}
+
+func (suite *DeduplicationSuite) TestSkipAlreadyProcessed() {
+	mw := Deduplication(deduplicationstores.NewMemory(), DeduplicationKeyFromHeaders("messageId", "correlationId"))
+	ctx := context.WithValue(context.Background(), extensions.ContextKeyIsDirection, "reception")
+	msg := extensions.BrokerMessage{Headers: map[string][]byte{"correlationId": []byte("1234")}}
+
+	var calls int
+	next := func(context.Context) error {
+		calls++
+		return nil
+	}
+
+	suite.Require().NoError(mw(ctx, &msg, next))
+	suite.Require().ErrorIs(mw(ctx, &msg, next), extensions.ErrSkipMessage)
+	suite.Require().Equal(1, calls)
+}
+
+func (suite *DeduplicationSuite) TestRecordOnlyOnSuccess() {
+	mw := Deduplication(deduplicationstores.NewMemory(), DeduplicationKeyFromHeaders("messageId"))
+	ctx := context.WithValue(context.Background(), extensions.ContextKeyIsDirection, "reception")
+	msg := extensions.BrokerMessage{Headers: map[string][]byte{"messageId": []byte("1234")}}
+
+	errHandler := errors.New("handler error")
+	suite.Require().ErrorIs(mw(ctx, &msg, func(context.Context) error { return errHandler }), errHandler)
+
+	var called bool
+	suite.Require().NoError(mw(ctx, &msg, func(context.Context) error {
+		called = true
+		return nil
+	}))
+	suite.Require().True(called)
+}
+
+func (suite *DeduplicationSuite) TestIgnorePublication() {
+	mw := Deduplication(deduplicationstores.NewMemory(), DeduplicationKeyFromHeaders("messageId"))
+	ctx := context.WithValue(context.Background(), extensions.ContextKeyIsDirection, "publication")
+	msg := extensions.BrokerMessage{Headers: map[string][]byte{"messageId": []byte("1234")}}
+
+	var calls int
+	next := func(context.Context) error {
+		calls++
+		return nil
+	}
+
+	suite.Require().NoError(mw(ctx, &msg, next))
+	suite.Require().NoError(mw(ctx, &msg, next))
+	suite.Require().Equal(2, calls)
+}
+
+func (suite *DeduplicationSuite) TestSkipAlreadyProcessedReply() {
+	mw := Deduplication(deduplicationstores.NewMemory(), DeduplicationKeyFromHeaders("messageId"))
+	ctx := context.WithValue(context.Background(), extensions.ContextKeyIsDirection, "wait-for")
+	msg := extensions.BrokerMessage{Headers: map[string][]byte{"messageId": []byte("1234")}}
+
+	var calls int
+	next := func(context.Context) error {
+		calls++
+		return nil
+	}
+
+	suite.Require().NoError(mw(ctx, &msg, next))
+	suite.Require().ErrorIs(mw(ctx, &msg, next), extensions.ErrSkipMessage)
+	suite.Require().Equal(1, calls)
+}
+
+func (suite *DeduplicationSuite) TestConcurrentDuplicates() {
+	mw := Deduplication(deduplicationstores.NewMemory(), DeduplicationKeyFromHeaders("messageId"))
+	ctx := context.WithValue(context.Background(), extensions.ContextKeyIsDirection, "reception")
+
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msg := extensions.BrokerMessage{Headers: map[string][]byte{"messageId": []byte("1234")}}
+			_ = mw(ctx, &msg, func(context.Context) error {
+				calls.Add(1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	suite.Require().Equal(int32(1), calls.Load())
+}
+
+func (suite *DeduplicationSuite) TestDuplicateWaitsForFailedProcessing() {
+	mw := Deduplication(deduplicationstores.NewMemory(), DeduplicationKeyFromHeaders("messageId"))
+	ctx := context.WithValue(context.Background(), extensions.ContextKeyIsDirection, "reception")
+	msg := extensions.BrokerMessage{Headers: map[string][]byte{"messageId": []byte("1234")}}
+
+	// Start processing the first copy, failing once the duplicate is received
+	started, release := make(chan any), make(chan any)
+	errHandler := errors.New("handler error")
+	first := make(chan error)
+	go func() {
+		first <- mw(ctx, &msg, func(context.Context) error {
+			close(started)
+			<-release
+			return errHandler
+		})
+	}()
+	<-started
+
+	// Receive the duplicate during the processing: it is processed once the
+	// first copy failed, instead of being skipped
+	second := make(chan error)
+	var called atomic.Bool
+	go func() {
+		second <- mw(ctx, &msg, func(context.Context) error {
+			called.Store(true)
+			return nil
+		})
+	}()
+	close(release)
+
+	suite.Require().ErrorIs(<-first, errHandler)
+	suite.Require().NoError(<-second)
+	suite.Require().True(called.Load())
+}
@@ -0,0 +1,14 @@
+package middlewares
+
+import (
+	"context"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+)
+
+// isReceived returns true if the message is received from the broker, either
+// from a subscription or as the reply of a request.
+func isReceived(ctx context.Context) bool {
+	direction := ctx.Value(extensions.ContextKeyIsDirection)
+	return direction == "reception" || direction == "wait-for"
+}
@@ -0,0 +1,142 @@
+package middlewares
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+)
+
+// RateLimiter is a middleware that limits the number of received messages
+// processed per second on each subscribed channel, based on a token bucket: up
+// to 'burst' messages can be processed at once, then tokens are refilled at
+// 'limit' tokens per second. Messages exceeding the limit wait for the next
+// available token. A 'limit' lower or equal to 0 disables the throttling.
+//
+// The messages received on the different addresses of a subscription (e.g.
+// with wildcard subscriptions) share the same bucket. The buckets that are
+// full again are removed as new channels are received, so the channels that
+// are not used anymore are forgotten.
+//
+// The logger will be notified when a channel starts and stops being throttled.
+func RateLimiter(logger extensions.Logger, limit float64, burst int) extensions.Middleware {
+	var mutex sync.Mutex
+	buckets := make(map[string]*tokenBucket)
+	nextEviction := rateLimiterMinEviction
+
+	return func(ctx context.Context, _ *extensions.BrokerMessage, next extensions.NextMiddleware) error {
+		// Only limit received messages, when the throttling is enabled
+		if !isReceived(ctx) || limit <= 0 {
+			return next(ctx)
+		}
+
+		// Get the bucket corresponding to the subscribed channel, or to the
+		// channel if the subscription is unknown
+		channel, ok := ctx.Value(extensions.ContextKeyIsSubscribedChannel).(string)
+		if !ok {
+			channel, _ = ctx.Value(extensions.ContextKeyIsChannel).(string)
+		}
+		mutex.Lock()
+		bucket, exists := buckets[channel]
+		if !exists {
+			// Remove the full buckets when the number of buckets has doubled
+			if len(buckets) >= nextEviction {
+				evictFullBuckets(buckets)
+				nextEviction = max(2*len(buckets), rateLimiterMinEviction)
+			}
+
+			bucket = newTokenBucket(limit, burst)
+			buckets[channel] = bucket
+		}
+		mutex.Unlock()
+
+		// Wait for a token
+		wait, throttled, changed := bucket.take()
+		if changed && throttled {
+			logger.Warning(ctx, "Rate limit reached, throttling channel",
+				extensions.LogInfo{Key: "wait", Value: wait.String()})
+		} else if changed {
+			logger.Info(ctx, "Rate limit not reached anymore, stop throttling channel")
+		}
+
+		if wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return extensions.ErrContextCanceled
+			}
+		}
+
+		return next(ctx)
+	}
+}
+
+// rateLimiterMinEviction is the minimum number of buckets from which the full
+// buckets are removed.
+const rateLimiterMinEviction = 64
+
+// evictFullBuckets removes the buckets that are full, as they are the same as
+// new buckets.
+func evictFullBuckets(buckets map[string]*tokenBucket) {
+	for channel, bucket := range buckets {
+		if bucket.isFull() {
+			delete(buckets, channel)
+		}
+	}
+}
+
+type tokenBucket struct {
+	limit float64
+	burst float64
+	now   func() time.Time
+
+	mutex     sync.Mutex
+	tokens    float64
+	last      time.Time
+	throttled bool
+}
+
+func newTokenBucket(limit float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		limit:  limit,
+		burst:  float64(burst),
+		now:    time.Now,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// take reserves a token and returns the time to wait before using it, if the
+// bucket is throttled and if this throttling state has changed.
+func (tb *tokenBucket) take() (wait time.Duration, throttled, changed bool) {
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
+
+	// Refill the bucket based on elapsed time
+	now := tb.now()
+	tb.tokens = math.Min(tb.burst, tb.tokens+now.Sub(tb.last).Seconds()*tb.limit)
+	tb.last = now
+
+	// Take a token, even if it is not available yet
+	tb.tokens--
+	if tb.tokens < 0 && tb.limit > 0 {
+		wait = time.Duration(-tb.tokens / tb.limit * float64(time.Second))
+	}
+
+	// Update throttling state
+	throttled = wait > 0
+	changed = throttled != tb.throttled
+	tb.throttled = throttled
+
+	return wait, throttled, changed
+}
+
+// isFull checks if the bucket has been refilled up to the burst.
+func (tb *tokenBucket) isFull() bool {
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
+
+	return tb.tokens+tb.now().Sub(tb.last).Seconds()*tb.limit >= tb.burst
+}
@@ -0,0 +1,98 @@
+package middlewares
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRateLimiterSuite(t *testing.T) {
+	suite.Run(t, new(RateLimiterSuite))
+}
+
+type RateLimiterSuite struct {
+	suite.Suite
+}
+
+func (suite *RateLimiterSuite) TestBurstThenThrottle() {
+	now := time.Now()
+	bucket := newTokenBucket(10, 2)
+	bucket.now = func() time.Time { return now }
+	bucket.last = now
+
+	// Burst should not wait
+	for i := 0; i < 2; i++ {
+		wait, throttled, _ := bucket.take()
+		suite.Require().Zero(wait)
+		suite.Require().False(throttled)
+	}
+
+	// Next one should wait for a refill
+	wait, throttled, changed := bucket.take()
+	suite.Require().Equal(100*time.Millisecond, wait)
+	suite.Require().True(throttled)
+	suite.Require().True(changed)
+
+	// After refill, it should not be throttled anymore
+	now = now.Add(time.Second)
+	wait, throttled, changed = bucket.take()
+	suite.Require().Zero(wait)
+	suite.Require().False(throttled)
+	suite.Require().True(changed)
+}
+
+func (suite *RateLimiterSuite) TestBucketPerSubscribedChannel() {
+	mw := RateLimiter(extensions.DummyLogger{}, 0.001, 1)
+	next := func(context.Context) error { return nil }
+
+	receive := func(subscribed, channel string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		ctx = context.WithValue(ctx, extensions.ContextKeyIsDirection, "reception")
+		ctx = context.WithValue(ctx, extensions.ContextKeyIsSubscribedChannel, subscribed)
+		ctx = context.WithValue(ctx, extensions.ContextKeyIsChannel, channel)
+		return mw(ctx, &extensions.BrokerMessage{}, next)
+	}
+
+	// The addresses received on a subscription share its bucket
+	suite.Require().NoError(receive("orders.*", "orders.eu"))
+	suite.Require().ErrorIs(receive("orders.*", "orders.us"), extensions.ErrContextCanceled)
+
+	// Another subscription has its own bucket
+	suite.Require().NoError(receive("orders.eu", "orders.eu"))
+}
+
+func (suite *RateLimiterSuite) TestDisabled() {
+	mw := RateLimiter(extensions.DummyLogger{}, 0, 0)
+	ctx := context.WithValue(context.Background(), extensions.ContextKeyIsDirection, "reception")
+
+	for i := 0; i < 10; i++ {
+		suite.Require().NoError(mw(ctx, &extensions.BrokerMessage{}, func(context.Context) error { return nil }))
+	}
+}
+
+func (suite *RateLimiterSuite) TestEvictFullBuckets() {
+	now := time.Now()
+	newBucket := func() *tokenBucket {
+		bucket := newTokenBucket(10, 1)
+		bucket.now = func() time.Time { return now }
+		bucket.last = now
+		return bucket
+	}
+
+	used, unused := newBucket(), newBucket()
+	used.take()
+	buckets := map[string]*tokenBucket{"used": used, "unused": unused}
+
+	// Only the full bucket is removed
+	evictFullBuckets(buckets)
+	suite.Require().Equal(map[string]*tokenBucket{"used": used}, buckets)
+
+	// Then the used one once it is refilled
+	now = now.Add(time.Second)
+	evictFullBuckets(buckets)
+	suite.Require().Empty(buckets)
+}
@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+)
+
+// ErrTimeout is returned on reception when the middlewares following the
+// timeout middleware and the subscription callback did not complete in time.
+// It also wraps context.DeadlineExceeded.
+var ErrTimeout = fmt.Errorf("%w: message processing timed out", extensions.ErrAsyncAPI)
+
+// Timeout is a middleware that bounds the execution time of the middlewares
+// coming after it and of the subscription callback on received messages.
+//
+// The context passed to the subscription callback will have the corresponding
+// deadline. If it is exceeded, an error wrapping ErrTimeout is returned: it will
+// be given to the error handler and the message will be negatively acknowledged,
+// while the callback keeps running in the background until it returns.
+//
+// The logger will be notified when such a callback returns, as its result
+// (and its acknowledgment) is ignored.
+//
+// NOTE: as the following code is executed in a separate goroutine, a Recovery
+// middleware should be set after this one to catch panics.
+func Timeout(logger extensions.Logger, timeout time.Duration) extensions.Middleware {
+	return func(ctx context.Context, _ *extensions.BrokerMessage, next extensions.NextMiddleware) error {
+		// Only apply to received messages
+		if !isReceived(ctx) {
+			return next(ctx)
+		}
+
+		// Set the deadline on context
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		// Execute the next middlewares asynchronously
+		done := make(chan error, 1)
+		go func() {
+			done <- next(ctx)
+		}()
+
+		// Wait for the end of the execution or the deadline
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			go logLateProcessing(ctx, logger, timeout, done)
+			return fmt.Errorf("%w: not processed after %s: %w", ErrTimeout, timeout, ctx.Err())
+		}
+	}
+}
+
+// logLateProcessing waits for the end of the processing of a message that timed
+// out, and notifies the logger that its result is ignored.
+func logLateProcessing(ctx context.Context, logger extensions.Logger, timeout time.Duration, done chan error) {
+	err := <-done
+	infos := []extensions.LogInfo{{Key: "timeout", Value: timeout.String()}}
+	if err != nil {
+		infos = append(infos, extensions.LogInfo{Key: "error", Value: err.Error()})
+	}
+	logger.Warning(ctx, "Message processed after timeout, its result and acknowledgment are ignored", infos...)
+}
@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTimeoutSuite(t *testing.T) {
+	suite.Run(t, new(TimeoutSuite))
+}
+
+type TimeoutSuite struct {
+	suite.Suite
+}
+
+// warningsLogger is a logger sending the warnings messages to a channel.
+type warningsLogger struct {
+	extensions.DummyLogger
+	warnings chan string
+}
+
+func (l warningsLogger) Warning(_ context.Context, msg string, _ ...extensions.LogInfo) {
+	l.warnings <- msg
+}
+
+func (suite *TimeoutSuite) TestTimeout() {
+	logger := warningsLogger{warnings: make(chan string, 1)}
+	mw := Timeout(logger, 10*time.Millisecond)
+	ctx := context.WithValue(context.Background(), extensions.ContextKeyIsDirection, "reception")
+
+	block := make(chan any)
+	err := mw(ctx, &extensions.BrokerMessage{}, func(context.Context) error {
+		<-block
+		return nil
+	})
+	suite.Require().ErrorIs(err, ErrTimeout)
+	suite.Require().ErrorIs(err, context.DeadlineExceeded)
+
+	// The end of the processing is logged
+	suite.Require().Empty(logger.warnings)
+	close(block)
+	suite.Require().Contains(<-logger.warnings, "after timeout")
+}
+
+func (suite *TimeoutSuite) TestDeadlineOnContext() {
+	mw := Timeout(extensions.DummyLogger{}, time.Minute)
+	ctx := context.WithValue(context.Background(), extensions.ContextKeyIsDirection, "reception")
+
+	err := mw(ctx, &extensions.BrokerMessage{}, func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		suite.Require().True(ok)
+		return nil
+	})
+	suite.Require().NoError(err)
+}
@@ -0,0 +1,254 @@
+package extensions
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// MessagePackCodec is the codec of MessagePack payloads. The values are
+// encoded with the same representation as in JSON (e.g. the byte slices are
+// base64 strings), so the generated types don't need specific marshalers.
+type MessagePackCodec struct{}
+
+// Marshal marshals the value into MessagePack.
+func (MessagePackCodec) Marshal(v any) ([]byte, error) {
+	generic, err := toGenericValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return appendMessagePack(nil, generic)
+}
+
+// Unmarshal unmarshals the MessagePack data into the value.
+func (MessagePackCodec) Unmarshal(data []byte, v any) error {
+	r := messagePackReader{data: data}
+	generic, err := r.value()
+	if err != nil {
+		return err
+	}
+	if r.pos != len(data) {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidMessagePack, len(data)-r.pos)
+	}
+	return fromGenericValue(generic, v)
+}
+
+//nolint:cyclop // Not necessary to split the value types
+func appendMessagePack(b []byte, v any) ([]byte, error) {
+	var err error
+	switch t := v.(type) {
+	case nil:
+		b = append(b, 0xc0)
+	case bool:
+		if t {
+			b = append(b, 0xc3)
+		} else {
+			b = append(b, 0xc2)
+		}
+	case json.Number:
+		b = appendMessagePackNumber(b, t)
+	case string:
+		b = appendMessagePackHeader(b, len(t), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		b = append(b, t...)
+	case []any:
+		b = appendMessagePackHeader(b, len(t), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, e := range t {
+			if b, err = appendMessagePack(b, e); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]any:
+		b = appendMessagePackHeader(b, len(t), 0x80, 16, 0, 0xde, 0xdf)
+		for _, k := range SortedKeys(t) {
+			b = appendMessagePackHeader(b, len(k), 0xa0, 32, 0xd9, 0xda, 0xdb)
+			b = append(b, k...)
+			if b, err = appendMessagePack(b, t[k]); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: unexpected value %T", ErrInvalidMessagePack, v)
+	}
+
+	return b, nil
+}
+
+func appendMessagePackNumber(b []byte, n json.Number) []byte {
+	if i, err := n.Int64(); err == nil {
+		switch {
+		case i >= 0 && i < 128, i >= -32 && i < 0:
+			return append(b, byte(i))
+		case i >= math.MinInt8 && i <= math.MaxInt8:
+			return append(b, 0xd0, byte(i))
+		case i >= math.MinInt16 && i <= math.MaxInt16:
+			return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(i))
+		case i >= math.MinInt32 && i <= math.MaxInt32:
+			return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(i))
+		default:
+			return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(i))
+		}
+	}
+
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), u)
+	}
+
+	f, _ := n.Float64()
+	return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(f))
+}
+
+// appendMessagePackHeader appends the header of a string, an array or a map
+// with its length, using the fixed format if the length is small enough.
+func appendMessagePackHeader(b []byte, length int, fixed byte, fixedMax int, code8, code16, code32 byte) []byte {
+	switch {
+	case length < fixedMax:
+		return append(b, fixed|byte(length))
+	case code8 != 0 && length <= math.MaxUint8:
+		return append(b, code8, byte(length))
+	case length <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code16), uint16(length))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, code32), uint32(length))
+	}
+}
+
+type messagePackReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *messagePackReader) read(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.data) {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidMessagePack)
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *messagePackReader) uint(size int) (uint64, error) {
+	b, err := r.read(size)
+	if err != nil {
+		return 0, err
+	}
+
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n, nil
+}
+
+//nolint:cyclop,funlen,gocyclo // Not necessary to split the formats
+func (r *messagePackReader) value() (any, error) {
+	b, err := r.read(1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := b[0]; {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xe0 == 0xa0:
+		return r.string(int(c & 0x1f))
+	case c&0xf0 == 0x90:
+		return r.array(int(c & 0x0f))
+	case c&0xf0 == 0x80:
+		return r.object(int(c & 0x0f))
+	case c == 0xc0:
+		return nil, nil
+	case c == 0xc2, c == 0xc3:
+		return c == 0xc3, nil
+	case c >= 0xc4 && c <= 0xc6:
+		n, err := r.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return r.read(int(n))
+	case c == 0xca:
+		n, err := r.uint(4)
+		return float64(math.Float32frombits(uint32(n))), err
+	case c == 0xcb:
+		n, err := r.uint(8)
+		return math.Float64frombits(n), err
+	case c >= 0xcc && c <= 0xcf:
+		return r.uint(1 << (c - 0xcc))
+	case c >= 0xd0 && c <= 0xd3:
+		size := 1 << (c - 0xd0)
+		n, err := r.uint(size)
+		// Extend the sign of the integer
+		shift := 64 - 8*size
+		return int64(n<<shift) >> shift, err
+	case c >= 0xd9 && c <= 0xdb:
+		n, err := r.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return r.string(int(n))
+	case c == 0xdc, c == 0xdd:
+		n, err := r.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return r.array(int(n))
+	case c == 0xde, c == 0xdf:
+		n, err := r.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return r.object(int(n))
+	default:
+		return nil, fmt.Errorf("%w: unsupported format 0x%x", ErrInvalidMessagePack, c)
+	}
+}
+
+func (r *messagePackReader) string(n int) (string, error) {
+	b, err := r.read(n)
+	return string(b), err
+}
+
+func (r *messagePackReader) array(n int) ([]any, error) {
+	if n > len(r.data)-r.pos {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidMessagePack)
+	}
+
+	a := make([]any, n)
+	for i := range a {
+		v, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		a[i] = v
+	}
+	return a, nil
+}
+
+func (r *messagePackReader) object(n int) (map[any]any, error) {
+	if n > len(r.data)-r.pos {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidMessagePack)
+	}
+
+	m := make(map[any]any, n)
+	for i := 0; i < n; i++ {
+		k, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		switch t := k.(type) {
+		case []byte:
+			k = string(t)
+		case []any, map[any]any:
+			return nil, fmt.Errorf("%w: unsupported map key %v", ErrInvalidMessagePack, k)
+		}
+		v, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+	return m, nil
+}
@@ -0,0 +1,179 @@
+package extensions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Nullable is a value that can be absent, explicitly null or set. Its zero
+// value is absent.
+//
+// It is used by generated code for optional and nullable fields, in order to
+// distinguish an absent field from a field explicitly set to null (for example
+// in events describing a partial update).
+type Nullable[T any] struct {
+	value   T
+	present bool
+	null    bool
+}
+
+// NewNullable creates a new Nullable set with the given value.
+func NewNullable[T any](v T) Nullable[T] {
+	return Nullable[T]{value: v, present: true}
+}
+
+// Null creates a new Nullable explicitly set to null.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{present: true, null: true}
+}
+
+// IsPresent checks if the Nullable is present, i.e. set with a value or
+// explicitly set to null.
+func (n Nullable[T]) IsPresent() bool {
+	return n.present
+}
+
+// IsNull checks if the Nullable is explicitly set to null.
+func (n Nullable[T]) IsNull() bool {
+	return n.present && n.null
+}
+
+// IsSet checks if the Nullable is set with a value.
+func (n Nullable[T]) IsSet() bool {
+	return n.present && !n.null
+}
+
+// Get returns the value of the Nullable and true if it is set with a value,
+// or the zero value and false otherwise.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.IsSet()
+}
+
+// ValueOr returns the value of the Nullable if it is set with a value, or the
+// given default value otherwise.
+func (n Nullable[T]) ValueOr(def T) T {
+	if !n.IsSet() {
+		return def
+	}
+	return n.value
+}
+
+// Set sets the Nullable with a value.
+func (n *Nullable[T]) Set(v T) {
+	*n = NewNullable(v)
+}
+
+// SetNull explicitly sets the Nullable to null.
+func (n *Nullable[T]) SetNull() {
+	*n = Null[T]()
+}
+
+// Unset sets the Nullable as absent.
+func (n *Nullable[T]) Unset() {
+	*n = Nullable[T]{}
+}
+
+// reflectValue returns the value of the Nullable and true if it is set with a
+// value, for the codecs that don't know its type.
+func (n Nullable[T]) reflectValue() (reflect.Value, bool) {
+	return reflect.ValueOf(n.value), n.IsSet()
+}
+
+// reflectSet sets the Nullable as present with a value and returns this value
+// so it can be set, for the codecs that don't know its type.
+func (n *Nullable[T]) reflectSet() reflect.Value {
+	n.present, n.null = true, false
+	return reflect.ValueOf(&n.value).Elem()
+}
+
+// String returns a string representation of the Nullable, mainly for logging
+// and debugging purposes.
+func (n Nullable[T]) String() string {
+	switch {
+	case !n.present:
+		return "<absent>"
+	case n.null:
+		return "<null>"
+	default:
+		return fmt.Sprint(n.value)
+	}
+}
+
+// MarshalJSON marshals the value of the Nullable, or null if it is null or
+// absent. The absent fields can be removed from a JSON object with 'OmitJSONFields'.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.IsSet() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.value)
+}
+
+// UnmarshalJSON unmarshals the Nullable value, or sets it to null if the data
+// is null. If the field is absent from the JSON, the Nullable is kept absent.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		n.SetNull()
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	n.Set(v)
+
+	return nil
+}
+
+// OmitJSONFields removes the given fields from a JSON object, keeping the
+// other fields in the same order.
+func OmitJSONFields(data []byte, fields ...string) ([]byte, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	omitted := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		omitted[f] = true
+	}
+
+	// Read the object opening
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil {
+		return nil, err
+	} else if t != json.Delim('{') {
+		return nil, fmt.Errorf("%w: expected a JSON object", ErrAsyncAPI)
+	}
+
+	// Copy each field that is not omitted
+	res := []byte{'{'}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := t.(string)
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if omitted[key] {
+			continue
+		}
+
+		if len(res) > 1 {
+			res = append(res, ',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		res = append(append(append(res, k...), ':'), value...)
+	}
+
+	return append(res, '}'), nil
+}
@@ -0,0 +1,72 @@
+package extensions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestNullableSuite(t *testing.T) {
+	suite.Run(t, new(NullableSuite))
+}
+
+type NullableSuite struct {
+	suite.Suite
+}
+
+func (suite *NullableSuite) TestStates() {
+	var absent Nullable[int]
+	suite.Require().False(absent.IsPresent())
+	suite.Require().False(absent.IsNull())
+	suite.Require().False(absent.IsSet())
+	suite.Require().Equal(3, absent.ValueOr(3))
+
+	null := Null[int]()
+	suite.Require().True(null.IsPresent())
+	suite.Require().True(null.IsNull())
+	suite.Require().False(null.IsSet())
+
+	set := NewNullable(42)
+	suite.Require().True(set.IsPresent())
+	suite.Require().False(set.IsNull())
+	v, ok := set.Get()
+	suite.Require().True(ok)
+	suite.Require().Equal(42, v)
+
+	set.Unset()
+	suite.Require().Equal(absent, set)
+}
+
+func (suite *NullableSuite) TestJSON() {
+	var v struct {
+		A Nullable[string] `json:"a"`
+		B Nullable[string] `json:"b"`
+		C Nullable[string] `json:"c"`
+	}
+	suite.Require().NoError(json.Unmarshal([]byte(`{"a":"hello","b":null}`), &v))
+	suite.Require().Equal(NewNullable("hello"), v.A)
+	suite.Require().True(v.B.IsNull())
+	suite.Require().False(v.C.IsPresent())
+
+	b, err := json.Marshal(v)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(`{"a":"hello","b":null,"c":null}`, string(b))
+
+	b, err = OmitJSONFields(b, "c")
+	suite.Require().NoError(err)
+	suite.Require().Equal(`{"a":"hello","b":null}`, string(b))
+}
+
+func (suite *NullableSuite) TestOmitJSONFields() {
+	b, err := OmitJSONFields([]byte(`{"a":{"c":1},"b":[1,2],"c":"x"}`), "a", "c")
+	suite.Require().NoError(err)
+	suite.Require().Equal(`{"b":[1,2]}`, string(b))
+
+	b, err = OmitJSONFields([]byte(`{"a":1}`), "a")
+	suite.Require().NoError(err)
+	suite.Require().Equal(`{}`, string(b))
+
+	_, err = OmitJSONFields([]byte(`[1]`), "a")
+	suite.Require().Error(err)
+}
@@ -0,0 +1,110 @@
+package extensions
+
+import (
+	"regexp"
+	"strings"
+)
+
+const hexDigits = "0123456789ABCDEF"
+
+// channelParameterSpecialBytes are the bytes escaped in the channel parameters:
+// the separators and wildcards of the brokers (e.g. '.', '*' and '>' for NATS,
+// '/', '+' and '#' for MQTT) and the whitespaces.
+const channelParameterSpecialBytes = ".*>/+# \t\r\n"
+
+// EscapeChannelParameter escapes a channel parameter value in order to
+// substitute it in a channel address.
+//
+// Only the separators and wildcards of the brokers and the whitespaces are
+// replaced by '_' followed by their hexadecimal value (e.g. 'eu.west' becomes
+// 'eu_2Ewest'), as well as the '_' that are followed by such an escape
+// sequence, so the value can be unescaped without ambiguity. The other values
+// are kept as is (e.g. 'tenant_01').
+func EscapeChannelParameter(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if strings.IndexByte(channelParameterSpecialBytes, c) < 0 && (c != '_' || !isEscapeSequence(value[i+1:])) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('_')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&0x0f])
+	}
+	return b.String()
+}
+
+// UnescapeChannelParameter unescapes a channel parameter value that has been
+// escaped with EscapeChannelParameter, once extracted from a channel address.
+// The values that are not escaped are kept as is, so the addresses built
+// without escaping the parameters can also be used.
+func UnescapeChannelParameter(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c != '_' || !isEscapeSequence(value[i+1:]) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte(unhex(value[i+1])<<4 | unhex(value[i+2]))
+		i += 2
+	}
+	return b.String()
+}
+
+// isEscapeSequence checks if the string starts with the hexadecimal value of
+// an escaped byte, i.e. a special byte or '_'.
+func isEscapeSequence(s string) bool {
+	if len(s) < 2 || unhex(s[0]) == 0xff || unhex(s[1]) == 0xff {
+		return false
+	}
+	c := unhex(s[0])<<4 | unhex(s[1])
+	return c == '_' || strings.IndexByte(channelParameterSpecialBytes, c) >= 0
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10
+	default:
+		return 0xff
+	}
+}
+
+var channelParameterRegexp = regexp.MustCompile("{[^{}]*}")
+
+// ChannelAddressWithWildcards replaces the parameters of a channel address by
+// a wildcard, for the brokers whose wildcards match a whole segment of the
+// address (e.g. '*' with '.' separator for NATS, '+' with '/' separator for
+// MQTT). A segment that contains a parameter is entirely replaced, so the
+// received addresses should still be checked against the channel address.
+func ChannelAddressWithWildcards(channel, separator, wildcard string) string {
+	segments := strings.Split(channel, separator)
+	for i, s := range segments {
+		if channelParameterRegexp.MatchString(s) {
+			segments[i] = wildcard
+		}
+	}
+	return strings.Join(segments, separator)
+}
+
+// ChannelAddressRegexp returns a regular expression matching all the addresses
+// of a channel, where each parameter matches any value, escaped or not (see
+// EscapeChannelParameter).
+func ChannelAddressRegexp(channel string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, i := range channelParameterRegexp.FindAllStringIndex(channel, -1) {
+		b.WriteString(regexp.QuoteMeta(channel[last:i[0]]))
+		b.WriteString(".*?")
+		last = i[1]
+	}
+	b.WriteString(regexp.QuoteMeta(channel[last:]))
+	b.WriteString("$")
+	return b.String()
+}
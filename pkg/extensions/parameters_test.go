@@ -0,0 +1,65 @@
+package extensions
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestParametersSuite(t *testing.T) {
+	suite.Run(t, new(ParametersSuite))
+}
+
+type ParametersSuite struct {
+	suite.Suite
+}
+
+func (suite *ParametersSuite) TestEscapeChannelParameter() {
+	cases := []struct {
+		Value   string
+		Escaped string
+	}{
+		{Value: "user-1234", Escaped: "user-1234"},
+		{Value: "user_id", Escaped: "user_id"},
+		{Value: "tenant_01", Escaped: "tenant_01"},
+		{Value: "eu.west", Escaped: "eu_2Ewest"},
+		{Value: "a/b+c#d", Escaped: "a_2Fb_2Bc_23d"},
+		{Value: "* >", Escaped: "_2A_20_3E"},
+		{Value: "_2E", Escaped: "_5F2E"},
+		{Value: "_5F", Escaped: "_5F5F"},
+		{Value: "_.", Escaped: "__2E"},
+		{Value: "_2.", Escaped: "_2_2E"},
+		{Value: "é:@", Escaped: "é:@"},
+		{Value: "", Escaped: ""},
+	}
+
+	for _, c := range cases {
+		suite.Require().Equal(c.Escaped, EscapeChannelParameter(c.Value), c.Value)
+		suite.Require().Equal(c.Value, UnescapeChannelParameter(c.Escaped), c.Escaped)
+	}
+}
+
+func (suite *ParametersSuite) TestUnescapeUnescapedChannelParameter() {
+	// The values that are not escaped are kept as is
+	for _, value := range []string{"eu.west", "tenant_01", "a_zz", "end_", "end_2"} {
+		suite.Require().Equal(value, UnescapeChannelParameter(value), value)
+	}
+}
+
+func (suite *ParametersSuite) TestChannelAddressWithWildcards() {
+	suite.Require().Equal("users.*.signup.*",
+		ChannelAddressWithWildcards("users.{userId}.signup.{region}", ".", "*"))
+	suite.Require().Equal("users/+/signup",
+		ChannelAddressWithWildcards("users/user-{userId}/signup", "/", "+"))
+	suite.Require().Equal("users.signup", ChannelAddressWithWildcards("users.signup", ".", "*"))
+}
+
+func (suite *ParametersSuite) TestChannelAddressRegexp() {
+	r := regexp.MustCompile(ChannelAddressRegexp("users.{userId}.signup"))
+	suite.Require().True(r.MatchString("users.bob_2Esmith.signup"))
+	suite.Require().True(r.MatchString("users.bob.smith.signup"))
+	suite.Require().True(r.MatchString("users..signup"))
+	suite.Require().False(r.MatchString("users.bob.signup.eu"))
+	suite.Require().False(r.MatchString("other.users.bob.signup"))
+}
@@ -0,0 +1,534 @@
+package extensions
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	// ProtobufContentType is the content type of the messages whose payload
+	// is encoded with the protobuf binary encoding.
+	ProtobufContentType = "application/x-protobuf"
+
+	// ProtobufTimestampType is the name of the protobuf well-known type for
+	// timestamps, that is (un)marshaled from and into time.Time.
+	ProtobufTimestampType = "google.protobuf.Timestamp"
+)
+
+// ProtobufMessage is the descriptor of a protobuf message, used by the
+// generated code to marshal and unmarshal payloads with the protobuf binary
+// encoding.
+type ProtobufMessage struct {
+	desc protoreflect.MessageDescriptor
+}
+
+// ParseProtobufSchema returns the message with the given full name from the
+// serialized FileDescriptorSet of a protobuf file and its imports.
+func ParseProtobufSchema(descriptors, message string) (*ProtobufMessage, error) {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal([]byte(descriptors), &set); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProtobuf, err)
+	}
+
+	// Use a separate registry, as the files can also be registered by the
+	// code generated by protoc-gen-go
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProtobuf, err)
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(message))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProtobuf, err)
+	}
+	m, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not a message", ErrInvalidProtobuf, message)
+	}
+
+	return &ProtobufMessage{desc: m}, nil
+}
+
+// MustParseProtobufSchema returns the message with the given full name from
+// the serialized FileDescriptorSet of a protobuf file and its imports, and
+// panics if it is invalid. It is used by the generated code, where the
+// descriptors come from the specification.
+func MustParseProtobufSchema(descriptors, message string) *ProtobufMessage {
+	m, err := ParseProtobufSchema(descriptors, message)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Descriptor returns the descriptor of the protobuf message.
+func (m *ProtobufMessage) Descriptor() protoreflect.MessageDescriptor {
+	return m.desc
+}
+
+// MarshalProtobuf marshals a value with the protobuf binary encoding of the
+// given message. The types generated by protoc-gen-go are marshaled directly.
+// Other messages are structs whose fields are matched with their JSON names,
+// and enums are either strings set to the value names or integers.
+func MarshalProtobuf(message *ProtobufMessage, v any) ([]byte, error) {
+	var m proto.Message
+	if pm, ok := v.(proto.Message); ok {
+		if err := message.check(pm); err != nil {
+			return nil, err
+		}
+		m = pm
+	} else {
+		rv, ok := indirect(reflect.ValueOf(v))
+		if !ok {
+			return nil, fmt.Errorf("%w: missing value for message %q", ErrInvalidProtobuf, message.desc.FullName())
+		}
+
+		dm := dynamicpb.NewMessage(message.desc)
+		if err := toProtobufMessage(dm, rv); err != nil {
+			return nil, err
+		}
+		m = dm
+	}
+
+	// Use a deterministic encoding so that the payloads can be compared
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProtobuf, err)
+	}
+	return b, nil
+}
+
+// UnmarshalProtobuf unmarshals data with the protobuf binary encoding of the
+// given message into the value pointed by v. If it points to a nil pointer of
+// a type generated by protoc-gen-go, a new message is allocated.
+func UnmarshalProtobuf(message *ProtobufMessage, data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("%w: expected a non-nil pointer", ErrInvalidProtobuf)
+	}
+
+	// Unmarshal directly into the types generated by protoc-gen-go
+	if e := rv.Elem(); e.Kind() == reflect.Pointer && e.Type().Implements(protoMessageType) {
+		if e.IsNil() {
+			e.Set(reflect.New(e.Type().Elem()))
+		}
+		rv = e
+	}
+	if pm, ok := rv.Interface().(proto.Message); ok {
+		if err := message.check(pm); err != nil {
+			return err
+		}
+		if err := proto.Unmarshal(data, pm); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidProtobuf, err)
+		}
+		return nil
+	}
+
+	dm := dynamicpb.NewMessage(message.desc)
+	if err := proto.Unmarshal(data, dm); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProtobuf, err)
+	}
+	return fromProtobufMessage(dm, allocate(rv.Elem()))
+}
+
+var protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// check checks that a message generated by protoc-gen-go is the expected one.
+func (m *ProtobufMessage) check(pm proto.Message) error {
+	if name := pm.ProtoReflect().Descriptor().FullName(); name != m.desc.FullName() {
+		return fmt.Errorf("%w: expected message %q, got %q", ErrInvalidProtobuf, m.desc.FullName(), name)
+	}
+	return nil
+}
+
+// toProtobufMessage sets the fields of a protobuf message from a struct, or
+// from a time.Time for timestamps.
+func toProtobufMessage(m protoreflect.Message, v reflect.Value) error {
+	desc := m.Descriptor()
+	if desc.FullName() == ProtobufTimestampType && v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		m.Set(desc.Fields().ByName("seconds"), protoreflect.ValueOfInt64(t.Unix()))
+		m.Set(desc.Fields().ByName("nanos"), protoreflect.ValueOfInt32(int32(t.Nanosecond())))
+		return nil
+	}
+
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: cannot encode %s as message %q", ErrInvalidProtobuf, v.Type(), desc.FullName())
+	}
+	indexes := jsonFieldIndexes(v.Type())
+
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		index, ok := indexes[string(fd.Name())]
+		if !ok {
+			return fmt.Errorf("%w: no field %q in %s", ErrInvalidProtobuf, fd.Name(), v.Type())
+		}
+
+		// Skip the fields that are not set
+		fv, ok := indirect(v.Field(index))
+		if !ok {
+			continue
+		}
+
+		var err error
+		switch {
+		case fd.IsMap():
+			err = toProtobufMap(m.Mutable(fd).Map(), fd, fv)
+		case fd.IsList():
+			err = toProtobufList(m.Mutable(fd).List(), fd, fv)
+		case fd.Message() != nil:
+			err = toProtobufMessage(m.Mutable(fd).Message(), fv)
+		default:
+			var value protoreflect.Value
+			if value, err = toProtobufScalar(fd, fv); err == nil {
+				m.Set(fd, value)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toProtobufList(l protoreflect.List, fd protoreflect.FieldDescriptor, v reflect.Value) error {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("%w: cannot encode %s as repeated field %q", ErrInvalidProtobuf, v.Type(), fd.FullName())
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		e, ok := indirect(v.Index(i))
+		if !ok {
+			return fmt.Errorf("%w: missing value in repeated field %q", ErrInvalidProtobuf, fd.FullName())
+		}
+
+		if fd.Message() != nil {
+			value := l.NewElement()
+			if err := toProtobufMessage(value.Message(), e); err != nil {
+				return err
+			}
+			l.Append(value)
+			continue
+		}
+
+		value, err := toProtobufScalar(fd, e)
+		if err != nil {
+			return err
+		}
+		l.Append(value)
+	}
+
+	return nil
+}
+
+func toProtobufMap(m protoreflect.Map, fd protoreflect.FieldDescriptor, v reflect.Value) error {
+	if v.Kind() == reflect.Struct {
+		v = v.FieldByName("AdditionalProperties")
+	}
+	if v.Kind() != reflect.Map {
+		return fmt.Errorf("%w: cannot encode %s as map field %q", ErrInvalidProtobuf, v.Type(), fd.FullName())
+	}
+
+	for it := v.MapRange(); it.Next(); {
+		key, err := toProtobufMapKey(fd.MapKey(), it.Key())
+		if err != nil {
+			return err
+		}
+		e, ok := indirect(it.Value())
+		if !ok {
+			return fmt.Errorf("%w: missing value in map field %q", ErrInvalidProtobuf, fd.FullName())
+		}
+
+		if fd.MapValue().Message() != nil {
+			if err := toProtobufMessage(m.Mutable(key).Message(), e); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err := toProtobufScalar(fd.MapValue(), e)
+		if err != nil {
+			return err
+		}
+		m.Set(key, value)
+	}
+
+	return nil
+}
+
+// toProtobufMapKey converts a map key, that is a string in the generated maps.
+func toProtobufMapKey(fd protoreflect.FieldDescriptor, v reflect.Value) (protoreflect.MapKey, error) {
+	if v.Kind() == reflect.String {
+		switch fd.Kind() {
+		case protoreflect.StringKind:
+			return protoreflect.ValueOfString(v.String()).MapKey(), nil
+		case protoreflect.BoolKind:
+			b, err := strconv.ParseBool(v.String())
+			if err != nil {
+				return protoreflect.MapKey{}, fmt.Errorf("%w: invalid key %q in map field %q",
+					ErrInvalidProtobuf, v.String(), fd.FullName())
+			}
+			v = reflect.ValueOf(b)
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			n, err := strconv.ParseUint(v.String(), 10, 64)
+			if err != nil {
+				return protoreflect.MapKey{}, fmt.Errorf("%w: invalid key %q in map field %q",
+					ErrInvalidProtobuf, v.String(), fd.FullName())
+			}
+			v = reflect.ValueOf(n)
+		default:
+			n, err := strconv.ParseInt(v.String(), 10, 64)
+			if err != nil {
+				return protoreflect.MapKey{}, fmt.Errorf("%w: invalid key %q in map field %q",
+					ErrInvalidProtobuf, v.String(), fd.FullName())
+			}
+			v = reflect.ValueOf(n)
+		}
+	}
+
+	value, err := toProtobufScalar(fd, v)
+	if err != nil {
+		return protoreflect.MapKey{}, err
+	}
+	return value.MapKey(), nil
+}
+
+//nolint:cyclop,funlen // Not necessary to split the protobuf types
+func toProtobufScalar(fd protoreflect.FieldDescriptor, v reflect.Value) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if v.Kind() == reflect.Bool {
+			return protoreflect.ValueOfBool(v.Bool()), nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		switch {
+		case v.CanInt():
+			return protoreflect.ValueOfInt32(int32(v.Int())), nil
+		case v.CanUint():
+			return protoreflect.ValueOfInt32(int32(v.Uint())), nil
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		switch {
+		case v.CanInt():
+			return protoreflect.ValueOfInt64(v.Int()), nil
+		case v.CanUint():
+			return protoreflect.ValueOfInt64(int64(v.Uint())), nil
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		switch {
+		case v.CanUint():
+			return protoreflect.ValueOfUint32(uint32(v.Uint())), nil
+		case v.CanInt():
+			return protoreflect.ValueOfUint32(uint32(v.Int())), nil
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		switch {
+		case v.CanUint():
+			return protoreflect.ValueOfUint64(v.Uint()), nil
+		case v.CanInt():
+			return protoreflect.ValueOfUint64(uint64(v.Int())), nil
+		}
+	case protoreflect.FloatKind:
+		if v.CanFloat() {
+			return protoreflect.ValueOfFloat32(float32(v.Float())), nil
+		}
+	case protoreflect.DoubleKind:
+		if v.CanFloat() {
+			return protoreflect.ValueOfFloat64(v.Float()), nil
+		}
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		if text, ok, err := textOf(v); ok || err != nil {
+			if fd.Kind() == protoreflect.StringKind {
+				return protoreflect.ValueOfString(string(text)), err
+			}
+			return protoreflect.ValueOfBytes(text), err
+		}
+	case protoreflect.EnumKind:
+		switch {
+		case v.Kind() == reflect.String && v.String() == "":
+			// Unset enums are set to their default value
+			return protoreflect.ValueOfEnum(fd.Default().Enum()), nil
+		case v.Kind() == reflect.String:
+			value := fd.Enum().Values().ByName(protoreflect.Name(v.String()))
+			if value == nil {
+				return protoreflect.Value{}, fmt.Errorf("%w: %q is not a value of enum %q",
+					ErrInvalidEnumValue, v.String(), fd.Enum().FullName())
+			}
+			return protoreflect.ValueOfEnum(value.Number()), nil
+		case v.CanInt():
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v.Int())), nil
+		}
+	}
+
+	return protoreflect.Value{}, fmt.Errorf("%w: cannot encode %s as field %q", ErrInvalidProtobuf, v.Type(), fd.FullName())
+}
+
+// fromProtobufMessage sets a struct from the fields of a protobuf message, or
+// a time.Time from a timestamp.
+func fromProtobufMessage(m protoreflect.Message, v reflect.Value) error {
+	desc := m.Descriptor()
+	if desc.FullName() == ProtobufTimestampType && v.Type() == timeType {
+		seconds := m.Get(desc.Fields().ByName("seconds")).Int()
+		nanos := m.Get(desc.Fields().ByName("nanos")).Int()
+		v.Set(reflect.ValueOf(time.Unix(seconds, nanos).UTC()))
+		return nil
+	}
+
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: cannot decode message %q into %s", ErrInvalidProtobuf, desc.FullName(), v.Type())
+	}
+	indexes := jsonFieldIndexes(v.Type())
+
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		index, ok := indexes[string(fd.Name())]
+		if !ok {
+			return fmt.Errorf("%w: no field %q in %s", ErrInvalidProtobuf, fd.Name(), v.Type())
+		}
+		fv := v.Field(index)
+
+		// Reset the fields that are not present, except the scalar ones without
+		// presence that are set to their default value
+		if !m.Has(fd) && (fd.HasPresence() || fd.IsList() || fd.IsMap()) {
+			setNull(fv)
+			continue
+		}
+
+		var err error
+		switch {
+		case fd.IsMap():
+			err = fromProtobufMap(m.Get(fd).Map(), fd, allocate(fv))
+		case fd.IsList():
+			err = fromProtobufList(m.Get(fd).List(), fd, allocate(fv))
+		default:
+			err = fromProtobufValue(fd, m.Get(fd), fv)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func fromProtobufList(l protoreflect.List, fd protoreflect.FieldDescriptor, v reflect.Value) error {
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("%w: cannot decode repeated field %q into %s", ErrInvalidProtobuf, fd.FullName(), v.Type())
+	}
+
+	v.Set(reflect.MakeSlice(v.Type(), l.Len(), l.Len()))
+	for i := 0; i < l.Len(); i++ {
+		if err := fromProtobufValue(fd, l.Get(i), v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func fromProtobufMap(m protoreflect.Map, fd protoreflect.FieldDescriptor, v reflect.Value) error {
+	if v.Kind() == reflect.Struct {
+		v = v.FieldByName("AdditionalProperties")
+	}
+	if v.Kind() != reflect.Map {
+		return fmt.Errorf("%w: cannot decode map field %q into %s", ErrInvalidProtobuf, fd.FullName(), v.Type())
+	}
+
+	v.Set(reflect.MakeMapWithSize(v.Type(), m.Len()))
+	var err error
+	m.Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+		k := reflect.New(v.Type().Key()).Elem()
+		if k.Kind() == reflect.String {
+			k.SetString(key.String())
+		} else if err = fromProtobufValue(fd.MapKey(), key.Value(), k); err != nil {
+			return false
+		}
+
+		e := reflect.New(v.Type().Elem()).Elem()
+		if err = fromProtobufValue(fd.MapValue(), value, e); err != nil {
+			return false
+		}
+
+		v.SetMapIndex(k, e)
+		return true
+	})
+
+	return err
+}
+
+//nolint:cyclop // Not necessary to split the protobuf types
+func fromProtobufValue(fd protoreflect.FieldDescriptor, value protoreflect.Value, v reflect.Value) error {
+	v = allocate(v)
+
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return fromProtobufMessage(value.Message(), v)
+	case protoreflect.EnumKind:
+		switch {
+		case v.Kind() == reflect.String:
+			ev := fd.Enum().Values().ByNumber(value.Enum())
+			if ev == nil {
+				return fmt.Errorf("%w: %d is not a value of enum %q", ErrInvalidEnumValue, value.Enum(), fd.Enum().FullName())
+			}
+			v.SetString(string(ev.Name()))
+			return nil
+		case v.CanInt():
+			v.SetInt(int64(value.Enum()))
+			return nil
+		}
+	case protoreflect.BoolKind:
+		if v.Kind() == reflect.Bool {
+			v.SetBool(value.Bool())
+			return nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if ok, err := fromProtobufNumber(value.Int(), v); ok {
+			return err
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if ok, err := fromProtobufNumber(value.Uint(), v); ok {
+			return err
+		}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		if v.CanFloat() {
+			v.SetFloat(value.Float())
+			return nil
+		}
+	case protoreflect.StringKind:
+		if ok, err := setText([]byte(value.String()), v); ok {
+			return err
+		}
+	case protoreflect.BytesKind:
+		if ok, err := setText(value.Bytes(), v); ok {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: cannot decode field %q into %s", ErrInvalidProtobuf, fd.FullName(), v.Type())
+}
+
+func fromProtobufNumber[T int64 | uint64](n T, v reflect.Value) (bool, error) {
+	switch {
+	case v.CanInt():
+		v.SetInt(int64(n))
+	case v.CanUint():
+		v.SetUint(uint64(n))
+	case v.CanFloat():
+		v.SetFloat(float64(n))
+	default:
+		return false, nil
+	}
+	return true, nil
+}
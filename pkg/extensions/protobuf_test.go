@@ -0,0 +1,263 @@
+package extensions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestProtobufSuite(t *testing.T) {
+	suite.Run(t, new(ProtobufSuite))
+}
+
+type ProtobufSuite struct {
+	suite.Suite
+}
+
+const protobufTestFile = `
+syntax = "proto3";
+
+package example.v1;
+
+import "google/protobuf/timestamp.proto";
+
+option go_package = "github.com/example/userpb;userpb";
+
+// User is a user of the application
+message User {
+	string id = 1;
+	int32 age = 2 [deprecated = true];
+	optional string email = 3;
+	Status status = 4;
+	repeated string tags = 5;
+	repeated sint64 scores = 6;
+	map<string, int64> counters = 7;
+	google.protobuf.Timestamp created_at = 8;
+	Address address = 9;
+	oneof contact {
+		string phone = 10;
+		Address postal = 11;
+	}
+	bytes hash = 12;
+	double ratio = 13;
+	fixed32 checksum = 14;
+
+	enum Status {
+		STATUS_UNSPECIFIED = 0;
+		STATUS_ACTIVE = 1;
+		STATUS_BANNED = 2;
+	}
+
+	/* Address of the user */
+	message Address {
+		string city = 1;
+	}
+}
+`
+
+type protobufTestAddress struct {
+	City string `json:"city"`
+}
+
+type protobufTestUser struct {
+	Id        string               `json:"id"`
+	Age       int32                `json:"age"`
+	Email     *string              `json:"email,omitempty"`
+	Status    string               `json:"status"`
+	Tags      []string             `json:"tags,omitempty"`
+	Scores    []int64              `json:"scores,omitempty"`
+	Counters  map[string]int64     `json:"counters,omitempty"`
+	CreatedAt *time.Time           `json:"created_at,omitempty"`
+	Address   *protobufTestAddress `json:"address,omitempty"`
+	Phone     *string              `json:"phone,omitempty"`
+	Postal    *protobufTestAddress `json:"postal,omitempty"`
+	Hash      []byte               `json:"hash,omitempty"`
+	Ratio     float64              `json:"ratio"`
+	Checksum  uint32               `json:"checksum"`
+}
+
+// compileProtobufTest compiles the protobuf files and returns the message with
+// the given name, as the generated code would do from the specification.
+func compileProtobufTest(message string, files map[string]string) (*ProtobufMessage, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(files),
+		}),
+	}
+	compiled, err := compiler.Compile(context.Background(), "test.proto")
+	if err != nil {
+		return nil, err
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+	add(compiled[0])
+
+	descriptors, err := proto.Marshal(&set)
+	if err != nil {
+		return nil, err
+	}
+	return ParseProtobufSchema(string(descriptors), message)
+}
+
+func (suite *ProtobufSuite) mustCompile(message, source string) *ProtobufMessage {
+	m, err := compileProtobufTest(message, map[string]string{"test.proto": source})
+	suite.Require().NoError(err)
+	return m
+}
+
+func (suite *ProtobufSuite) TestParse() {
+	m := suite.mustCompile("example.v1.User", protobufTestFile)
+	suite.Require().Equal(protoreflect.FullName("example.v1.User"), m.Descriptor().FullName())
+
+	// Imports are resolved
+	m, err := compileProtobufTest("example.v1.Order", map[string]string{
+		"test.proto": `syntax = "proto3"; package example.v1; import "item.proto";
+			message Order { repeated Item items = 1; }`,
+		"item.proto": `syntax = "proto3"; package example.v1; message Item { string name = 1; }`,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(protoreflect.FullName("example.v1.Item"),
+		m.Descriptor().Fields().ByName("items").Message().FullName())
+
+	_, err = compileProtobufTest("example.v1.Unknown", map[string]string{"test.proto": protobufTestFile})
+	suite.Require().ErrorIs(err, ErrInvalidProtobuf)
+	_, err = compileProtobufTest("example.v1.User.Status", map[string]string{"test.proto": protobufTestFile})
+	suite.Require().ErrorIs(err, ErrInvalidProtobuf)
+	_, err = ParseProtobufSchema("invalid", "example.v1.User")
+	suite.Require().ErrorIs(err, ErrInvalidProtobuf)
+}
+
+func (suite *ProtobufSuite) TestRoundTrip() {
+	schema := suite.mustCompile("example.v1.User", protobufTestFile)
+
+	email, phone := "bob@example.com", "+33123456789"
+	createdAt := time.UnixMilli(1700000000123).UTC()
+	user := protobufTestUser{
+		Id:        "1234",
+		Age:       -42,
+		Email:     &email,
+		Status:    "STATUS_BANNED",
+		Tags:      []string{"a", "b"},
+		Scores:    []int64{-1, 0, 300},
+		Counters:  map[string]int64{"x": 1, "y": -300},
+		CreatedAt: &createdAt,
+		Address:   &protobufTestAddress{City: "Paris"},
+		Phone:     &phone,
+		Hash:      []byte{0xca, 0xfe},
+		Ratio:     0.5,
+		Checksum:  0xdeadbeef,
+	}
+
+	b, err := MarshalProtobuf(schema, user)
+	suite.Require().NoError(err)
+
+	var decoded protobufTestUser
+	suite.Require().NoError(UnmarshalProtobuf(schema, b, &decoded))
+	suite.Require().Equal(user, decoded)
+
+	// Default values are not encoded
+	b, err = MarshalProtobuf(schema, protobufTestUser{})
+	suite.Require().NoError(err)
+	suite.Require().Empty(b)
+	suite.Require().NoError(UnmarshalProtobuf(schema, b, &decoded))
+	suite.Require().Equal(protobufTestUser{Status: "STATUS_UNSPECIFIED"}, decoded)
+}
+
+func (suite *ProtobufSuite) TestEncoding() {
+	// Values from the protobuf encoding guide
+	schema := suite.mustCompile("Test", `syntax = "proto3"; message Test {
+		int32 a = 1;
+		string b = 2;
+		repeated int32 d = 4;
+	}`)
+	v := struct {
+		A int32   `json:"a"`
+		B string  `json:"b"`
+		D []int32 `json:"d"`
+	}{A: 150, B: "testing", D: []int32{3, 270, 86942}}
+
+	b, err := MarshalProtobuf(schema, v)
+	suite.Require().NoError(err)
+	suite.Require().Equal([]byte{
+		0x08, 0x96, 0x01,
+		0x12, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		0x22, 0x06, 0x03, 0x8e, 0x02, 0x9e, 0xa7, 0x05,
+	}, b)
+
+	// Unpacked repeated values and unknown fields are accepted
+	v.D = nil
+	suite.Require().NoError(UnmarshalProtobuf(schema, []byte{0x20, 0x03, 0x28, 0x01, 0x20, 0x04}, &v))
+	suite.Require().Equal([]int32{3, 4}, v.D)
+}
+
+func (suite *ProtobufSuite) TestOneof() {
+	schema := suite.mustCompile("example.v1.User", protobufTestFile)
+	phone := "+33123456789"
+
+	b, err := MarshalProtobuf(schema, protobufTestUser{Postal: &protobufTestAddress{City: "Paris"}})
+	suite.Require().NoError(err)
+
+	// The last field of the oneof wins
+	decoded := protobufTestUser{Phone: &phone}
+	suite.Require().NoError(UnmarshalProtobuf(schema, b, &decoded))
+	suite.Require().Nil(decoded.Phone)
+	suite.Require().Equal("Paris", decoded.Postal.City)
+}
+
+func (suite *ProtobufSuite) TestErrors() {
+	schema := suite.mustCompile("example.v1.User", protobufTestFile)
+
+	_, err := MarshalProtobuf(schema, protobufTestUser{Status: "UNKNOWN"})
+	suite.Require().ErrorIs(err, ErrInvalidEnumValue)
+
+	_, err = MarshalProtobuf(schema, struct{}{})
+	suite.Require().ErrorIs(err, ErrInvalidProtobuf)
+
+	var decoded protobufTestUser
+	suite.Require().ErrorIs(UnmarshalProtobuf(schema, []byte{0x0a, 0x05, 'a'}, &decoded), ErrInvalidProtobuf)
+	suite.Require().ErrorIs(UnmarshalProtobuf(schema, []byte{0x20, 0x07}, &decoded), ErrInvalidEnumValue)
+	suite.Require().ErrorIs(UnmarshalProtobuf(schema, nil, decoded), ErrInvalidProtobuf)
+}
+
+func (suite *ProtobufSuite) TestGeneratedTypes() {
+	// Timestamp is generated by protoc-gen-go
+	schema := suite.mustCompile(ProtobufTimestampType, `syntax = "proto3"; import "google/protobuf/timestamp.proto";`)
+	createdAt := time.UnixMilli(1700000000123).UTC()
+
+	b, err := MarshalProtobuf(schema, timestamppb.New(createdAt))
+	suite.Require().NoError(err)
+	expected, err := proto.Marshal(timestamppb.New(createdAt))
+	suite.Require().NoError(err)
+	suite.Require().Equal(expected, b)
+
+	var decoded *timestamppb.Timestamp
+	suite.Require().NoError(UnmarshalProtobuf(schema, b, &decoded))
+	suite.Require().Equal(createdAt, decoded.AsTime())
+
+	// The generated types are interoperable with the structs
+	var t time.Time
+	suite.Require().NoError(UnmarshalProtobuf(schema, b, &t))
+	suite.Require().Equal(createdAt, t)
+
+	// The message must match
+	other := suite.mustCompile("Test", `syntax = "proto3"; message Test {}`)
+	_, err = MarshalProtobuf(other, timestamppb.New(createdAt))
+	suite.Require().ErrorIs(err, ErrInvalidProtobuf)
+	suite.Require().ErrorIs(UnmarshalProtobuf(other, b, &decoded), ErrInvalidProtobuf)
+}
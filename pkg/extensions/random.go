@@ -0,0 +1,431 @@
+package extensions
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/netip"
+	"regexp/syntax"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// RandomMaxDepth is the depth of nested objects from which the random
+	// values only get their required fields and the minimum number of items,
+	// in order to end on recursive schemas.
+	RandomMaxDepth = 3
+
+	// randomAttempts is the number of random values generated before giving up
+	// on a constraint that is hard to satisfy (e.g. a pattern with lengths).
+	randomAttempts = 100
+	// randomRange is the size of the range of the random numbers, when there
+	// is no minimum or maximum.
+	randomRange = 1000
+	// randomMaxLength is the number of characters or items added to the
+	// minimum length of the random strings and arrays, when there is no maximum.
+	randomMaxLength = 16
+	// randomMaxRepeat is the maximum number of repetitions of the unbounded
+	// repetitions in patterns (e.g. '*' or '+').
+	randomMaxRepeat = 8
+)
+
+const randomCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// RandomConstraints are the validations from the specification that the
+// random values should satisfy. As zero is a valid bound, the maximums and the
+// numbers bounds are only used when their 'Has' field is set.
+type RandomConstraints struct {
+	MinLength           uint
+	MaxLength           uint
+	HasMaxLength        bool
+	Pattern             string
+	Format              string
+	Minimum             float64
+	HasMinimum          bool
+	Maximum             float64
+	HasMaximum          bool
+	ExclusiveMinimum    float64
+	HasExclusiveMinimum bool
+	ExclusiveMaximum    float64
+	HasExclusiveMaximum bool
+	MinItems            uint
+	MaxItems            uint
+	HasMaxItems         bool
+	UniqueItems         bool
+}
+
+// RandomOptional returns randomly if an optional field should be set, knowing
+// that it is never set from the maximum depth.
+func RandomOptional(r *rand.Rand, depth int) bool {
+	return depth < RandomMaxDepth && r.Intn(2) == 0
+}
+
+// RandomChoice returns one of the values randomly.
+func RandomChoice[T any](r *rand.Rand, values []T) T {
+	return values[r.Intn(len(values))]
+}
+
+// RandomString returns a random string matching the pattern, or having the
+// format if there is no pattern, with a length between the minimum and
+// maximum lengths. If the pattern (or format) can't be satisfied with these
+// lengths, the string has only the right length.
+func RandomString(r *rand.Rand, c RandomConstraints) string {
+	if c.Pattern != "" {
+		if re, err := syntax.Parse(c.Pattern, syntax.Perl); err == nil {
+			re = re.Simplify()
+			for i := 0; i < randomAttempts; i++ {
+				var b strings.Builder
+				writeRandomPattern(r, &b, re)
+				if s := b.String(); c.hasLength(len([]rune(s))) {
+					return s
+				}
+			}
+		}
+	} else if format, ok := randomFormats[c.Format]; ok {
+		for i := 0; i < randomAttempts; i++ {
+			if s := format(r); c.hasLength(len([]rune(s))) {
+				return s
+			}
+		}
+	}
+
+	b := make([]byte, c.length(r))
+	for i := range b {
+		b[i] = randomCharacters[r.Intn(len(randomCharacters))]
+	}
+	return string(b)
+}
+
+// RandomBytes returns random bytes, with a length between the minimum and
+// maximum lengths.
+func RandomBytes(r *rand.Rand, c RandomConstraints) []byte {
+	b := make([]byte, c.length(r))
+	_, _ = r.Read(b)
+	return b
+}
+
+// RandomText returns a random value of a type that is unmarshaled from a
+// string with the given format (e.g. 'uuid' or 'date-time').
+func RandomText[T any, PT interface {
+	*T
+	encoding.TextUnmarshaler
+}](r *rand.Rand, format string) T {
+	var v T
+
+	text, ok := randomFormats[format]
+	if !ok {
+		panic(fmt.Sprintf("no random value for format %q", format))
+	}
+	if err := PT(&v).UnmarshalText([]byte(text(r))); err != nil {
+		panic(fmt.Sprintf("invalid random value for format %q: %s", format, err))
+	}
+
+	return v
+}
+
+// RandomInteger returns a random integer between the minimum and maximum (or
+// exclusive minimum and maximum), that fits in the given number of bits.
+func RandomInteger(r *rand.Rand, c RandomConstraints, bits int) int64 {
+	lo, hi, exclusiveLo, exclusiveHi := c.bounds()
+
+	// Get the bounds as integers, in the range of the type
+	low, high := math.Ceil(lo), math.Floor(hi)
+	if exclusiveLo && low == lo {
+		low++
+	}
+	if exclusiveHi && high == hi {
+		high--
+	}
+	minInt, maxInt := -math.Exp2(float64(bits-1)), math.Exp2(float64(bits-1))-1
+	lowInt := toInt64(math.Min(math.Max(low, minInt), maxInt))
+	highInt := toInt64(math.Min(math.Max(high, minInt), maxInt))
+	if lowInt >= highInt {
+		return lowInt
+	}
+
+	// Compute on unsigned integers, as the span can exceed the maximum int64
+	span := uint64(highInt) - uint64(lowInt) + 1
+	if span == 0 {
+		return int64(r.Uint64())
+	}
+	return int64(uint64(lowInt) + r.Uint64()%span)
+}
+
+// toInt64 converts a float to an integer, saturating to the int64 range as
+// the conversion of a float out of this range is undefined.
+func toInt64(v float64) int64 {
+	switch {
+	case v >= math.Exp2(63):
+		return math.MaxInt64
+	case v <= math.MinInt64:
+		return math.MinInt64
+	default:
+		return int64(v)
+	}
+}
+
+// RandomNumber returns a random number between the minimum and maximum (or
+// exclusive minimum and maximum). The number is a multiple of 0.25 when
+// possible, so it is the same as a float32 or as a decimal string.
+func RandomNumber(r *rand.Rand, c RandomConstraints) float64 {
+	lo, hi, exclusiveLo, exclusiveHi := c.bounds()
+	isValid := func(v float64) bool {
+		return (v > lo || (!exclusiveLo && v == lo)) && (v < hi || (!exclusiveHi && v == hi))
+	}
+
+	v := lo + r.Float64()*(hi-lo)
+	if rounded := math.Round(v*4) / 4; isValid(rounded) {
+		return rounded
+	} else if !isValid(v) {
+		return lo + (hi-lo)/2
+	}
+	return v
+}
+
+// RandomSlice returns a slice with a random number of items between the
+// minimum and maximum number of items, each one being created by the
+// function. The items are compared as JSON when they should be unique.
+func RandomSlice[T any](r *rand.Rand, depth int, c RandomConstraints, item func() T) []T {
+	n := c.items(r, depth)
+	items := make([]T, 0, n)
+	seen := make(map[string]bool, n)
+	for i := 0; len(items) < n && i < randomAttempts*n; i++ {
+		v := item()
+		if c.UniqueItems {
+			key, err := json.Marshal(v)
+			if err == nil && seen[string(key)] {
+				continue
+			}
+			seen[string(key)] = true
+		}
+		items = append(items, v)
+	}
+	return items
+}
+
+// RandomMap returns a map with one or two values, whose keys and values are
+// created by the functions.
+func RandomMap[T any](r *rand.Rand, key func() string, value func() T) map[string]T {
+	n := 1 + r.Intn(2)
+	m := make(map[string]T, n)
+	for i := 0; len(m) < n && i < randomAttempts; i++ {
+		m[key()] = value()
+	}
+	return m
+}
+
+// randomFormats are the functions creating random strings with a format.
+var randomFormats = map[string]func(r *rand.Rand) string{
+	"date-time": func(r *rand.Rand) string {
+		return randomTime(r).Format(time.RFC3339)
+	},
+	"date": func(r *rand.Rand) string {
+		return randomTime(r).Format(time.DateOnly)
+	},
+	"time": func(r *rand.Rand) string {
+		return randomTime(r).Format(time.TimeOnly) + "Z"
+	},
+	"duration": func(r *rand.Rand) string {
+		return Duration(time.Duration(r.Int63n(7*24*3600)) * time.Second).String()
+	},
+	"uuid": func(r *rand.Rand) string {
+		var b [16]byte
+		_, _ = r.Read(b[:])
+		b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+		b[8] = (b[8] & 0x3f) | 0x80 // Variant RFC 4122
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	},
+	"email": func(r *rand.Rand) string {
+		return randomLowercase(r, 8) + "@example.com"
+	},
+	"hostname": func(r *rand.Rand) string {
+		return randomLowercase(r, 8) + ".example.com"
+	},
+	"uri": func(r *rand.Rand) string {
+		return "https://example.com/" + randomLowercase(r, 8)
+	},
+	"ipv4": func(r *rand.Rand) string {
+		var b [4]byte
+		_, _ = r.Read(b[:])
+		return netip.AddrFrom4(b).String()
+	},
+	"ipv6": func(r *rand.Rand) string {
+		var b [16]byte
+		_, _ = r.Read(b[:])
+		return netip.AddrFrom16(b).String()
+	},
+	"byte": func(r *rand.Rand) string {
+		b := make([]byte, 1+r.Intn(randomMaxLength))
+		_, _ = r.Read(b)
+		return base64.StdEncoding.EncodeToString(b)
+	},
+}
+
+// randomTime returns a random time, to the second, between 2000 and 2030.
+func randomTime(r *rand.Rand) time.Time {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	return start.Add(time.Duration(r.Int63n(30*365*24*3600)) * time.Second)
+}
+
+func randomLowercase(r *rand.Rand, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + r.Intn(26))
+	}
+	return string(b)
+}
+
+// length returns a random length between the minimum and maximum lengths,
+// with at least one character if possible.
+func (c RandomConstraints) length(r *rand.Rand) int {
+	lo, hi := c.MinLength, c.MaxLength
+	if !c.HasMaxLength {
+		hi = lo + randomMaxLength
+	}
+	if lo == 0 && hi > 0 {
+		lo = 1
+	}
+	if hi <= lo {
+		return int(lo)
+	}
+	return int(lo) + r.Intn(int(hi-lo)+1)
+}
+
+func (c RandomConstraints) hasLength(n int) bool {
+	return n >= int(c.MinLength) && (!c.HasMaxLength || n <= int(c.MaxLength))
+}
+
+// items returns a random number of items between the minimum and maximum
+// number of items, with at least one item if possible, and only the minimum
+// from the maximum depth.
+func (c RandomConstraints) items(r *rand.Rand, depth int) int {
+	lo, hi := c.MinItems, c.MaxItems
+	if depth >= RandomMaxDepth {
+		return int(lo)
+	}
+	if !c.HasMaxItems {
+		hi = lo + randomMaxLength/4
+	}
+	if lo == 0 && hi > 0 {
+		lo = 1
+	}
+	if hi <= lo {
+		return int(lo)
+	}
+	return int(lo) + r.Intn(int(hi-lo)+1)
+}
+
+// bounds returns the range of the random numbers. Without minimum, the range
+// starts at zero (or below the maximum if it is negative), and without
+// maximum it has a fixed size.
+func (c RandomConstraints) bounds() (lo, hi float64, exclusiveLo, exclusiveHi bool) {
+	lo, hi = math.Inf(-1), math.Inf(1)
+	if c.HasMinimum {
+		lo = c.Minimum
+	}
+	if c.HasExclusiveMinimum && c.ExclusiveMinimum >= lo {
+		lo, exclusiveLo = c.ExclusiveMinimum, true
+	}
+	if c.HasMaximum {
+		hi = c.Maximum
+	}
+	if c.HasExclusiveMaximum && c.ExclusiveMaximum <= hi {
+		hi, exclusiveHi = c.ExclusiveMaximum, true
+	}
+
+	if math.IsInf(lo, -1) {
+		lo = 0
+		if hi <= 0 {
+			lo, exclusiveLo = hi-randomRange, false
+		}
+	}
+	if math.IsInf(hi, 1) {
+		hi = lo + randomRange
+	}
+
+	return lo, hi, exclusiveLo, exclusiveHi
+}
+
+// writeRandomPattern writes a random string matching the regular expression.
+//
+//nolint:cyclop // Not necessary to split the operators
+func writeRandomPattern(r *rand.Rand, b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, c := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && r.Intn(2) == 0 {
+				c = unicode.SimpleFold(c)
+			}
+			b.WriteRune(c)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(randomClassRune(r, re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(randomCharacters[r.Intn(len(randomCharacters))])
+	case syntax.OpCapture:
+		writeRandomPattern(r, b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeRandomPattern(r, b, sub)
+		}
+	case syntax.OpAlternate:
+		writeRandomPattern(r, b, RandomChoice(r, re.Sub))
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			lo, hi = 0, randomMaxRepeat
+		case syntax.OpPlus:
+			lo, hi = 1, randomMaxRepeat
+		case syntax.OpQuest:
+			lo, hi = 0, 1
+		default:
+			if hi < 0 {
+				hi = lo + randomMaxRepeat
+			}
+		}
+		for i := lo + r.Intn(hi-lo+1); i > 0; i-- {
+			writeRandomPattern(r, b, re.Sub[0])
+		}
+	default:
+		// Empty matches, anchors and word boundaries don't produce characters
+	}
+}
+
+// randomClassRune returns a random rune from the ranges of a character class,
+// preferring the printable ASCII characters.
+func randomClassRune(r *rand.Rand, ranges []rune) rune {
+	printable := make([]rune, 0, len(ranges))
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := max(ranges[i], '!'), min(ranges[i+1], '~')
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+	if len(printable) > 0 {
+		ranges = printable
+	}
+
+	// Pick a rune, with the same probability for all the runes of the ranges
+	var total int
+	for i := 0; i+1 < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	if total == 0 {
+		return 'a'
+	}
+	n := r.Intn(total)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		size := int(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n)
+		}
+		n -= size
+	}
+	return ranges[0]
+}
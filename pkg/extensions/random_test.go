@@ -0,0 +1,146 @@
+package extensions
+
+import (
+	"math"
+	"math/rand"
+	"net/netip"
+	"regexp"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRandomSuite(t *testing.T) {
+	suite.Run(t, new(RandomSuite))
+}
+
+type RandomSuite struct {
+	suite.Suite
+}
+
+func (suite *RandomSuite) TestString() {
+	r := rand.New(rand.NewSource(1))
+
+	cases := []RandomConstraints{
+		{},
+		{MinLength: 3, MaxLength: 5, HasMaxLength: true},
+		{MaxLength: 1, HasMaxLength: true},
+		{MaxLength: 0, HasMaxLength: true},
+		{Pattern: `^[A-Z]{2}-\d{4}$`},
+		{Pattern: `^(foo|bar)+[^a-z]?$`, MaxLength: 9, HasMaxLength: true},
+		{Pattern: `(?i)^abc\.`},
+		{Format: "email"},
+		{Format: "date-time"},
+		// Constraints that can't be satisfied together
+		{Pattern: `^a{10}$`, MaxLength: 4, HasMaxLength: true},
+	}
+
+	for _, c := range cases {
+		for i := 0; i < 50; i++ {
+			s := RandomString(r, c)
+			n := utf8.RuneCountInString(s)
+			suite.Require().GreaterOrEqual(n, int(c.MinLength), c)
+			if c.HasMaxLength {
+				suite.Require().LessOrEqual(n, int(c.MaxLength), c)
+			}
+			if c.Pattern != "" && c.MaxLength != 4 {
+				suite.Require().Regexp(regexp.MustCompile(c.Pattern), s, c)
+			}
+			if c.Format == "date-time" {
+				_, err := time.Parse(time.RFC3339, s)
+				suite.Require().NoError(err)
+			}
+		}
+	}
+}
+
+func (suite *RandomSuite) TestText() {
+	r := rand.New(rand.NewSource(1))
+
+	id := RandomText[uuid.UUID](r, "uuid")
+	suite.Require().Equal(uuid.Version(4), id.Version())
+	suite.Require().True(RandomText[netip.Addr](r, "ipv4").Is4())
+	suite.Require().True(RandomText[netip.Addr](r, "ipv6").Is6())
+	suite.Require().NotZero(RandomText[time.Time](r, "date-time"))
+	suite.Require().Equal("https", RandomText[URL](r, "uri").Scheme)
+	suite.Require().GreaterOrEqual(RandomText[Duration](r, "duration"), Duration(0))
+
+	suite.Require().Panics(func() { RandomText[URL](r, "unknown") })
+}
+
+func (suite *RandomSuite) TestNumbers() {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100; i++ {
+		v := RandomInteger(r, RandomConstraints{Minimum: -3, HasMinimum: true, Maximum: 3, HasMaximum: true}, 64)
+		suite.Require().True(v >= -3 && v <= 3, v)
+
+		v = RandomInteger(r, RandomConstraints{
+			ExclusiveMinimum: 1, HasExclusiveMinimum: true, ExclusiveMaximum: 3, HasExclusiveMaximum: true}, 64)
+		suite.Require().Equal(int64(2), v)
+
+		v = RandomInteger(r, RandomConstraints{Maximum: -10, HasMaximum: true}, 32)
+		suite.Require().True(v >= -1010 && v <= -10, v)
+
+		v = RandomInteger(r, RandomConstraints{Minimum: 100, HasMinimum: true}, 8)
+		suite.Require().True(v >= 100 && v <= 127, v)
+
+		// Zero is a bound when it is set
+		v = RandomInteger(r, RandomConstraints{Maximum: 0, HasMaximum: true}, 64)
+		suite.Require().True(v >= -1000 && v <= 0, v)
+
+		v = RandomInteger(r, RandomConstraints{ExclusiveMaximum: 0, HasExclusiveMaximum: true}, 64)
+		suite.Require().True(v >= -1000 && v < 0, v)
+
+		// The range of the type, without overflow
+		v = RandomInteger(r, RandomConstraints{Minimum: -1e30, HasMinimum: true, Maximum: 1e30, HasMaximum: true}, 64)
+		suite.Require().NotZero(v)
+
+		v = RandomInteger(r, RandomConstraints{Minimum: 1e30, HasMinimum: true}, 64)
+		suite.Require().Equal(int64(math.MaxInt64), v)
+
+		f := RandomNumber(r, RandomConstraints{
+			ExclusiveMinimum: 0.5, HasExclusiveMinimum: true, Maximum: 0.75, HasMaximum: true})
+		suite.Require().True(f > 0.5 && f <= 0.75, f)
+
+		f = RandomNumber(r, RandomConstraints{})
+		suite.Require().True(f >= 0 && f <= 1000, f)
+		suite.Require().Equal(f, float64(float32(f)))
+	}
+}
+
+func (suite *RandomSuite) TestSlice() {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		items := RandomSlice(r, 0, RandomConstraints{MinItems: 2, MaxItems: 3, HasMaxItems: true, UniqueItems: true}, func() int64 {
+			return RandomInteger(r, RandomConstraints{Minimum: 1, HasMinimum: true, Maximum: 3, HasMaximum: true}, 64)
+		})
+		suite.Require().True(len(items) == 2 || len(items) == 3, items)
+		suite.Require().NotEqual(items[0], items[1])
+	}
+
+	// No items when the maximum is zero
+	items0 := RandomSlice(r, 0, RandomConstraints{MaxItems: 0, HasMaxItems: true}, func() string { return "" })
+	suite.Require().Empty(items0)
+
+	// Only the minimum number of items from the maximum depth
+	items := RandomSlice(r, RandomMaxDepth, RandomConstraints{}, func() string { return "" })
+	suite.Require().NotNil(items)
+	suite.Require().Empty(items)
+}
+
+func (suite *RandomSuite) TestReproducible() {
+	generate := func(seed int64) []string {
+		r := rand.New(rand.NewSource(seed))
+		return RandomSlice(r, 0, RandomConstraints{MinItems: 5}, func() string {
+			return RandomString(r, RandomConstraints{Pattern: `^[a-f0-9]{8}$`})
+		})
+	}
+
+	suite.Require().Equal(generate(42), generate(42))
+	suite.Require().NotEqual(generate(42), generate(43))
+}
@@ -1,5 +1,10 @@
 package utils
 
+import (
+	"cmp"
+	"slices"
+)
+
 // MapToList will change a map to a list.
 func MapToList[T1 comparable, T2 any](m map[T1]T2) []T2 {
 	l := make([]T2, 0, len(m))
@@ -8,3 +13,13 @@ func MapToList[T1 comparable, T2 any](m map[T1]T2) []T2 {
 	}
 	return l
 }
+
+// SortedKeys will return the keys of a map, sorted.
+func SortedKeys[T1 cmp.Ordered, T2 any](m map[T1]T2) []T1 {
+	keys := make([]T1, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
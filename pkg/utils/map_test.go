@@ -22,3 +22,13 @@ func TestMapToListWithString(t *testing.T) {
 	less := func(a, b string) bool { return a < b }
 	assert.Equal(t, cmp.Diff(expectedOutput, MapToList(input), cmpopts.SortSlices(less)), "")
 }
+
+func TestSortedKeys(t *testing.T) {
+	input := map[string]int{
+		"c": 3,
+		"a": 1,
+		"d": 4,
+		"b": 2,
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d"}, SortedKeys(input))
+}
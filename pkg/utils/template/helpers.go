@@ -5,6 +5,7 @@ import (
 	"html/template"
 	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 	"unicode"
 
@@ -144,13 +145,38 @@ func CutSuffix(s, suffix string) string {
 	return s
 }
 
-var isDateOrDateTimeGenerated = func(format string) bool {
-	return format == "date" || format == "date-time"
+// StringFormats are the string formats that can be generated as specific
+// golang types instead of strings.
+var StringFormats = []string{"date", "date-time", "uuid", "byte", "binary", "duration", "uri", "ipv4", "ipv6"}
+
+var disabledStringFormats = make(map[string]bool)
+
+// IsStringFormatGenerated checks if a string format is generated as a specific
+// golang type.
+func IsStringFormatGenerated(format string) bool {
+	return slices.Contains(StringFormats, format) && !disabledStringFormats[format]
+}
+
+// DisableStringFormats is used to disable the generation of specific types
+// for the given string formats, which will then be generated as strings.
+func DisableStringFormats(formats ...string) error {
+	for _, f := range formats {
+		if !slices.Contains(StringFormats, f) {
+			return fmt.Errorf("unknown string format %q, supported values: %s", f, strings.Join(StringFormats, ", "))
+		}
+		disabledStringFormats[f] = true
+	}
+
+	return nil
+}
+
+func isDateOrDateTimeGenerated(format string) bool {
+	return (format == "date" || format == "date-time") && IsStringFormatGenerated(format)
 }
 
 // DisableDateOrTimeGeneration is used to disable the generation of date/date-time formats within types.
 func DisableDateOrTimeGeneration() {
-	isDateOrDateTimeGenerated = func(_ string) bool { return false }
+	_ = DisableStringFormats("date", "date-time")
 }
 
 // HelpersFunctions returns the functions that can be used as helpers
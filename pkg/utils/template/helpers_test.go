@@ -67,3 +67,17 @@ func (suite *HelpersSuite) TestNamifyWithoutParams() {
 		suite.Require().Equal(c.Out, NamifyWithoutParams(c.In), i)
 	}
 }
+
+func (suite *HelpersSuite) TestDisableStringFormats() {
+	defer func() { disabledStringFormats = make(map[string]bool) }()
+
+	suite.Require().True(IsStringFormatGenerated("uuid"))
+	suite.Require().True(IsStringFormatGenerated("date"))
+	suite.Require().False(IsStringFormatGenerated("email"))
+
+	suite.Require().NoError(DisableStringFormats("uuid"))
+	suite.Require().False(IsStringFormatGenerated("uuid"))
+	suite.Require().True(IsStringFormatGenerated("date"))
+
+	suite.Require().Error(DisableStringFormats("email"))
+}
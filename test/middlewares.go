@@ -0,0 +1,16 @@
+package test
+
+import (
+	"context"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+)
+
+// Recorder returns a middleware that will keep the last broker message going
+// through it, in order to check what has been sent or received.
+func Recorder(received *extensions.BrokerMessage) extensions.Middleware {
+	return func(ctx context.Context, msg *extensions.BrokerMessage, next extensions.NextMiddleware) error {
+		*received = *msg
+		return next(ctx)
+	}
+}
@@ -0,0 +1,94 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -p allof -i ./asyncapi.yaml -o ./asyncapi.gen.go
+
+package allof
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	app    *AppController
+	user   *UserController
+
+	admins chan AdminMessageFromAdminsChannel
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) SetupSuite() {
+	// Create app
+	app, err := NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	suite.app = app
+
+	// Create user
+	user, err := NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	suite.user = user
+
+	// Subscribe to admins operation
+	suite.admins = make(chan AdminMessageFromAdminsChannel, 1)
+	err = suite.app.SubscribeToReceiveAdminsOperation(context.Background(),
+		func(_ context.Context, msg AdminMessageFromAdminsChannel) error {
+			suite.admins <- msg
+			return nil
+		})
+	suite.Require().NoError(err)
+}
+
+func (suite *Suite) TearDownSuite() {
+	suite.app.Close(context.Background())
+	suite.user.Close(context.Background())
+}
+
+func (suite *Suite) TestFlattenedWithRequired() {
+	// Properties and required fields from the referenced schema are merged
+	user := UserSchema{
+		Id:     "1234",
+		Name:   "John",
+		Status: utils.ToPointer(StatusSchema("active")),
+	}
+
+	data, err := json.Marshal(user)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(`{"id":"1234","name":"John","status":"active"}`, string(data))
+}
+
+func (suite *Suite) TestEmbedded() {
+	// Send an admin, with the user schema embedded
+	var msg AdminMessageFromAdminsChannel
+	msg.Payload.UserSchema = UserSchema{Id: "1234", Name: "John"}
+	msg.Payload.Role = utils.ToPointer("owner")
+	suite.Require().NoError(suite.user.SendToReceiveAdminsOperation(context.Background(), msg))
+
+	// Check that the fields are flattened in JSON and received correctly
+	received := <-suite.admins
+	suite.Require().Equal(msg, received)
+	suite.Require().Equal("John", received.Payload.Name)
+
+	data, err := json.Marshal(received.Payload)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(`{"id":"1234","name":"John","role":"owner"}`, string(data))
+}
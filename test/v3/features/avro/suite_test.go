@@ -0,0 +1,128 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -p raw -i ./asyncapi.yaml -o ./raw/asyncapi.gen.go
+//go:generate go run ../../../../cmd/asyncapi-codegen --avro-confluent -p confluent -i ./asyncapi.yaml -o ./confluent/asyncapi.gen.go
+
+package avro
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/avro/confluent"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/avro/raw"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) TestRaw() {
+	var received extensions.BrokerMessage
+	app, err := raw.NewAppController(suite.broker, raw.WithReceptionMiddlewares(testutil.Recorder(&received)))
+	suite.Require().NoError(err)
+	defer app.Close(context.Background())
+
+	user, err := raw.NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	defer user.Close(context.Background())
+
+	sent := raw.NewUserMessage()
+	sent.Payload = raw.UserMessagePayload{
+		Id:        uuid.New(),
+		Name:      "bob",
+		Age:       42,
+		Email:     utils.ToPointer("bob@example.com"),
+		Status:    raw.StatusPropertyFromUserMessagePayloadBANNED,
+		Tags:      []string{"a", "b"},
+		CreatedAt: time.UnixMilli(1700000000123).UTC(),
+		Contact:   &raw.ContactPropertyFromUserMessagePayload{Long: utils.ToPointer(int64(1234))},
+		Friend: &raw.UserMessagePayload{
+			Id:        uuid.New(),
+			Name:      "alice",
+			Status:    raw.StatusPropertyFromUserMessagePayloadACTIVE,
+			Tags:      []string{},
+			CreatedAt: time.UnixMilli(0).UTC(),
+		},
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err = app.SubscribeToReceiveRawUsersOperation(context.Background(),
+		func(_ context.Context, msg raw.UserMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer app.UnsubscribeFromReceiveRawUsersOperation(context.Background())
+
+	suite.Require().NoError(user.SendToReceiveRawUsersOperation(context.Background(), sent))
+	wg.Wait()
+
+	// Check that the payload is encoded with the Avro binary encoding, starting
+	// with the length of the ID (zigzag encoded) followed by the ID
+	suite.Require().Equal(byte(2*len(sent.Payload.Id.String())), received.Payload[0])
+	suite.Require().Equal(sent.Payload.Id.String(), string(received.Payload[1:37]))
+}
+
+func (suite *Suite) TestConfluent() {
+	var received extensions.BrokerMessage
+	app, err := confluent.NewAppController(suite.broker, confluent.WithReceptionMiddlewares(testutil.Recorder(&received)))
+	suite.Require().NoError(err)
+	defer app.Close(context.Background())
+
+	user, err := confluent.NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	defer user.Close(context.Background())
+
+	// Get the schema ID from the schema registry, as it is not set on the message
+	extensions.SetAvroSchemaRegistry(extensions.StaticAvroSchemaRegistry{"com.example.User": 42})
+	defer extensions.SetAvroSchemaRegistry(nil)
+
+	sent := confluent.NewUserMessage()
+	sent.Payload.Id = uuid.New()
+	sent.Payload.Name = "bob"
+	sent.Payload.Tags = []string{}
+	sent.Payload.Status = confluent.StatusPropertyFromUserMessagePayloadACTIVE
+	sent.Payload.CreatedAt = time.UnixMilli(1700000000123).UTC()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err = app.SubscribeToReceiveConfluentUsersOperation(context.Background(),
+		func(_ context.Context, msg confluent.UserMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+			suite.Require().Equal(int32(42), msg.AvroSchemaID)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer app.UnsubscribeFromReceiveConfluentUsersOperation(context.Background())
+
+	suite.Require().NoError(user.SendToReceiveConfluentUsersOperation(context.Background(), sent))
+	wg.Wait()
+
+	// Check that the payload is prefixed with the magic byte and the schema ID
+	suite.Require().Equal([]byte{extensions.AvroConfluentMagicByte, 0, 0, 0, 42}, received.Payload[:5])
+}
@@ -0,0 +1,120 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen --cloudevents binary -p binary -i ./asyncapi.yaml -o ./binary/asyncapi.gen.go
+//go:generate go run ../../../../cmd/asyncapi-codegen --cloudevents structured -p structured -i ./asyncapi.yaml -o ./structured/asyncapi.gen.go
+
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/cloudevents/binary"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/cloudevents/structured"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) TestBinary() {
+	var raw extensions.BrokerMessage
+	app, err := binary.NewAppController(suite.broker, binary.WithReceptionMiddlewares(testutil.Recorder(&raw)))
+	suite.Require().NoError(err)
+	defer app.Close(context.Background())
+
+	user, err := binary.NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	defer user.Close(context.Background())
+
+	sent := binary.NewUserSignedUpMessage()
+	sent.CloudEvent.Subject = "user/1234"
+	sent.Payload.Email = utils.ToPointer("user@example.com")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err = app.SubscribeToReceiveBinaryOperation(context.Background(),
+		func(_ context.Context, msg binary.UserSignedUpMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+			suite.Require().Equal(sent.CloudEvent.ID, msg.CloudEvent.ID)
+			suite.Require().Equal(binary.CloudEventsSource, msg.CloudEvent.Source)
+			suite.Require().Equal("UserSignedUp", msg.CloudEvent.Type)
+			suite.Require().Equal("user/1234", msg.CloudEvent.Subject)
+			suite.Require().True(sent.CloudEvent.Time.Equal(msg.CloudEvent.Time))
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer app.UnsubscribeFromReceiveBinaryOperation(context.Background())
+
+	suite.Require().NoError(user.SendToReceiveBinaryOperation(context.Background(), sent))
+	wg.Wait()
+
+	// Check that attributes are sent as headers and payload is left untouched
+	suite.Require().Equal(sent.CloudEvent.ID, string(raw.Headers[extensions.CloudEventsHeaderID]))
+	suite.Require().Equal(extensions.CloudEventsSpecVersion, string(raw.Headers[extensions.CloudEventsHeaderSpecVersion]))
+	suite.Require().JSONEq(`{"email":"user@example.com"}`, string(raw.Payload))
+}
+
+func (suite *Suite) TestStructured() {
+	var raw extensions.BrokerMessage
+	app, err := structured.NewAppController(suite.broker, structured.WithReceptionMiddlewares(testutil.Recorder(&raw)))
+	suite.Require().NoError(err)
+	defer app.Close(context.Background())
+
+	user, err := structured.NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	defer user.Close(context.Background())
+
+	sent := structured.NewUserSignedUpMessage()
+	sent.CloudEvent.Subject = "user/1234"
+	sent.Payload.Email = utils.ToPointer("user@example.com")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err = app.SubscribeToReceiveStructuredOperation(context.Background(),
+		func(_ context.Context, msg structured.UserSignedUpMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+			suite.Require().Equal(sent.CloudEvent.ID, msg.CloudEvent.ID)
+			suite.Require().Equal(structured.CloudEventsSource, msg.CloudEvent.Source)
+			suite.Require().Equal("UserSignedUp", msg.CloudEvent.Type)
+			suite.Require().Equal("user/1234", msg.CloudEvent.Subject)
+			suite.Require().True(sent.CloudEvent.Time.Equal(msg.CloudEvent.Time))
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer app.UnsubscribeFromReceiveStructuredOperation(context.Background())
+
+	suite.Require().NoError(user.SendToReceiveStructuredOperation(context.Background(), sent))
+	wg.Wait()
+
+	// Check that the payload is a CloudEvent envelope
+	suite.Require().Equal(extensions.CloudEventsStructuredContentType,
+		string(raw.Headers[extensions.CloudEventsHeaderContentType]))
+	var envelope map[string]any
+	suite.Require().NoError(json.Unmarshal(raw.Payload, &envelope))
+	suite.Require().Equal(sent.CloudEvent.ID, envelope["id"])
+	suite.Require().Equal(extensions.CloudEventsSpecVersion, envelope["specversion"])
+	suite.Require().Equal(map[string]any{"email": "user@example.com"}, envelope["data"])
+}
@@ -0,0 +1,221 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -p contenttypes -i ./asyncapi.yaml -o ./asyncapi.gen.go
+
+package contenttypes
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker   extensions.BrokerController
+	app      *AppController
+	user     *UserController
+	received extensions.BrokerMessage
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) SetupTest() {
+	app, err := NewAppController(suite.broker, WithReceptionMiddlewares(testutil.Recorder(&suite.received)))
+	suite.Require().NoError(err)
+	suite.app = app
+
+	user, err := NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	suite.user = user
+}
+
+func (suite *Suite) TearDownTest() {
+	suite.app.Close(context.Background())
+	suite.user.Close(context.Background())
+}
+
+func newUser() UserSchema {
+	return UserSchema{
+		Name:      "bob",
+		Age:       utils.ToPointer(int64(42)),
+		CreatedAt: utils.ToPointer(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Tags:      []string{"a", "b"},
+	}
+}
+
+func (suite *Suite) TestMessagePack() {
+	sent := NewMsgpackUserMessage()
+	sent.Payload = newUser()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err := suite.app.SubscribeToReceiveMsgpackOperation(context.Background(),
+		func(_ context.Context, msg MsgpackUserMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer suite.app.UnsubscribeFromReceiveMsgpackOperation(context.Background())
+
+	suite.Require().NoError(suite.user.SendToReceiveMsgpackOperation(context.Background(), sent))
+	wg.Wait()
+
+	// Check that the payload is a MessagePack map with the content type header
+	suite.Require().Equal([]byte("application/msgpack"), suite.received.Headers[extensions.ContentTypeHeader])
+	suite.Require().Equal(byte(0x84), suite.received.Payload[0])
+}
+
+func (suite *Suite) TestCBOR() {
+	sent := NewCborUserMessage()
+	sent.Payload = newUser()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err := suite.app.SubscribeToReceiveCborOperation(context.Background(),
+		func(_ context.Context, msg CborUserMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer suite.app.UnsubscribeFromReceiveCborOperation(context.Background())
+
+	suite.Require().NoError(suite.user.SendToReceiveCborOperation(context.Background(), sent))
+	wg.Wait()
+
+	// Check that the payload is a CBOR map with the content type header
+	suite.Require().Equal([]byte("application/cbor"), suite.received.Headers[extensions.ContentTypeHeader])
+	suite.Require().Equal(byte(0xa4), suite.received.Payload[0])
+}
+
+func (suite *Suite) TestDefaultContentType() {
+	sent := NewJsonUserMessage()
+	sent.Payload = newUser()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err := suite.app.SubscribeToReceiveJsonOperation(context.Background(),
+		func(_ context.Context, msg JsonUserMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer suite.app.UnsubscribeFromReceiveJsonOperation(context.Background())
+
+	suite.Require().NoError(suite.user.SendToReceiveJsonOperation(context.Background(), sent))
+	wg.Wait()
+
+	// Check that the payload is a JSON object with the default content type header
+	suite.Require().Equal([]byte("application/json"), suite.received.Headers[extensions.ContentTypeHeader])
+	suite.Require().Equal(byte('{'), suite.received.Payload[0])
+}
+
+func (suite *Suite) TestReceivedContentType() {
+	sent := newUser()
+	payload, err := extensions.MarshalWithContentType(extensions.CBORContentType, sent)
+	suite.Require().NoError(err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err = suite.app.SubscribeToReceiveJsonOperation(context.Background(),
+		func(_ context.Context, msg JsonUserMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(sent, msg.Payload)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer suite.app.UnsubscribeFromReceiveJsonOperation(context.Background())
+
+	// Send a CBOR payload on the JSON channel, that should be decoded with
+	// the codec of the received content type
+	suite.Require().NoError(suite.broker.Publish(context.Background(), "v3.features.contenttypes.json",
+		extensions.BrokerMessage{
+			Headers: map[string][]byte{extensions.ContentTypeHeader: []byte(extensions.CBORContentType)},
+			Payload: payload,
+		}))
+	wg.Wait()
+}
+
+func (suite *Suite) TestString() {
+	sent := NewNameMessageFromStringChannel()
+	sent.Payload = "bob"
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err := suite.app.SubscribeToReceiveStringOperation(context.Background(),
+		func(_ context.Context, msg NameMessageFromStringChannel) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer suite.app.UnsubscribeFromReceiveStringOperation(context.Background())
+
+	suite.Require().NoError(suite.user.SendToReceiveStringOperation(context.Background(), sent))
+	wg.Wait()
+
+	// The strings are sent as JSON, as the default content type
+	suite.Require().Equal(`"bob"`, string(suite.received.Payload))
+}
+
+func (suite *Suite) TestText() {
+	sent := NewCountMessageFromTextChannel()
+	sent.Payload = 1234
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err := suite.app.SubscribeToReceiveTextOperation(context.Background(),
+		func(_ context.Context, msg CountMessageFromTextChannel) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer suite.app.UnsubscribeFromReceiveTextOperation(context.Background())
+
+	suite.Require().NoError(suite.user.SendToReceiveTextOperation(context.Background(), sent))
+	wg.Wait()
+
+	suite.Require().Equal("1234", string(suite.received.Payload))
+}
+
+func (suite *Suite) TestOctetStream() {
+	sent := NewDataMessageFromBinaryChannel()
+	sent.Payload = []byte{0xde, 0xad, 0xbe, 0xef}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err := suite.app.SubscribeToReceiveBinaryOperation(context.Background(),
+		func(_ context.Context, msg DataMessageFromBinaryChannel) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer suite.app.UnsubscribeFromReceiveBinaryOperation(context.Background())
+
+	suite.Require().NoError(suite.user.SendToReceiveBinaryOperation(context.Background(), sent))
+	wg.Wait()
+
+	suite.Require().Equal(sent.Payload, suite.received.Payload)
+}
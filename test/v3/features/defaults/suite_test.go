@@ -0,0 +1,104 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -p defaults -i ./asyncapi.yaml -o ./asyncapi.gen.go
+
+package defaults
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	app    *AppController
+
+	orders chan OrderMessageFromOrdersChannel
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) SetupSuite() {
+	// Create app
+	app, err := NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	suite.app = app
+
+	// Subscribe to orders operation
+	suite.orders = make(chan OrderMessageFromOrdersChannel, 1)
+	err = suite.app.SubscribeToReceiveOrdersOperation(context.Background(),
+		func(_ context.Context, msg OrderMessageFromOrdersChannel) error {
+			suite.orders <- msg
+			return nil
+		})
+	suite.Require().NoError(err)
+}
+
+func (suite *Suite) TearDownSuite() {
+	suite.app.Close(context.Background())
+}
+
+func (suite *Suite) TestSchemaConstructor() {
+	order := NewOrderSchema()
+	suite.Require().Equal(int64(1), *order.Quantity)
+	suite.Require().False(*order.Gift)
+	suite.Require().Equal(StatusSchemaPending, *order.Status)
+	suite.Require().Equal([]string{"new"}, order.Tags)
+
+	// Nested objects are not created, only filled when present
+	suite.Require().Nil(order.Shipping)
+	suite.Require().Nil(order.Items)
+}
+
+func (suite *Suite) TestSetDefaultsKeepsValues() {
+	order := OrderSchema{
+		Quantity: utils.ToPointer(int64(3)),
+		Shipping: &ShippingSchema{Method: utils.ToPointer("express")},
+		Items:    []ItemSchema{{Name: utils.ToPointer("pen")}},
+	}
+	order.SetDefaults()
+
+	suite.Require().Equal(int64(3), *order.Quantity)
+	suite.Require().Equal("express", *order.Shipping.Method)
+	suite.Require().Equal("piece", *order.Items[0].Unit)
+}
+
+func (suite *Suite) TestMessageConstructor() {
+	msg := NewOrderMessageFromOrdersChannel()
+	suite.Require().Equal("v1", *msg.Headers.Version)
+	suite.Require().Equal(int64(1), *msg.Payload.Quantity)
+}
+
+func (suite *Suite) TestDefaultsOnReception() {
+	// Send a message with missing fields
+	err := suite.broker.Publish(context.Background(), OrdersChannelPath, extensions.BrokerMessage{
+		Headers: map[string][]byte{},
+		Payload: []byte(`{"id":"1234","shipping":{},"items":[{"name":"pen"}]}`),
+	})
+	suite.Require().NoError(err)
+
+	// Check that defaults are set
+	msg := <-suite.orders
+	suite.Require().Equal("v1", *msg.Headers.Version)
+	suite.Require().Equal("1234", msg.Payload.Id)
+	suite.Require().Equal(int64(1), *msg.Payload.Quantity)
+	suite.Require().Equal("standard", *msg.Payload.Shipping.Method)
+	suite.Require().Equal("piece", *msg.Payload.Items[0].Unit)
+}
@@ -0,0 +1,70 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -p strict -i ./asyncapi.yaml -o ./strict/asyncapi.gen.go
+//go:generate go run ../../../../cmd/asyncapi-codegen --allow-unknown-enums -p lax -i ./asyncapi.yaml -o ./lax/asyncapi.gen.go
+
+package enums
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/enums/lax"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/enums/strict"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, NewSuite())
+}
+
+type Suite struct {
+	suite.Suite
+}
+
+func NewSuite() *Suite {
+	return &Suite{}
+}
+
+func (suite *Suite) TestConstants() {
+	suite.Require().Equal(strict.StatusSchema("in-progress"), strict.StatusSchemaInProgress)
+	suite.Require().Equal(strict.PriorityPropertyFromOrderSchema(2), strict.PriorityPropertyFromOrderSchemaValue2)
+	suite.Require().Equal(strict.ItemFromTagsPropertyFromOrderSchema("gift"), strict.ItemFromTagsPropertyFromOrderSchemaGift)
+}
+
+func (suite *Suite) TestIsValidAndValues() {
+	suite.Require().True(strict.StatusSchemaDone.IsValid())
+	suite.Require().False(strict.StatusSchema("unknown").IsValid())
+	suite.Require().Equal([]strict.StatusSchema{
+		strict.StatusSchemaPending,
+		strict.StatusSchemaInProgress,
+		strict.StatusSchemaDone,
+	}, strict.StatusSchema("").Values())
+}
+
+func (suite *Suite) TestStrictUnmarshal() {
+	var order strict.OrderSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{"version":2,"status":"done","tags":["gift"]}`), &order))
+	suite.Require().Equal(strict.StatusSchemaDone, order.Status)
+
+	err := json.Unmarshal([]byte(`{"version":2,"status":"cancelled"}`), &order)
+	suite.Require().ErrorIs(err, extensions.ErrInvalidEnumValue)
+
+	err = json.Unmarshal([]byte(`{"version":2,"status":"done","priority":4}`), &order)
+	suite.Require().ErrorIs(err, extensions.ErrInvalidEnumValue)
+
+	err = json.Unmarshal([]byte(`{"version":2,"status":"done","tags":["fragile"]}`), &order)
+	suite.Require().ErrorIs(err, extensions.ErrInvalidEnumValue)
+}
+
+func (suite *Suite) TestLaxUnmarshal() {
+	var order lax.OrderSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{"version":2,"status":"cancelled"}`), &order))
+	suite.Require().Equal(lax.StatusSchema("cancelled"), order.Status)
+	suite.Require().False(order.Status.IsValid())
+}
+
+func (suite *Suite) TestConstInConstructor() {
+	msg := strict.NewOrderUpdatedMessageFromOrdersChannel()
+	suite.Require().Equal("order-updated", *msg.Headers.EventType)
+	suite.Require().Equal(int64(2), msg.Payload.Version)
+}
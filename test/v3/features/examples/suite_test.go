@@ -0,0 +1,112 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen --examples -p examples -i ./asyncapi.yaml -o ./asyncapi.gen.go
+
+package examples
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) TestExamples() {
+	examples := ExamplesUserMessage()
+	suite.Require().Len(examples, 2)
+
+	// First example, with all the fields
+	suite.Require().Equal(examples[0], ExampleUserMessage())
+	suite.Require().Equal("acme", examples[0].Headers.TenantId)
+	suite.Require().Equal(uuid.MustParse("1b4e28ba-2fa1-11d2-883f-0016d3cca427"), *examples[0].Headers.RequestId)
+	suite.Require().Equal("alice", examples[0].Payload.Name)
+	suite.Require().Equal(int64(42), *examples[0].Payload.Age)
+	suite.Require().Equal(RolePropertyFromUserMessagePayloadAdmin, *examples[0].Payload.Role)
+	suite.Require().Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), *examples[0].Payload.CreatedAt)
+	suite.Require().Equal([]string{"founder"}, examples[0].Payload.Tags)
+
+	// Second example, with the default values from the specification
+	suite.Require().Equal("bob", examples[1].Payload.Name)
+	suite.Require().Nil(examples[1].Headers.RequestId)
+	suite.Require().Equal(RolePropertyFromUserMessagePayloadMember, *examples[1].Payload.Role)
+
+	// Message with a non-object payload
+	suite.Require().Equal("hello", ExampleNoteMessageFromNotesChannel().Payload)
+}
+
+func (suite *Suite) TestSendExample() {
+	app, err := NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	defer app.Close(context.Background())
+
+	user, err := NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	defer user.Close(context.Background())
+
+	sent := ExampleUserMessage()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err = app.SubscribeToReceiveUsersOperation(context.Background(),
+		func(_ context.Context, msg UserMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Headers, msg.Headers)
+			suite.Require().Equal(sent.Payload, msg.Payload)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer app.UnsubscribeFromReceiveUsersOperation(context.Background())
+
+	suite.Require().NoError(user.SendToReceiveUsersOperation(context.Background(), sent))
+	wg.Wait()
+}
+
+func (suite *Suite) TestInvalidExample() {
+	defer func(original []struct{ Headers, Payload string }) {
+		examplesOfUserMessage = original
+	}(examplesOfUserMessage)
+
+	cases := []struct{ Headers, Payload string }{
+		{Headers: `{"tenantId":"acme"}`, Payload: `{"name":"bob","unknown":true}`},
+		{Headers: `{"tenantId":"acme"}`, Payload: `{"name":"bob","role":"owner"}`},
+		{Headers: `{"tenantId":42}`, Payload: `{"name":"bob"}`},
+	}
+	for _, c := range cases {
+		examplesOfUserMessage = append(examplesOfUserMessage[:0:0], c)
+
+		_, err := decodeUserMessageExamples()
+		suite.Require().ErrorIs(err, extensions.ErrInvalidExample, c)
+		suite.Require().Panics(func() { ExamplesUserMessage() }, c)
+	}
+
+	// Check that the valid examples are still valid
+	examplesOfUserMessage = []struct{ Headers, Payload string }{
+		{Headers: `{"tenantId":"acme"}`, Payload: `{"name":"bob","age":3}`},
+	}
+	msgs, err := decodeUserMessageExamples()
+	suite.Require().NoError(err)
+	suite.Require().Equal(int64(3), *msgs[0].Payload.Age)
+}
@@ -0,0 +1,229 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen --fake-controllers -p fakes -i ./asyncapi.yaml -o ./asyncapi.gen.go
+
+package fakes
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/stretchr/testify/suite"
+)
+
+var errInvalidQuantity = errors.New("invalid quantity")
+
+// orderService is an example of code using the app controller through its
+// interface, so it can be tested with the fake controller.
+type orderService struct {
+	ctrl AppControllerAPI
+}
+
+func (s orderService) Start(ctx context.Context) error {
+	if err := s.ctrl.SubscribeToReceiveOrdersOperation(ctx, s.confirm); err != nil {
+		return err
+	}
+
+	return s.ctrl.SubscribeToPingOperation(ctx, func(ctx context.Context, msg PingMessage) error {
+		return s.ctrl.ReplyToPingOperation(ctx, msg, func(replyMsg *PongMessage) {
+			replyMsg.Payload = "pong"
+		})
+	})
+}
+
+func (s orderService) confirm(ctx context.Context, msg OrderMessageFromOrdersChannel) error {
+	if msg.Payload.Quantity != nil && *msg.Payload.Quantity <= 0 {
+		return errInvalidQuantity
+	}
+
+	status := NewStatusMessageFromStatusesChannel()
+	status.Payload.OrderId = msg.Payload.Id
+	status.Payload.Status = "confirmed"
+	return s.ctrl.SendAsSendStatusesOperation(ctx, StatusesChannelParameters{Region: "us"}, status)
+}
+
+func newOrder(id string, quantity int64) OrderMessageFromOrdersChannel {
+	msg := NewOrderMessageFromOrdersChannel()
+	msg.Payload.Id = id
+	msg.Payload.Quantity = &quantity
+	return msg
+}
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) TestServiceWithController() {
+	app, err := NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	defer app.Close(context.Background())
+
+	user, err := NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	defer user.Close(context.Background())
+
+	suite.Require().NoError(orderService{ctrl: app}.Start(context.Background()))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err = user.SubscribeToSendStatusesOperation(context.Background(), StatusesChannelParameters{Region: "us"},
+		func(_ context.Context, msg StatusMessageFromStatusesChannel) error {
+			defer wg.Done()
+			suite.Require().Equal("order-1", msg.Payload.OrderId)
+			suite.Require().Equal("confirmed", msg.Payload.Status)
+			return nil
+		})
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(user.SendToReceiveOrdersOperation(context.Background(), newOrder("order-1", 2)))
+	wg.Wait()
+}
+
+func TestFakeSuite(t *testing.T) {
+	suite.Run(t, new(FakeSuite))
+}
+
+type FakeSuite struct {
+	suite.Suite
+}
+
+func (suite *FakeSuite) TestService() {
+	app := NewFakeAppController()
+	app.Expect("SubscribeToReceiveOrdersOperation", 1)
+	app.Expect("SendAsSendStatusesOperation", 1)
+
+	suite.Require().NoError(orderService{ctrl: app}.Start(context.Background()))
+
+	// Inject an order and check the sent status
+	suite.Require().NoError(app.InjectReceiveOrdersOperation(context.Background(), newOrder("order-1", 2)))
+	sent := app.SentAsSendStatusesOperation()
+	suite.Require().Len(sent, 1)
+	suite.Require().Equal("order-1", sent[0].Payload.OrderId)
+	suite.Require().Equal("confirmed", sent[0].Payload.Status)
+	suite.Require().Equal(StatusesChannelParameters{Region: "us"},
+		app.CallsTo("SendAsSendStatusesOperation")[0].Params)
+
+	// The errors of the callbacks are given back
+	err := app.InjectReceiveOrdersOperation(context.Background(), newOrder("order-2", 0))
+	suite.Require().ErrorIs(err, errInvalidQuantity)
+
+	suite.Require().True(app.AssertExpectations(suite.T()))
+}
+
+func (suite *FakeSuite) TestSetError() {
+	app := NewFakeAppController()
+	suite.Require().NoError(orderService{ctrl: app}.Start(context.Background()))
+
+	errBroker := errors.New("broker unavailable")
+	app.SetError("SendAsSendStatusesOperation", errBroker)
+	err := app.InjectReceiveOrdersOperation(context.Background(), newOrder("order-1", 1))
+	suite.Require().ErrorIs(err, errBroker)
+
+	app.SetError("SendAsSendStatusesOperation", nil)
+	suite.Require().NoError(app.InjectReceiveOrdersOperation(context.Background(), newOrder("order-1", 1)))
+
+	// Unknown methods are refused
+	suite.Require().Panics(func() { app.SetError("SendToUnknown", errBroker) })
+}
+
+func (suite *FakeSuite) TestSubscriptions() {
+	app := NewFakeAppController()
+
+	// Nothing subscribed
+	err := app.InjectReceiveOrdersOperation(context.Background(), newOrder("order-1", 1))
+	suite.Require().ErrorIs(err, extensions.ErrNoSubscription)
+
+	// Subscribed twice
+	suite.Require().NoError(orderService{ctrl: app}.Start(context.Background()))
+	err = orderService{ctrl: app}.Start(context.Background())
+	suite.Require().ErrorIs(err, extensions.ErrAlreadySubscribedChannel)
+
+	// Unsubscribed on close
+	app.Close(context.Background())
+	suite.Require().Len(app.CallsTo("UnsubscribeFromReceiveOrdersOperation"), 1)
+	err = app.InjectReceiveOrdersOperation(context.Background(), newOrder("order-1", 1))
+	suite.Require().ErrorIs(err, extensions.ErrNoSubscription)
+}
+
+func (suite *FakeSuite) TestParameters() {
+	user := NewFakeUserController()
+
+	var received, receivedAll []StatusesChannelParameters
+	err := user.SubscribeToSendStatusesOperation(context.Background(), StatusesChannelParameters{Region: "us"},
+		func(_ context.Context, _ StatusMessageFromStatusesChannel) error {
+			received = append(received, StatusesChannelParameters{Region: "us"})
+			return nil
+		})
+	suite.Require().NoError(err)
+	err = user.SubscribeToAllSendStatusesOperation(context.Background(),
+		func(_ context.Context, params StatusesChannelParameters, _ StatusMessageFromStatusesChannel) error {
+			receivedAll = append(receivedAll, params)
+			return nil
+		})
+	suite.Require().NoError(err)
+
+	// Invalid parameters are refused
+	err = user.SubscribeToSendStatusesOperation(context.Background(), StatusesChannelParameters{Region: "asia"},
+		func(_ context.Context, _ StatusMessageFromStatusesChannel) error { return nil })
+	suite.Require().ErrorIs(err, extensions.ErrInvalidChannelParameter)
+
+	status := NewStatusMessageFromStatusesChannel()
+	suite.Require().NoError(user.InjectSendStatusesOperation(context.Background(), StatusesChannelParameters{Region: "us"}, status))
+	suite.Require().NoError(user.InjectSendStatusesOperation(context.Background(), StatusesChannelParameters{}, status))
+	suite.Require().Equal([]StatusesChannelParameters{{Region: "us"}}, received)
+	suite.Require().Equal([]StatusesChannelParameters{{Region: "us"}, {Region: "eu"}}, receivedAll)
+
+	user.UnsubscribeFromAllSendStatusesOperation(context.Background())
+	err = user.InjectSendStatusesOperation(context.Background(), StatusesChannelParameters{}, status)
+	suite.Require().ErrorIs(err, extensions.ErrNoSubscription)
+}
+
+func (suite *FakeSuite) TestRequestReply() {
+	user := NewFakeUserController()
+
+	// No reply set
+	_, err := user.RequestToPingOperation(context.Background(), NewPingMessage())
+	suite.Require().ErrorIs(err, extensions.ErrNoFakeReply)
+
+	// Reply with the fake app controller
+	app := NewFakeAppController()
+	suite.Require().NoError(orderService{ctrl: app}.Start(context.Background()))
+	user.OnRequestToPingOperation(func(ctx context.Context, msg PingMessage) (PongMessage, error) {
+		if err := app.InjectPingOperation(ctx, msg); err != nil {
+			return PongMessage{}, err
+		}
+		replies := app.SentAsReplyToPingOperation()
+		return replies[len(replies)-1], nil
+	})
+
+	ping := NewPingMessage()
+	ping.Payload = "ping"
+	pong, err := user.RequestToPingOperation(context.Background(), ping)
+	suite.Require().NoError(err)
+	suite.Require().Equal("pong", pong.Payload)
+
+	// The correlation ID has been set on the request and given to the reply
+	sent := user.SentToPingOperation()
+	suite.Require().Len(sent, 2)
+	suite.Require().NotEmpty(sent[1].CorrelationID())
+	suite.Require().Equal(sent[1].CorrelationID(), pong.CorrelationID())
+	suite.Require().Len(user.CallsTo("RequestToPingOperation"), 2)
+}
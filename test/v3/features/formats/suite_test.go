@@ -0,0 +1,182 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -p typed -i ./asyncapi.yaml -o ./typed/asyncapi.gen.go
+//go:generate go run ../../../../cmd/asyncapi-codegen --ignore-string-formats uuid,uri -p ignored -i ./asyncapi.yaml -o ./ignored/asyncapi.gen.go
+
+package formats
+
+import (
+	"context"
+	"encoding/json"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/formats/ignored"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/formats/typed"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	app    *typed.AppController
+	user   *typed.UserController
+
+	events chan typed.EventMessageFromEventsChannel
+	ids    chan typed.IdMessageFromIdsChannel
+	blobs  chan typed.BlobMessageFromBlobsChannel
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) SetupSuite() {
+	// Create app
+	app, err := typed.NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	suite.app = app
+
+	// Create user
+	user, err := typed.NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	suite.user = user
+
+	// Subscribe to operations
+	suite.events = make(chan typed.EventMessageFromEventsChannel, 1)
+	err = suite.app.SubscribeToReceiveEventsOperation(context.Background(),
+		func(_ context.Context, msg typed.EventMessageFromEventsChannel) error {
+			suite.events <- msg
+			return nil
+		})
+	suite.Require().NoError(err)
+
+	suite.ids = make(chan typed.IdMessageFromIdsChannel, 1)
+	err = suite.app.SubscribeToReceiveIdsOperation(context.Background(),
+		func(_ context.Context, msg typed.IdMessageFromIdsChannel) error {
+			suite.ids <- msg
+			return nil
+		})
+	suite.Require().NoError(err)
+
+	suite.blobs = make(chan typed.BlobMessageFromBlobsChannel, 1)
+	err = suite.app.SubscribeToReceiveBlobsOperation(context.Background(),
+		func(_ context.Context, msg typed.BlobMessageFromBlobsChannel) error {
+			suite.blobs <- msg
+			return nil
+		})
+	suite.Require().NoError(err)
+}
+
+func (suite *Suite) TearDownSuite() {
+	suite.app.Close(context.Background())
+	suite.user.Close(context.Background())
+}
+
+func (suite *Suite) TestJSON() {
+	data := `{
+		"id": "8f14e45f-ceea-467f-a8f7-4e1f0ddd4a3b",
+		"data": "aGVsbG8=",
+		"timeout": "PT1M30S",
+		"homepage": "https://example.com/path",
+		"ipv4": "192.168.0.1",
+		"ipv6": "2001:db8::1",
+		"email": "hello@example.com",
+		"related": ["6ba7b810-9dad-11d1-80b4-00c04fd430c8"]
+	}`
+
+	var event typed.EventSchema
+	suite.Require().NoError(json.Unmarshal([]byte(data), &event))
+	suite.Require().Equal(typed.IdSchema(uuid.MustParse("8f14e45f-ceea-467f-a8f7-4e1f0ddd4a3b")), event.Id)
+	suite.Require().Equal([]byte("hello"), *event.Data)
+	suite.Require().Equal(extensions.Duration(90*time.Second), *event.Timeout)
+	suite.Require().Equal("example.com", event.Homepage.Host)
+	suite.Require().Equal(netip.MustParseAddr("192.168.0.1"), *event.Ipv4)
+	suite.Require().Equal(netip.MustParseAddr("2001:db8::1"), *event.Ipv6)
+	suite.Require().Equal("hello@example.com", *event.Email)
+	suite.Require().Equal([]uuid.UUID{uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")}, event.Related)
+
+	b, err := json.Marshal(event)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(data, string(b))
+
+	// Invalid values are rejected
+	suite.Require().Error(json.Unmarshal([]byte(`{"id": "not-an-uuid"}`), &event))
+	suite.Require().Error(json.Unmarshal([]byte(`{"id": "8f14e45f-ceea-467f-a8f7-4e1f0ddd4a3b", "ipv4": "1.2.3"}`), &event))
+	suite.Require().ErrorIs(json.Unmarshal(
+		[]byte(`{"id": "8f14e45f-ceea-467f-a8f7-4e1f0ddd4a3b", "timeout": "90s"}`), &event),
+		extensions.ErrInvalidDuration)
+}
+
+func (suite *Suite) TestDefault() {
+	event := typed.NewEventSchema()
+	suite.Require().Equal(extensions.Duration(30*time.Second), *event.Timeout)
+}
+
+func (suite *Suite) TestIgnoredFormats() {
+	var event ignored.EventSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{"id": "not-an-uuid", "homepage": "whatever", "ipv4": "10.0.0.1"}`), &event))
+	suite.Require().Equal(ignored.IdSchema("not-an-uuid"), event.Id)
+	suite.Require().Equal("whatever", *event.Homepage)
+	suite.Require().Equal(netip.MustParseAddr("10.0.0.1"), *event.Ipv4)
+}
+
+func (suite *Suite) TestCorrelationID() {
+	msg := typed.NewEventMessageFromEventsChannel()
+	suite.Require().NotEqual(uuid.Nil, msg.Headers.RequestId)
+	suite.Require().Equal(msg.Headers.RequestId.String(), msg.CorrelationID())
+
+	// Invalid IDs are ignored
+	id := msg.CorrelationID()
+	msg.SetCorrelationID("not-an-uuid")
+	suite.Require().Equal(id, msg.CorrelationID())
+}
+
+func (suite *Suite) TestEventMessage() {
+	signature := []byte{0x00, 0xff, 0x10}
+	sent := typed.NewEventMessageFromEventsChannel()
+	sent.Headers.Signature = &signature
+	sent.Payload.Id = typed.IdSchema(uuid.New())
+
+	err := suite.user.SendToReceiveEventsOperation(context.Background(), sent)
+	suite.Require().NoError(err)
+
+	received := <-suite.events
+	suite.Require().Equal(sent.Headers, received.Headers)
+	suite.Require().Equal(sent.Payload, received.Payload)
+}
+
+func (suite *Suite) TestIdMessage() {
+	sent := typed.NewIdMessageFromIdsChannel()
+	sent.Payload = typed.IdSchema(uuid.New())
+
+	err := suite.user.SendToReceiveIdsOperation(context.Background(), sent)
+	suite.Require().NoError(err)
+
+	received := <-suite.ids
+	suite.Require().Equal(sent.Payload, received.Payload)
+}
+
+func (suite *Suite) TestBlobMessage() {
+	sent := typed.NewBlobMessageFromBlobsChannel()
+	sent.Payload = []byte{0x00, 0x01, 0xfe, 0xff}
+
+	err := suite.user.SendToReceiveBlobsOperation(context.Background(), sent)
+	suite.Require().NoError(err)
+
+	received := <-suite.blobs
+	suite.Require().Equal(sent.Payload, received.Payload)
+}
@@ -0,0 +1,133 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -p headers -i ./asyncapi.yaml -o ./asyncapi.gen.go
+
+package headers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+// headersRecorder is the headers read by a middleware with the typed accessors.
+type headersRecorder struct {
+	TenantID  TenantIdSchema
+	RequestID uuid.UUID
+	SentAt    time.Time
+	Signature []byte
+	Trace     TracePropertyFromHeadersFromEventMessage
+	Missing   []string
+}
+
+func (r *headersRecorder) middleware(ctx context.Context, msg *extensions.BrokerMessage, next extensions.NextMiddleware) error {
+	var ok bool
+	var err error
+
+	if r.TenantID, ok, err = GetEventMessageTenantIdHeader(*msg); err != nil {
+		return err
+	} else if !ok {
+		r.Missing = append(r.Missing, EventMessageTenantIdHeader)
+	}
+	if r.RequestID, ok, err = GetEventMessageRequestIdHeader(*msg); err != nil {
+		return err
+	} else if !ok {
+		r.Missing = append(r.Missing, EventMessageRequestIdHeader)
+	}
+	if r.SentAt, ok, err = GetEventMessageSentAtHeader(*msg); err != nil {
+		return err
+	} else if !ok {
+		r.Missing = append(r.Missing, EventMessageSentAtHeader)
+	}
+	if r.Signature, ok, err = GetEventMessageSignatureHeader(*msg); err != nil {
+		return err
+	} else if !ok {
+		r.Missing = append(r.Missing, EventMessageSignatureHeader)
+	}
+	if r.Trace, ok, err = GetEventMessageTraceHeader(*msg); err != nil {
+		return err
+	} else if !ok {
+		r.Missing = append(r.Missing, EventMessageTraceHeader)
+	}
+
+	return next(ctx)
+}
+
+func (suite *Suite) TestTypedAccessors() {
+	var recorder headersRecorder
+	app, err := NewAppController(suite.broker, WithReceptionMiddlewares(recorder.middleware))
+	suite.Require().NoError(err)
+	defer app.Close(context.Background())
+
+	user, err := NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	defer user.Close(context.Background())
+
+	sent := NewEventMessage()
+	sent.Headers.TenantId = "acme"
+	sent.Headers.RequestId = utils.ToPointer(uuid.New())
+	sent.Headers.SentAt = utils.ToPointer(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	sent.Headers.Signature = utils.ToPointer([]byte{0xde, 0xad, 0xbe, 0xef})
+	sent.Payload.Name = utils.ToPointer("signup")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err = app.SubscribeToReceiveEventsOperation(context.Background(),
+		func(_ context.Context, msg EventMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Headers, msg.Headers)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer app.UnsubscribeFromReceiveEventsOperation(context.Background())
+
+	suite.Require().NoError(user.SendToReceiveEventsOperation(context.Background(), sent))
+	wg.Wait()
+
+	// Check that the middleware got the same headers, with the same types
+	suite.Require().Equal(sent.Headers.TenantId, recorder.TenantID)
+	suite.Require().Equal(*sent.Headers.RequestId, recorder.RequestID)
+	suite.Require().Equal(*sent.Headers.SentAt, recorder.SentAt)
+	suite.Require().Equal(*sent.Headers.Signature, recorder.Signature)
+	suite.Require().Equal([]string{EventMessageTraceHeader}, recorder.Missing)
+}
+
+func (suite *Suite) TestInvalidHeader() {
+	_, ok, err := GetEventMessageSentAtHeader(extensions.BrokerMessage{
+		Headers: map[string][]byte{EventMessageSentAtHeader: []byte("yesterday")},
+	})
+	suite.Require().True(ok)
+	suite.Require().Error(err)
+
+	trace, ok, err := GetEventMessageTraceHeader(extensions.BrokerMessage{
+		Headers: map[string][]byte{EventMessageTraceHeader: []byte(`{"traceId":"abc","spanId":"def"}`)},
+	})
+	suite.Require().True(ok)
+	suite.Require().NoError(err)
+	suite.Require().Equal("abc", *trace.TraceId)
+}
@@ -0,0 +1,164 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -p structs -i ./asyncapi.yaml -o ./structs/asyncapi.gen.go
+//go:generate go run ../../../../cmd/asyncapi-codegen --strict-additional-properties --plain-maps -p strict -i ./asyncapi.yaml -o ./strict/asyncapi.gen.go
+
+package maps
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/maps/strict"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/maps/structs"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	app    *strict.AppController
+	user   *strict.UserController
+
+	documents chan strict.DocumentMessageFromDocumentsChannel
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) SetupSuite() {
+	// Create app
+	app, err := strict.NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	suite.app = app
+
+	// Create user
+	user, err := strict.NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	suite.user = user
+
+	// Subscribe to operation
+	suite.documents = make(chan strict.DocumentMessageFromDocumentsChannel, 1)
+	err = suite.app.SubscribeToReceiveDocumentsOperation(context.Background(),
+		func(_ context.Context, msg strict.DocumentMessageFromDocumentsChannel) error {
+			suite.documents <- msg
+			return nil
+		})
+	suite.Require().NoError(err)
+}
+
+func (suite *Suite) TearDownSuite() {
+	suite.app.Close(context.Background())
+	suite.user.Close(context.Background())
+}
+
+func (suite *Suite) TestPatternProperties() {
+	data := `{"name": "doc", "x-origin": "test", "n_pages": 12, "tag_draft": true}`
+
+	var labels strict.LabelsSchema
+	suite.Require().NoError(json.Unmarshal([]byte(data), &labels))
+	suite.Require().Equal("doc", *labels.Name)
+	suite.Require().Equal(map[string]string{"x-origin": "test"}, labels.PatternPropertiesX)
+	suite.Require().Equal(map[string]int64{"n_pages": 12}, labels.PatternPropertiesN)
+	suite.Require().Equal(map[string]bool{"tag_draft": true}, labels.Tags)
+
+	b, err := json.Marshal(labels)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(data, string(b))
+
+	// Values should have the type of their pattern
+	suite.Require().Error(json.Unmarshal([]byte(`{"n_pages": "twelve"}`), &labels))
+
+	// Keys should match their pattern
+	labels.Tags["draft"] = true
+	_, err = json.Marshal(labels)
+	suite.Require().ErrorIs(err, extensions.ErrInvalidPropertyKey)
+}
+
+func (suite *Suite) TestForbiddenAdditionalProperties() {
+	// Unknown properties are rejected with strict decoding
+	var point strict.PointSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{"lat": 1.5, "lon": 2.5}`), &point))
+	suite.Require().Equal(strict.PointSchema{Lat: 1.5, Lon: 2.5}, point)
+	suite.Require().ErrorIs(json.Unmarshal([]byte(`{"lat": 1.5, "lon": 2.5, "alt": 3}`), &point),
+		extensions.ErrUnknownProperty)
+
+	var labels strict.LabelsSchema
+	suite.Require().ErrorIs(json.Unmarshal([]byte(`{"unknown": "value"}`), &labels),
+		extensions.ErrUnknownProperty)
+
+	// Unknown properties are ignored otherwise
+	var lenientPoint structs.PointSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{"lat": 1.5, "lon": 2.5, "alt": 3}`), &lenientPoint))
+	suite.Require().Equal(structs.PointSchema{Lat: 1.5, Lon: 2.5}, lenientPoint)
+
+	var lenientLabels structs.LabelsSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{"unknown": "value"}`), &lenientLabels))
+}
+
+func (suite *Suite) TestPlainMaps() {
+	data := `{"a": {"value": "1"}, "b": {"value": "2", "unit": "kg"}}`
+
+	// With plain maps
+	var entries strict.EntriesSchema
+	suite.Require().NoError(json.Unmarshal([]byte(data), &entries))
+	entries.SetDefaults()
+	suite.Require().Equal("piece", *entries["a"].Unit)
+	suite.Require().Equal("kg", *entries["b"].Unit)
+
+	// Without plain maps
+	var structEntries structs.EntriesSchema
+	suite.Require().NoError(json.Unmarshal([]byte(data), &structEntries))
+	structEntries.SetDefaults()
+	suite.Require().Equal("piece", *structEntries.AdditionalProperties["a"].Unit)
+
+	b, err := json.Marshal(structEntries)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(`{"a": {"value": "1", "unit": "piece"}, "b": {"value": "2", "unit": "kg"}}`, string(b))
+}
+
+func (suite *Suite) TestAnyAdditionalProperties() {
+	var extra strict.ExtraPropertyFromDocumentSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{"a": 1, "b": "two"}`), &extra))
+	suite.Require().Equal(strict.ExtraPropertyFromDocumentSchema{"a": float64(1), "b": "two"}, extra)
+
+	var structExtra structs.ExtraPropertyFromDocumentSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{"a": 1, "b": "two"}`), &structExtra))
+	suite.Require().Equal(map[string]any{"a": float64(1), "b": "two"}, structExtra.AdditionalProperties)
+}
+
+func (suite *Suite) TestDocumentMessage() {
+	name := "doc"
+	value := "1"
+	sent := strict.NewDocumentMessageFromDocumentsChannel()
+	sent.Payload.Id = "1234"
+	sent.Payload.Labels = &strict.LabelsSchema{
+		Name: &name,
+		Tags: map[string]bool{"tag_draft": true},
+	}
+	sent.Payload.Entries = &strict.EntriesSchema{"a": {Value: &value}}
+	sent.Payload.Point = &strict.PointSchema{Lat: 1.5, Lon: 2.5}
+
+	err := suite.user.SendToReceiveDocumentsOperation(context.Background(), sent)
+	suite.Require().NoError(err)
+
+	// Defaults are set on the received message
+	received := <-suite.documents
+	suite.Require().Equal("1234", received.Payload.Id)
+	suite.Require().Equal(sent.Payload.Labels.Tags, received.Payload.Labels.Tags)
+	suite.Require().Equal("piece", *(*received.Payload.Entries)["a"].Unit)
+	suite.Require().Equal(sent.Payload.Point, received.Payload.Point)
+}
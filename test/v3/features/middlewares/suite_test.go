@@ -0,0 +1,113 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -p middlewares -i ./asyncapi.yaml -o ./asyncapi.gen.go
+
+package middlewares
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	app    *AppController
+	user   *UserController
+
+	mutex  sync.Mutex
+	called []string
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) recorder(name string) extensions.Middleware {
+	return func(ctx context.Context, _ *extensions.BrokerMessage, next extensions.NextMiddleware) error {
+		suite.mutex.Lock()
+		suite.called = append(suite.called, name)
+		suite.mutex.Unlock()
+		return next(ctx)
+	}
+}
+
+func (suite *Suite) SetupTest() {
+	suite.called = nil
+
+	// Create app
+	app, err := NewAppController(suite.broker,
+		WithMiddlewares(suite.recorder("app")),
+		WithReceptionMiddlewares(suite.recorder("app-reception")),
+		WithPublicationMiddlewares(suite.recorder("app-publication")))
+	suite.Require().NoError(err)
+	suite.app = app
+
+	// Create user
+	user, err := NewUserController(suite.broker,
+		WithMiddlewares(suite.recorder("user")),
+		WithReceptionMiddlewares(suite.recorder("user-reception")),
+		WithPublicationMiddlewares(suite.recorder("user-publication")))
+	suite.Require().NoError(err)
+	suite.user = user
+}
+
+func (suite *Suite) TearDownTest() {
+	suite.app.Close(context.Background())
+	suite.user.Close(context.Background())
+}
+
+func (suite *Suite) TestDirectionAndOperationMiddlewares() {
+	var wg sync.WaitGroup
+	handler := func(_ context.Context, _ EventMessage) error {
+		wg.Done()
+		return nil
+	}
+
+	// Subscribe with an operation middleware on the first channel only
+	err := suite.app.SubscribeToReceiveFirstOperation(context.Background(), handler,
+		WithOperationMiddlewares(suite.recorder("app-first")))
+	suite.Require().NoError(err)
+	err = suite.app.SubscribeToReceiveSecondOperation(context.Background(), handler)
+	suite.Require().NoError(err)
+
+	// Send on the first channel
+	wg.Add(1)
+	msg := EventMessage{Payload: EventMessagePayload{Content: utils.ToPointer("first")}}
+	err = suite.user.SendToReceiveFirstOperation(context.Background(), msg,
+		WithOperationMiddlewares(suite.recorder("user-first")))
+	suite.Require().NoError(err)
+	wg.Wait()
+
+	suite.Require().Equal([]string{
+		"user", "user-publication", "user-first",
+		"app", "app-reception", "app-first",
+	}, suite.called)
+
+	// Send on the second channel
+	suite.called = nil
+	wg.Add(1)
+	err = suite.user.SendToReceiveSecondOperation(context.Background(), msg)
+	suite.Require().NoError(err)
+	wg.Wait()
+
+	suite.Require().Equal([]string{
+		"user", "user-publication",
+		"app", "app-reception",
+	}, suite.called)
+}
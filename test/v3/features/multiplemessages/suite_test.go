@@ -0,0 +1,138 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -p multiplemessages -i ./asyncapi.yaml -o ./asyncapi.gen.go
+
+package multiplemessages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	app    *AppController
+	user   *UserController
+
+	users    chan ReceiveUsersOperationMessage
+	accounts chan ReceiveAccountsOperationMessage
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) SetupSuite() {
+	// Create app
+	app, err := NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	suite.app = app
+
+	// Create user
+	user, err := NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	suite.user = user
+
+	// Subscribe to users operation
+	suite.users = make(chan ReceiveUsersOperationMessage, 1)
+	err = suite.app.SubscribeToReceiveUsersOperation(context.Background(),
+		func(_ context.Context, msg ReceiveUsersOperationMessage) error {
+			suite.users <- msg
+			return nil
+		})
+	suite.Require().NoError(err)
+
+	// Subscribe to accounts operation
+	suite.accounts = make(chan ReceiveAccountsOperationMessage, 1)
+	err = suite.app.SubscribeToReceiveAccountsOperation(context.Background(),
+		func(_ context.Context, msg ReceiveAccountsOperationMessage) error {
+			suite.accounts <- msg
+			return nil
+		})
+	suite.Require().NoError(err)
+}
+
+func (suite *Suite) TearDownSuite() {
+	suite.app.Close(context.Background())
+	suite.user.Close(context.Background())
+}
+
+func (suite *Suite) TestDispatchWithMessageID() {
+	// Send with the generic function
+	var signedUp UserSignedUpMessage
+	signedUp.Payload.Email = utils.ToPointer("user@example.com")
+	suite.Require().NoError(suite.user.SendToReceiveUsersOperation(context.Background(), signedUp))
+
+	switch msg := (<-suite.users).(type) {
+	case UserSignedUpMessage:
+		suite.Require().Equal(signedUp, msg)
+	default:
+		suite.Require().Failf("unexpected message", "%T", msg)
+	}
+
+	// Send with the dedicated function
+	var deleted UserDeletedMessage
+	deleted.Payload.Reason = utils.ToPointer("inactive")
+	suite.Require().NoError(suite.user.SendToReceiveUsersOperationWithUserDeletedMessage(context.Background(), deleted))
+
+	switch msg := (<-suite.users).(type) {
+	case UserDeletedMessage:
+		suite.Require().Equal(deleted, msg)
+	default:
+		suite.Require().Failf("unexpected message", "%T", msg)
+	}
+}
+
+func (suite *Suite) TestDispatchWithContentType() {
+	// Send a message without message ID
+	err := suite.broker.Publish(context.Background(), UsersChannelPath, extensions.BrokerMessage{
+		Headers: map[string][]byte{extensions.ContentTypeHeader: []byte("application/vnd.user-deleted+json")},
+		Payload: []byte(`{"reason":"inactive"}`),
+	})
+	suite.Require().NoError(err)
+
+	msg, ok := (<-suite.users).(UserDeletedMessage)
+	suite.Require().True(ok)
+	suite.Require().Equal("inactive", *msg.Payload.Reason)
+}
+
+func (suite *Suite) TestDispatchWithContentTypeParameters() {
+	// Send a message without message ID, with content type parameters
+	err := suite.broker.Publish(context.Background(), UsersChannelPath, extensions.BrokerMessage{
+		Headers: map[string][]byte{extensions.ContentTypeHeader: []byte("Application/vnd.user-deleted+json; charset=utf-8")},
+		Payload: []byte(`{"reason":"inactive"}`),
+	})
+	suite.Require().NoError(err)
+
+	msg, ok := (<-suite.users).(UserDeletedMessage)
+	suite.Require().True(ok)
+	suite.Require().Equal("inactive", *msg.Payload.Reason)
+}
+
+func (suite *Suite) TestDispatchWithDiscriminatorHeader() {
+	// Send a message without message ID
+	err := suite.broker.Publish(context.Background(), AccountsChannelPath, extensions.BrokerMessage{
+		Headers: map[string][]byte{"eventType": []byte("closed")},
+		Payload: []byte(`{"id":"1234"}`),
+	})
+	suite.Require().NoError(err)
+
+	msg, ok := (<-suite.accounts).(AccountClosedMessage)
+	suite.Require().True(ok)
+	suite.Require().Equal("1234", *msg.Payload.Id)
+}
@@ -0,0 +1,159 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen --use-nullable -p wrapper -i ./asyncapi.yaml -o ./wrapper/asyncapi.gen.go
+//go:generate go run ../../../../cmd/asyncapi-codegen -p pointers -i ./asyncapi.yaml -o ./pointers/asyncapi.gen.go
+
+package nullable
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/nullable/pointers"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/nullable/wrapper"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	app    *wrapper.AppController
+	user   *wrapper.UserController
+
+	patches chan wrapper.PatchMessageFromPatchesChannel
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) SetupSuite() {
+	// Create app
+	app, err := wrapper.NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	suite.app = app
+
+	// Create user
+	user, err := wrapper.NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	suite.user = user
+
+	// Subscribe to operation
+	suite.patches = make(chan wrapper.PatchMessageFromPatchesChannel, 1)
+	err = suite.app.SubscribeToReceivePatchesOperation(context.Background(),
+		func(_ context.Context, msg wrapper.PatchMessageFromPatchesChannel) error {
+			suite.patches <- msg
+			return nil
+		})
+	suite.Require().NoError(err)
+}
+
+func (suite *Suite) TearDownSuite() {
+	suite.app.Close(context.Background())
+	suite.user.Close(context.Background())
+}
+
+func (suite *Suite) TestJSON() {
+	var patch wrapper.ProfilePatchSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{
+		"id": "1234",
+		"name": null,
+		"nickname": "bob",
+		"address": {"street": "Main street"}
+	}`), &patch))
+
+	// Explicit null, set and absent fields can be distinguished
+	suite.Require().True(patch.Name.IsNull())
+	suite.Require().Equal(extensions.NewNullable("bob"), patch.Nickname)
+	suite.Require().False(patch.Age.IsPresent())
+
+	// Defaults are applied on nested objects
+	patch.SetDefaults()
+	address, ok := patch.Address.Get()
+	suite.Require().True(ok)
+	suite.Require().Equal("Paris", address.City.ValueOr(""))
+
+	// Absent fields are omitted but null fields are kept
+	b, err := json.Marshal(patch)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(`{
+		"id": "1234",
+		"name": null,
+		"nickname": "bob",
+		"address": {"city": "Paris", "street": "Main street"}
+	}`, string(b))
+}
+
+func (suite *Suite) TestAdditionalProperties() {
+	var settings wrapper.SettingsSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{"lang": "fr"}`), &settings))
+	suite.Require().False(settings.Theme.IsPresent())
+	suite.Require().Equal(map[string]string{"lang": "fr"}, settings.AdditionalProperties)
+
+	b, err := json.Marshal(settings)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(`{"lang": "fr"}`, string(b))
+
+	settings.Theme.SetNull()
+	b, err = json.Marshal(settings)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(`{"theme": null, "lang": "fr"}`, string(b))
+}
+
+func (suite *Suite) TestDefaults() {
+	patch := wrapper.NewProfilePatchSchema()
+	suite.Require().False(patch.Address.IsPresent())
+
+	msg := wrapper.NewPatchMessageFromPatchesChannel()
+	suite.Require().Equal(extensions.NewNullable("v1"), msg.Headers.Version)
+	suite.Require().False(msg.Headers.Source.IsPresent())
+}
+
+func (suite *Suite) TestCorrelationID() {
+	msg := wrapper.NewPatchMessageFromPatchesChannel()
+	suite.Require().NotEmpty(msg.CorrelationID())
+
+	msg.SetCorrelationID("my-id")
+	suite.Require().Equal(extensions.NewNullable("my-id"), msg.Headers.CorrelationId)
+	suite.Require().Equal("my-id", msg.CorrelationID())
+
+	msg.Headers.CorrelationId.SetNull()
+	suite.Require().Equal("", msg.CorrelationID())
+}
+
+func (suite *Suite) TestPatchMessage() {
+	sent := wrapper.NewPatchMessageFromPatchesChannel()
+	sent.Headers.Source.Set("test")
+	sent.Payload.Id = "1234"
+	sent.Payload.Name.SetNull()
+	sent.Payload.Nickname.Set("bob")
+	sent.Payload.Age.Set(42)
+
+	err := suite.user.SendToReceivePatchesOperation(context.Background(), sent)
+	suite.Require().NoError(err)
+
+	received := <-suite.patches
+	suite.Require().Equal(sent.Headers, received.Headers)
+	suite.Require().Equal(sent.Payload, received.Payload)
+}
+
+func (suite *Suite) TestPointers() {
+	var patch pointers.ProfilePatchSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{"id": "1234", "nickname": null}`), &patch))
+
+	// Without the wrapper, a required nullable field is a pointer
+	suite.Require().Nil(patch.Nickname)
+	suite.Require().Nil(patch.Name)
+}
@@ -0,0 +1,100 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -g types --split-files --types-package github.com/lerenn/asyncapi-codegen/test/v3/features/packages/types -p types -i ./asyncapi.yaml -o ./types/asyncapi.gen.go
+//go:generate go run ../../../../cmd/asyncapi-codegen -g application --split-files --types-package github.com/lerenn/asyncapi-codegen/test/v3/features/packages/types -p app -i ./asyncapi.yaml -o ./app/asyncapi.gen.go
+//go:generate go run ../../../../cmd/asyncapi-codegen -g user --split-files --types-package github.com/lerenn/asyncapi-codegen/test/v3/features/packages/types -p user -i ./asyncapi.yaml -o ./user/asyncapi.gen.go
+
+package packages
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/packages/app"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/packages/types"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/packages/user"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	app    *app.AppController
+	user   *user.UserController
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) SetupTest() {
+	var err error
+
+	// Create app, from its own package
+	suite.app, err = app.NewAppController(suite.broker)
+	suite.Require().NoError(err)
+
+	// Create user, from its own package
+	suite.user, err = user.NewUserController(suite.broker)
+	suite.Require().NoError(err)
+}
+
+func (suite *Suite) TearDownTest() {
+	suite.app.Close(context.Background())
+	suite.user.Close(context.Background())
+}
+
+func (suite *Suite) TestSendReceive() {
+	params := types.OrdersChannelParameters{Region: "us"}
+
+	// Set the message, from the types package shared by the app and user
+	sent := types.NewOrderMessage()
+	sent.Headers.Source = utils.ToPointer("shop")
+	sent.Payload.Id = "order-1"
+	sent.Payload.Quantity = utils.ToPointer(int64(2))
+
+	// Check what the app receives
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err := suite.app.SubscribeToReceiveOrdersOperation(context.Background(), params,
+		func(_ context.Context, msg types.OrderMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(sent, msg)
+			return nil
+		})
+	suite.Require().NoError(err)
+
+	// Send the message from the user
+	suite.Require().NoError(suite.user.SendToReceiveOrdersOperation(context.Background(), params, sent))
+	wg.Wait()
+}
+
+func (suite *Suite) TestRequestReply() {
+	// Reply to the requests on the app
+	err := suite.app.SubscribeToPingOperation(context.Background(), func(ctx context.Context, msg types.PingMessage) error {
+		return suite.app.ReplyToPingOperation(ctx, msg, func(replyMsg *types.PongMessage) {
+			replyMsg.Payload = msg.Payload + " pong"
+		})
+	})
+	suite.Require().NoError(err)
+
+	// Make the request from the user
+	ping := types.NewPingMessage()
+	ping.Payload = "ping"
+	pong, err := suite.user.RequestToPingOperation(context.Background(), ping)
+	suite.Require().NoError(err)
+	suite.Require().Equal("ping pong", pong.Payload)
+}
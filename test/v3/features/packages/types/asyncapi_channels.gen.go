@@ -0,0 +1,69 @@
+// Package "types" provides primitives to interact with the AsyncAPI specification.
+//
+// Code generated by github.com/lerenn/asyncapi-codegen version (devel) DO NOT EDIT.
+package types
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+)
+
+// OrdersChannelParameters represents OrdersChannel channel parameters
+type OrdersChannelParameters struct {
+	// Region is a channel parameter.
+	Region string
+}
+
+// SetDefaults sets the default values of the empty OrdersChannel channel parameters.
+func (p *OrdersChannelParameters) SetDefaults() {
+	if p.Region == "" {
+		p.Region = "eu"
+	}
+}
+
+// Validate checks that the OrdersChannel channel parameters are
+// one of their possible values, if any.
+func (p OrdersChannelParameters) Validate() error {
+	switch p.Region {
+	case "eu", "us":
+	default:
+		return fmt.Errorf("%w: %q is not a possible value of region (expected one of %q)",
+			extensions.ErrInvalidChannelParameter, p.Region, []string{"eu", "us"})
+	}
+	return nil
+}
+
+var regexpOrdersChannelParametersAddress = regexp.MustCompile("^v3\\.features\\.packages\\.(.*?)\\.orders$")
+
+// OrdersChannelParametersFromAddress extracts the OrdersChannel
+// channel parameters from an address of the channel.
+func OrdersChannelParametersFromAddress(addr string) (OrdersChannelParameters, error) {
+	matches := regexpOrdersChannelParametersAddress.FindStringSubmatch(addr)
+	if matches == nil {
+		return OrdersChannelParameters{}, fmt.Errorf("%w: address %q doesn't match %q",
+			extensions.ErrInvalidChannelParameter, addr, "v3.features.packages.{region}.orders")
+	}
+
+	values := matches[1:]
+	return OrdersChannelParameters{
+		Region: values[0],
+	}, nil
+}
+
+const (
+	// OrdersChannelPath is the constant representing the 'OrdersChannel' channel path.
+	OrdersChannelPath = "v3.features.packages.{region}.orders"
+	// PingChannelPath is the constant representing the 'PingChannel' channel path.
+	PingChannelPath = "v3.features.packages.ping"
+	// PongChannelPath is the constant representing the 'PongChannel' channel path.
+	PongChannelPath = "v3.features.packages.pong"
+)
+
+// ChannelsPaths is an array of all channels paths
+var ChannelsPaths = []string{
+	OrdersChannelPath,
+	PingChannelPath,
+	PongChannelPath,
+}
@@ -0,0 +1,13 @@
+// Package "types" provides primitives to interact with the AsyncAPI specification.
+//
+// Code generated by github.com/lerenn/asyncapi-codegen version (devel) DO NOT EDIT.
+package types
+
+// AsyncAPIVersion is the version of the used AsyncAPI document
+const AsyncAPIVersion = ""
+
+// OrderSchema is a schema from the AsyncAPI specification required in messages
+type OrderSchema struct {
+	Id       string `json:"id"`
+	Quantity *int64 `json:"quantity,omitempty"`
+}
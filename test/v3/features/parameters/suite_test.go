@@ -0,0 +1,188 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen --escape-channel-parameters -p parameters -i ./asyncapi.yaml -o ./asyncapi.gen.go
+
+package parameters
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions/brokers/kafka"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	app    *AppController
+	user   *UserController
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) SetupTest() {
+	app, err := NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	suite.app = app
+
+	user, err := NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	suite.user = user
+}
+
+func (suite *Suite) TearDownTest() {
+	suite.app.Close(context.Background())
+	suite.user.Close(context.Background())
+}
+
+func (suite *Suite) TestDefaultsAndContext() {
+	sent := NewUserMessageFromUsersChannel()
+	sent.Payload.Name = utils.ToPointer("bob")
+
+	// The region is not set, so the default one should be used
+	params := UsersChannelParameters{UserId: "bob"}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err := suite.app.SubscribeToReceiveUsersOperation(context.Background(), params,
+		func(ctx context.Context, msg UserMessageFromUsersChannel) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+
+			// Check the address and the parameters extracted from it
+			suite.Require().Equal("v3.features.parameters.eu.users.bob", ctx.Value(extensions.ContextKeyIsChannel))
+			suite.Require().Equal(UsersChannelParameters{Region: "eu", UserId: "bob"},
+				ctx.Value(extensions.ContextKeyIsChannelParameters))
+
+			// Check the metadata given by the broker
+			metadata, ok := extensions.BrokerMessageMetadataFromContext(ctx)
+			suite.Require().True(ok)
+			suite.Require().Equal("v3.features.parameters.eu.users.bob", metadata.Channel)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer suite.app.UnsubscribeFromReceiveUsersOperation(context.Background(), params)
+
+	suite.Require().NoError(suite.user.SendToReceiveUsersOperation(context.Background(),
+		UsersChannelParameters{Region: "eu", UserId: "bob"}, sent))
+	wg.Wait()
+}
+
+func (suite *Suite) TestEscaping() {
+	sent := NewUserMessageFromUsersChannel()
+	sent.Payload.Name = utils.ToPointer("alice")
+
+	// The user ID contains separators and wildcards that should be escaped
+	params := UsersChannelParameters{Region: "us", UserId: "alice.smith/*>"}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err := suite.app.SubscribeToReceiveUsersOperation(context.Background(), params,
+		func(ctx context.Context, msg UserMessageFromUsersChannel) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+
+			suite.Require().Equal("v3.features.parameters.us.users.alice_2Esmith_2F_2A_3E",
+				ctx.Value(extensions.ContextKeyIsChannel))
+			suite.Require().Equal(params, ctx.Value(extensions.ContextKeyIsChannelParameters))
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer suite.app.UnsubscribeFromReceiveUsersOperation(context.Background(), params)
+
+	suite.Require().NoError(suite.user.SendToReceiveUsersOperation(context.Background(), params, sent))
+	wg.Wait()
+}
+
+func (suite *Suite) TestValidation() {
+	params := UsersChannelParameters{Region: "asia", UserId: "carol"}
+	suite.Require().ErrorIs(params.Validate(), extensions.ErrInvalidChannelParameter)
+
+	err := suite.app.SubscribeToReceiveUsersOperation(context.Background(), params,
+		func(_ context.Context, _ UserMessageFromUsersChannel) error {
+			return nil
+		})
+	suite.Require().ErrorIs(err, extensions.ErrInvalidChannelParameter)
+
+	err = suite.user.SendToReceiveUsersOperation(context.Background(), params, NewUserMessageFromUsersChannel())
+	suite.Require().ErrorIs(err, extensions.ErrInvalidChannelParameter)
+}
+
+func (suite *Suite) TestParametersFromAddress() {
+	params, err := UsersChannelParametersFromAddress("v3.features.parameters.us.users.dave_2Ejones")
+	suite.Require().NoError(err)
+	suite.Require().Equal(UsersChannelParameters{Region: "us", UserId: "dave.jones"}, params)
+
+	// The addresses built without escaping are also accepted
+	params, err = UsersChannelParametersFromAddress("v3.features.parameters.eu.users.tenant_01.west")
+	suite.Require().NoError(err)
+	suite.Require().Equal(UsersChannelParameters{Region: "eu", UserId: "tenant_01.west"}, params)
+
+	_, err = UsersChannelParametersFromAddress("v3.features.other.us.users.dave")
+	suite.Require().ErrorIs(err, extensions.ErrInvalidChannelParameter)
+}
+
+func (suite *Suite) TestWildcards() {
+	sent := map[WildcardsChannelParameters]UserMessageFromWildcardsChannel{}
+	for _, params := range []WildcardsChannelParameters{
+		{Region: "eu", UserId: "erin"},
+		{Region: "us", UserId: "frank.miller"},
+	} {
+		msg := NewUserMessageFromWildcardsChannel()
+		msg.Payload.Name = utils.ToPointer(params.UserId)
+		sent[params] = msg
+
+		// Create the topics beforehand, as Kafka only subscribes to existing topics
+		if _, isKafka := suite.broker.(*kafka.Controller); isKafka {
+			sub, err := suite.broker.Subscribe(context.Background(), "v3.features.parameters.wildcards."+
+				extensions.EscapeChannelParameter(params.Region)+"."+extensions.EscapeChannelParameter(params.UserId))
+			suite.Require().NoError(err)
+			sub.Cancel(context.Background())
+		}
+	}
+
+	var mutex sync.Mutex
+	received := map[WildcardsChannelParameters]UserMessageFromWildcardsChannel{}
+
+	var wg sync.WaitGroup
+	wg.Add(len(sent))
+	err := suite.app.SubscribeToAllReceiveWildcardsOperation(context.Background(),
+		func(ctx context.Context, params WildcardsChannelParameters, msg UserMessageFromWildcardsChannel) error {
+			defer wg.Done()
+
+			// The context should have the address on which the message has been received
+			suite.Require().Equal("v3.features.parameters.wildcards."+
+				extensions.EscapeChannelParameter(params.Region)+"."+extensions.EscapeChannelParameter(params.UserId),
+				ctx.Value(extensions.ContextKeyIsChannel))
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			received[params] = msg
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer suite.app.UnsubscribeFromAllReceiveWildcardsOperation(context.Background())
+
+	for params, msg := range sent {
+		suite.Require().NoError(suite.user.SendToReceiveWildcardsOperation(context.Background(), params, msg))
+	}
+	wg.Wait()
+
+	suite.Require().Equal(sent, received)
+}
@@ -0,0 +1,172 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -p generated -i ./asyncapi.yaml -o ./generated/asyncapi.gen.go
+//go:generate go run ../../../../cmd/asyncapi-codegen --protobuf-go-types -p referenced -i ./asyncapi.yaml -o ./referenced/asyncapi.gen.go
+
+package protobuf
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/protobuf/generated"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/protobuf/pb"
+	"github.com/lerenn/asyncapi-codegen/test/v3/features/protobuf/referenced"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) TestGenerated() {
+	var received extensions.BrokerMessage
+	app, err := generated.NewAppController(suite.broker, generated.WithReceptionMiddlewares(testutil.Recorder(&received)))
+	suite.Require().NoError(err)
+	defer app.Close(context.Background())
+
+	user, err := generated.NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	defer user.Close(context.Background())
+
+	sent := generated.NewUserMessage()
+	sent.Payload = generated.UserMessagePayload{
+		Id:     "1234",
+		Name:   "bob",
+		Age:    42,
+		Email:  utils.ToPointer("bob@example.com"),
+		Status: generated.StatusPropertyFromUserMessagePayloadBANNED,
+		Tags:   []string{"a", "b"},
+		Scores: &generated.ScoresPropertyFromUserMessagePayload{
+			AdditionalProperties: map[string]int64{"chess": 1500},
+		},
+		Address: &generated.AddressPropertyFromUserMessagePayload{
+			Street: "1 main street",
+			City:   "Paris",
+		},
+		Phone:     utils.ToPointer("+33123456789"),
+		CreatedAt: utils.ToPointer(time.UnixMilli(1700000000123).UTC()),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err = app.SubscribeToReceiveGeneratedUsersOperation(context.Background(),
+		func(_ context.Context, msg generated.UserMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(sent.Payload, msg.Payload)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer app.UnsubscribeFromReceiveGeneratedUsersOperation(context.Background())
+
+	suite.Require().NoError(user.SendToReceiveGeneratedUsersOperation(context.Background(), sent))
+	wg.Wait()
+
+	// Check that the payload is encoded with the protobuf binary encoding,
+	// starting with the tag of the ID field (1, length-delimited) and its length
+	suite.Require().Equal([]byte{0x0a, 4, '1', '2', '3', '4'}, received.Payload[:6])
+	suite.Require().Equal([]byte(extensions.ProtobufContentType), received.Headers[extensions.ContentTypeHeader])
+}
+
+func (suite *Suite) TestReferenced() {
+	app, err := referenced.NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	defer app.Close(context.Background())
+
+	user, err := referenced.NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	defer user.Close(context.Background())
+
+	// Use the types generated by protoc-gen-go, with a oneof
+	sent := referenced.NewUserMessage()
+	sent.Payload = &pb.User{
+		Id:        "1234",
+		Name:      "bob",
+		Age:       42,
+		Status:    pb.User_BANNED,
+		Tags:      []string{"a", "b"},
+		Scores:    map[string]int64{"chess": 1500},
+		Address:   &pb.Address{City: "Paris"},
+		Contact:   &pb.User_Postal{Postal: &pb.Address{Street: "1 main street", City: "Paris"}},
+		CreatedAt: timestamppb.New(time.UnixMilli(1700000000123)),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err = app.SubscribeToReceiveReferencedUsersOperation(context.Background(),
+		func(_ context.Context, msg referenced.UserMessage) error {
+			defer wg.Done()
+			suite.Require().True(proto.Equal(sent.Payload, msg.Payload), "got %v", msg.Payload)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer app.UnsubscribeFromReceiveReferencedUsersOperation(context.Background())
+
+	suite.Require().NoError(user.SendToReceiveReferencedUsersOperation(context.Background(), sent))
+	wg.Wait()
+}
+
+func (suite *Suite) TestInteroperability() {
+	// Receive the messages from the types generated by protoc-gen-go
+	app, err := generated.NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	defer app.Close(context.Background())
+
+	user, err := referenced.NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	defer user.Close(context.Background())
+
+	sent := referenced.NewUserMessage()
+	sent.Payload = &pb.User{
+		Id:        "1234",
+		Email:     proto.String("bob@example.com"),
+		Status:    pb.User_ACTIVE,
+		Scores:    map[string]int64{"chess": 1500},
+		Contact:   &pb.User_Phone{Phone: "+33123456789"},
+		CreatedAt: timestamppb.New(time.UnixMilli(1700000000123)),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err = app.SubscribeToReceiveInteroperableUsersOperation(context.Background(),
+		func(_ context.Context, msg generated.UserMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(generated.UserMessagePayload{
+				Id:     "1234",
+				Email:  utils.ToPointer("bob@example.com"),
+				Status: generated.StatusPropertyFromUserMessagePayloadACTIVE,
+				Scores: &generated.ScoresPropertyFromUserMessagePayload{
+					AdditionalProperties: map[string]int64{"chess": 1500},
+				},
+				Phone:     utils.ToPointer("+33123456789"),
+				CreatedAt: utils.ToPointer(time.UnixMilli(1700000000123).UTC()),
+			}, msg.Payload)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer app.UnsubscribeFromReceiveInteroperableUsersOperation(context.Background())
+
+	suite.Require().NoError(user.SendToReceiveInteroperableUsersOperation(context.Background(), sent))
+	wg.Wait()
+}
@@ -0,0 +1,165 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen --random-messages -p random -i ./asyncapi.yaml -o ./asyncapi.gen.go
+
+package random
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) TestReproducible() {
+	suite.Require().Equal(
+		RandomOrderMessage(rand.New(rand.NewSource(42))),
+		RandomOrderMessage(rand.New(rand.NewSource(42))))
+	suite.Require().NotEqual(
+		RandomOrderMessage(rand.New(rand.NewSource(42))),
+		RandomOrderMessage(rand.New(rand.NewSource(43))))
+
+	suite.Require().Equal(RandomOrderMessagePayloads(42, 5), RandomOrderMessagePayloads(42, 5))
+	suite.Require().Len(RandomOrderMessagePayloads(42, 5), 5)
+}
+
+func (suite *Suite) TestValid() {
+	validate := validator.New()
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		msg := RandomOrderMessage(r)
+		suite.Require().NoError(validate.Struct(msg.Headers))
+		suite.Require().NoError(validate.Struct(msg.Payload))
+
+		// Check the constraints that are not covered by the validations
+		suite.Require().Regexp(`^[a-z]{3}-[0-9]{2}$`, msg.Headers.TenantId)
+		suite.Require().NotNil(msg.Headers.CorrelationId)
+		suite.Require().Equal("order", msg.Payload.Kind)
+		if msg.Payload.Reference != nil {
+			suite.Require().Regexp(`^ORD-[A-Z]{2}[0-9]{4}$`, *msg.Payload.Reference)
+		}
+		suite.Require().True(len(msg.Payload.Items) >= 1 && len(msg.Payload.Items) <= 5)
+		for _, item := range msg.Payload.Items {
+			if item.Adjustment != nil {
+				suite.Require().LessOrEqual(*item.Adjustment, int64(0))
+			}
+		}
+		if msg.Payload.Total != nil {
+			suite.Require().Greater(*msg.Payload.Total, 0.0)
+		}
+		if msg.Payload.Tags != nil {
+			suite.Require().True(len(msg.Payload.Tags) >= 2 && len(msg.Payload.Tags) <= 3)
+		}
+		if msg.Payload.Discount != nil {
+			suite.Require().True(*msg.Payload.Discount >= 0.5 && *msg.Payload.Discount < 1)
+		}
+		if msg.Payload.Payment != nil {
+			if card, ok := msg.Payload.Payment.AsCard(); ok {
+				suite.Require().Equal("Card", card.Method)
+				suite.Require().Regexp(regexp.MustCompile(`^\d{4}$`), card.Last4)
+			} else {
+				suite.Require().Equal("Transfer", msg.Payload.Payment.Transfer.Method)
+			}
+		}
+
+		// Check that the message is the same after being converted to a
+		// broker message and back
+		bMsg, err := msg.toBrokerMessage()
+		suite.Require().NoError(err)
+		received, err := brokerMessageToOrderMessage(bMsg)
+		suite.Require().NoError(err)
+		suite.Require().Equal(msg, received)
+	}
+
+	for i := 0; i < 50; i++ {
+		msg := RandomCommentMessageFromCommentsChannel(r)
+		suite.Require().True(len(msg.Payload) >= 3 && len(msg.Payload) <= 10, msg.Payload)
+	}
+}
+
+func (suite *Suite) TestSendRandom() {
+	app, err := NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	defer app.Close(context.Background())
+
+	user, err := NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	defer user.Close(context.Background())
+
+	r := rand.New(rand.NewSource(1))
+	sent := make([]OrderMessage, 0, 3)
+	for i := 0; i < 3; i++ {
+		sent = append(sent, RandomOrderMessage(r))
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(sent))
+	received := make([]OrderMessage, 0, len(sent))
+	err = app.SubscribeToReceiveOrdersOperation(context.Background(),
+		func(_ context.Context, msg OrderMessage) error {
+			defer wg.Done()
+			received = append(received, msg)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer app.UnsubscribeFromReceiveOrdersOperation(context.Background())
+
+	for _, msg := range sent {
+		suite.Require().NoError(user.SendToReceiveOrdersOperation(context.Background(), msg))
+	}
+	wg.Wait()
+
+	suite.Require().ElementsMatch(sent, received)
+}
+
+func FuzzOrderMessage(f *testing.F) {
+	for _, payload := range RandomOrderMessagePayloads(1, 20) {
+		f.Add(payload)
+	}
+
+	validate := validator.New()
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		// A valid payload should be the same after being encoded and decoded again
+		msg, err := brokerMessageToOrderMessage(extensions.BrokerMessage{Payload: payload})
+		if err != nil || validate.Struct(msg.Payload) != nil {
+			return
+		}
+		bMsg, err := msg.toBrokerMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := brokerMessageToOrderMessage(bMsg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(msg, again) {
+			t.Fatalf("got %+v after encoding and decoding again, expected %+v", again, msg)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen --trait-mixins -p traits -i ./asyncapi.yaml -o ./asyncapi.gen.go
+
+package traits
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+// tenantRecorder records the tenants of all the received messages, whatever
+// their type, with the headers of the 'Tenant' trait.
+type tenantRecorder struct {
+	mutex   sync.Mutex
+	tenants []HeadersFromTenantTrait
+}
+
+func (r *tenantRecorder) middleware(ctx context.Context, msg *extensions.BrokerMessage, next extensions.NextMiddleware) error {
+	h, err := GetHeadersFromTenantTrait(*msg)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	r.tenants = append(r.tenants, h)
+	r.mutex.Unlock()
+
+	return next(ctx)
+}
+
+func (suite *Suite) TestSharedTypes() {
+	order, invoice := NewOrderMessage(), NewInvoiceMessage()
+
+	// The trait headers are the same type in both messages
+	tenant := NewHeadersFromTenantTrait()
+	tenant.TenantId = "acme"
+	order.Headers.HeadersFromTenantTrait = tenant
+	invoice.Headers.HeadersFromTenantTrait = tenant
+	suite.Require().Equal(order.Headers.HeadersFromTenantTrait, invoice.Headers.HeadersFromTenantTrait)
+
+	// The trait fields are promoted, and have their default values
+	suite.Require().Equal("acme", invoice.Headers.TenantId)
+	suite.Require().Equal(utils.ToPointer("eu"), NewInvoiceMessage().Headers.Region)
+	suite.Require().Equal(utils.ToPointer("eu"), NewOrderMessage().Headers.Region)
+}
+
+func (suite *Suite) TestRoundTrip() {
+	var recorder tenantRecorder
+	app, err := NewAppController(suite.broker, WithReceptionMiddlewares(recorder.middleware))
+	suite.Require().NoError(err)
+	defer app.Close(context.Background())
+
+	user, err := NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	defer user.Close(context.Background())
+
+	order := NewOrderMessage()
+	order.Headers.TenantId = "acme"
+	order.Headers.TraceId = utils.ToPointer(uuid.New())
+	order.Headers.Span = &SpanPropertyFromHeadersFromTracedTrait{Id: utils.ToPointer("span")}
+	order.Headers.Priority = utils.ToPointer("high")
+	order.Payload.Id = utils.ToPointer("order-1")
+
+	invoice := NewInvoiceMessage()
+	invoice.Headers.TenantId = "globex"
+	invoice.Headers.Region = utils.ToPointer("us")
+	invoice.Payload.Amount = utils.ToPointer(42.5)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	err = app.SubscribeToReceiveOrdersOperation(context.Background(),
+		func(_ context.Context, msg OrderMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(order, msg)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer app.UnsubscribeFromReceiveOrdersOperation(context.Background())
+
+	err = app.SubscribeToReceiveInvoicesOperation(context.Background(),
+		func(_ context.Context, msg InvoiceMessage) error {
+			defer wg.Done()
+			suite.Require().Equal(invoice, msg)
+			return nil
+		})
+	suite.Require().NoError(err)
+	defer app.UnsubscribeFromReceiveInvoicesOperation(context.Background())
+
+	suite.Require().NoError(user.SendToReceiveOrdersOperation(context.Background(), order))
+	suite.Require().NoError(user.SendToReceiveInvoicesOperation(context.Background(), invoice))
+	wg.Wait()
+
+	// The middleware got the tenants of both messages with the trait type
+	suite.Require().ElementsMatch([]HeadersFromTenantTrait{
+		order.Headers.HeadersFromTenantTrait,
+		invoice.Headers.HeadersFromTenantTrait,
+	}, recorder.tenants)
+}
+
+func (suite *Suite) TestInvalidTraitHeaders() {
+	_, err := GetHeadersFromTracedTrait(extensions.BrokerMessage{
+		Headers: map[string][]byte{"traceId": []byte("not-a-uuid")},
+	})
+	suite.Require().Error(err)
+}
@@ -0,0 +1,152 @@
+//go:generate go run ../../../../cmd/asyncapi-codegen -p unions -i ./asyncapi.yaml -o ./asyncapi.gen.go
+
+package unions
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+	"github.com/lerenn/asyncapi-codegen/pkg/utils"
+	testutil "github.com/lerenn/asyncapi-codegen/test"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuite(t *testing.T) {
+	brokers, cleanup := testutil.BrokerControllers(t)
+	defer cleanup()
+
+	for _, b := range brokers {
+		suite.Run(t, NewSuite(b))
+	}
+}
+
+type Suite struct {
+	broker extensions.BrokerController
+	app    *AppController
+	user   *UserController
+
+	pets chan PetMessageFromPetsChannel
+	suite.Suite
+}
+
+func NewSuite(broker extensions.BrokerController) *Suite {
+	return &Suite{
+		broker: broker,
+	}
+}
+
+func (suite *Suite) SetupSuite() {
+	// Create app
+	app, err := NewAppController(suite.broker)
+	suite.Require().NoError(err)
+	suite.app = app
+
+	// Create user
+	user, err := NewUserController(suite.broker)
+	suite.Require().NoError(err)
+	suite.user = user
+
+	// Subscribe to pets operation
+	suite.pets = make(chan PetMessageFromPetsChannel, 1)
+	err = suite.app.SubscribeToReceivePetsOperation(context.Background(),
+		func(_ context.Context, msg PetMessageFromPetsChannel) error {
+			suite.pets <- msg
+			return nil
+		})
+	suite.Require().NoError(err)
+}
+
+func (suite *Suite) TearDownSuite() {
+	suite.app.Close(context.Background())
+	suite.user.Close(context.Background())
+}
+
+func (suite *Suite) TestDiscriminatorRoundTrip() {
+	// Send a dog
+	var msg PetMessageFromPetsChannel
+	msg.Payload = NewPetSchemaWithDog(DogSchema{
+		PetType: utils.ToPointer("dog"),
+		Name:    utils.ToPointer("Rex"),
+		Breed:   utils.ToPointer("Labrador"),
+	})
+	suite.Require().NoError(suite.user.SendToReceivePetsOperation(context.Background(), msg))
+
+	// Check that the dog has been received as a dog
+	received := <-suite.pets
+	suite.Require().Equal(msg, received)
+
+	dog, ok := received.Payload.AsDog()
+	suite.Require().True(ok)
+	suite.Require().Equal("Rex", *dog.Name)
+
+	_, ok = received.Payload.AsCat()
+	suite.Require().False(ok)
+}
+
+func (suite *Suite) TestDiscriminatorUnknownValue() {
+	var pet PetSchema
+	err := json.Unmarshal([]byte(`{"petType":"bird","name":"Tweety"}`), &pet)
+	suite.Require().ErrorIs(err, extensions.ErrUnknownVariant)
+}
+
+func (suite *Suite) TestFallbackWithoutDiscriminator() {
+	var shape ShapeSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{"side":2}`), &shape))
+	suite.Require().Nil(shape.Circle)
+	suite.Require().Equal(NewShapeSchemaWithSquare(SquareSchema{Side: utils.ToPointer(2.0)}), shape)
+
+	err := json.Unmarshal([]byte(`{"diameter":2}`), &shape)
+	suite.Require().ErrorIs(err, extensions.ErrUnknownVariant)
+}
+
+func (suite *Suite) TestOneOfAmbiguousValue() {
+	// An empty object matches both variants
+	var shape ShapeSchema
+	err := json.Unmarshal([]byte(`{}`), &shape)
+	suite.Require().ErrorIs(err, extensions.ErrAmbiguousVariant)
+	suite.Require().Equal(ShapeSchema{}, shape)
+}
+
+func (suite *Suite) TestOneOfMultipleVariantsSet() {
+	shape := ShapeSchema{
+		Circle: &CircleSchema{Radius: utils.ToPointer(1.0)},
+		Square: &SquareSchema{Side: utils.ToPointer(2.0)},
+	}
+
+	_, err := json.Marshal(shape)
+	suite.Require().ErrorIs(err, extensions.ErrMultipleVariantsSet)
+}
+
+func (suite *Suite) TestAnyOf() {
+	// An empty object matches every variant
+	var contact ContactSchema
+	suite.Require().NoError(json.Unmarshal([]byte(`{}`), &contact))
+	suite.Require().NotNil(contact.Email)
+	suite.Require().NotNil(contact.Phone)
+
+	// Only the matching variant is set
+	suite.Require().NoError(json.Unmarshal([]byte(`{"phone":"0123"}`), &contact))
+	suite.Require().Nil(contact.Email)
+	phone, ok := contact.AsPhone()
+	suite.Require().True(ok)
+	suite.Require().Equal("0123", *phone.Phone)
+
+	// Marshal
+	data, err := json.Marshal(contact)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(`{"phone":"0123"}`, string(data))
+}
+
+func (suite *Suite) TestAnyOfMarshalMultipleVariants() {
+	contact := ContactSchema{
+		Email: &AnyOf0FromContactSchema{Email: utils.ToPointer("a@b.c")},
+		Phone: &AnyOf1FromContactSchema{Phone: utils.ToPointer("0123")},
+	}
+
+	// Only the first variant is marshaled
+	data, err := json.Marshal(contact)
+	suite.Require().NoError(err)
+	suite.Require().JSONEq(`{"email":"a@b.c"}`, string(data))
+}
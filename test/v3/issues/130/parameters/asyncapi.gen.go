@@ -5,8 +5,9 @@ package parameters
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
 )
@@ -96,9 +97,20 @@ func (c AppController) wrapMiddlewares(
 	}
 }
 
-func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -131,21 +143,25 @@ func (c *AppController) SubscribeToAllChannels(ctx context.Context, as AppSubscr
 
 // UnsubscribeFromAllChannels will stop the subscription of all remaining subscribed channels
 func (c *AppController) UnsubscribeFromAllChannels(ctx context.Context) {
+	c.UnsubscribeFromAllReceiveUserSignedUpOperation(ctx)
 }
 
 // SubscribeToReceiveUserSignedUpOperation will receive UserMessageFromUserSignupChannel messages from UserSignup channel.
 //
 // Callback function 'fn' will be called each time a new message is received.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SubscribeToReceiveUserSignedUpOperation(
 	ctx context.Context,
 	params UserSignupChannelParameters,
 	fn func(ctx context.Context, msg UserMessageFromUserSignupChannel) error,
+	options ...OperationOption,
 ) error {
+	// Set the default values of the parameters and check them
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		c.logger.Error(ctx, err.Error())
+		return err
+	}
+
 	// Get channel address
 	addr := fmt.Sprintf("v3.issue130.user.%s.signedup", params.UserId)
 
@@ -169,11 +185,14 @@ func (c *AppController) SubscribeToReceiveUserSignedUpOperation(
 	}
 	c.logger.Info(ctx, "Subscribed to channel")
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Asynchronously listen to new messages and pass them to app receiver
 	go func() {
 		for {
 			// Listen to next message
-			stop, err := c.listenToReceiveUserSignedUpOperationNextMessage(addr, sub, fn)
+			stop, err := c.listenToReceiveUserSignedUpOperationNextMessage(addr, sub, opts, fn)
 			if err != nil {
 				c.logger.Error(ctx, err.Error())
 			}
@@ -194,6 +213,7 @@ func (c *AppController) SubscribeToReceiveUserSignedUpOperation(
 func (c *AppController) listenToReceiveUserSignedUpOperationNextMessage(
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 	fn func(ctx context.Context, msg UserMessageFromUserSignupChannel) error,
 ) (stop bool, err error) {
 	// Create a context for the received response
@@ -211,11 +231,33 @@ func (c *AppController) listenToReceiveUserSignedUpOperationNextMessage(
 		return true, nil
 	}
 
-	// Set broker message to context
+	// Set broker message and its metadata to context
 	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
+
+	// Set the address on which the message has been received to context, if
+	// the broker gives it, as it can differ from the subscribed one
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsSubscribedChannel, addr)
+	receivedAddr := addr
+	if acknowledgeableBrokerMessage.Metadata.Channel != "" {
+		receivedAddr = acknowledgeableBrokerMessage.Metadata.Channel
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsChannel, receivedAddr)
+	}
+
+	// Extract the channel parameters from the received address and set them to context
+	params, err := UserSignupChannelParametersFromAddress(receivedAddr)
+	if err != nil {
+		// The address doesn't match the channel (e.g. matched by a broker
+		// wildcard only): handle it like any invalid message, with the error
+		// handler and a negative acknowledgment
+		c.errorHandler(msgCtx, addr, &acknowledgeableBrokerMessage, err)
+		acknowledgeableBrokerMessage.Nak()
+		return false, nil
+	}
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsChannelParameters, params)
 
 	// Execute middlewares before handling the message
-	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, func(middlewareCtx context.Context) error {
+	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, func(middlewareCtx context.Context) error {
 		// Process message
 		msg, err := brokerMessageToUserMessageFromUserSignupChannel(acknowledgeableBrokerMessage.BrokerMessage)
 		if err != nil {
@@ -231,6 +273,13 @@ func (c *AppController) listenToReceiveUserSignedUpOperationNextMessage(
 
 		return nil
 	}); err != nil {
+		// If a middleware asked to skip the message, then acknowledge it as it
+		// should not be processed again and do not consider it as an error
+		if errors.Is(err, extensions.ErrSkipMessage) {
+			acknowledgeableBrokerMessage.Ack()
+			return false, nil
+		}
+
 		c.errorHandler(msgCtx, addr, &acknowledgeableBrokerMessage, err)
 		// On error execute the acknowledgeableBrokerMessage nack() function and
 		// let the BrokerAcknowledgment decide what is the right nack behavior for the broker
@@ -246,6 +295,9 @@ func (c *AppController) UnsubscribeFromReceiveUserSignedUpOperation(
 	ctx context.Context,
 	params UserSignupChannelParameters,
 ) {
+	// Set the default values of the parameters
+	params.SetDefaults()
+
 	// Get channel address
 	addr := fmt.Sprintf("v3.issue130.user.%s.signedup", params.UserId)
 
@@ -267,6 +319,107 @@ func (c *AppController) UnsubscribeFromReceiveUserSignedUpOperation(
 	c.logger.Info(ctx, "Unsubscribed from channel")
 }
 
+// SubscribeToAllReceiveUserSignedUpOperation will receive UserMessageFromUserSignupChannel messages from all the
+// addresses of UserSignup channel, using the broker wildcards in place of the parameters.
+// The broker controller should implement extensions.WildcardBrokerController.
+//
+// Callback function 'fn' will be called each time a new message is received,
+// with the parameters extracted from the address the message has been received on.
+func (c *AppController) SubscribeToAllReceiveUserSignedUpOperation(
+	ctx context.Context,
+	fn func(ctx context.Context, params UserSignupChannelParameters, msg UserMessageFromUserSignupChannel) error,
+	options ...OperationOption,
+) error {
+	// Get channel address, with the parameters
+	addr := "v3.issue130.user.{userId}.signedup"
+
+	// Set context
+	ctx = addAppContextValues(ctx, addr)
+	ctx = context.WithValue(ctx, extensions.ContextKeyIsDirection, "reception")
+
+	// Check that the broker supports wildcards
+	broker, ok := c.broker.(extensions.WildcardBrokerController)
+	if !ok {
+		err := fmt.Errorf("%w: %T", extensions.ErrWildcardsNotSupported, c.broker)
+		c.logger.Error(ctx, err.Error())
+		return err
+	}
+
+	// Check if the controller is already subscribed
+	_, exists := c.subscriptions[addr]
+	if exists {
+		err := fmt.Errorf("%w: controller is already subscribed on channel %q", extensions.ErrAlreadySubscribedChannel, addr)
+		c.logger.Error(ctx, err.Error())
+		return err
+	}
+
+	// Subscribe to broker channel with wildcards
+	sub, err := broker.SubscribeWithWildcards(ctx, addr)
+	if err != nil {
+		c.logger.Error(ctx, err.Error())
+		return err
+	}
+	c.logger.Info(ctx, "Subscribed to channel")
+
+	// Get operation options
+	opts := newOperationOptions(options...)
+
+	// Get the parameters extracted from the received address
+	handler := func(ctx context.Context, msg UserMessageFromUserSignupChannel) error {
+		params, ok := ctx.Value(extensions.ContextKeyIsChannelParameters).(UserSignupChannelParameters)
+		if !ok {
+			return fmt.Errorf("%w: no parameters in context for %q", extensions.ErrInvalidChannelParameter, addr)
+		}
+		return fn(ctx, params, msg)
+	}
+
+	// Asynchronously listen to new messages and pass them to app receiver
+	go func() {
+		for {
+			// Listen to next message
+			stop, err := c.listenToReceiveUserSignedUpOperationNextMessage(addr, sub, opts, handler)
+			if err != nil {
+				c.logger.Error(ctx, err.Error())
+			}
+
+			// Stop if required
+			if stop {
+				return
+			}
+		}
+	}()
+
+	// Add the cancel channel to the inside map
+	c.subscriptions[addr] = sub
+
+	return nil
+}
+
+// UnsubscribeFromAllReceiveUserSignedUpOperation will stop the reception of UserMessageFromUserSignupChannel messages
+// started with SubscribeToAllReceiveUserSignedUpOperation.
+// A timeout can be set in context to avoid blocking operation, if needed.
+func (c *AppController) UnsubscribeFromAllReceiveUserSignedUpOperation(ctx context.Context) {
+	// Get channel address, with the parameters
+	addr := "v3.issue130.user.{userId}.signedup"
+
+	// Check if there receivers for this channel
+	sub, exists := c.subscriptions[addr]
+	if !exists {
+		return
+	}
+
+	// Set context
+	ctx = addAppContextValues(ctx, addr)
+
+	// Stop the subscription
+	sub.Cancel(ctx)
+
+	// Remove if from the receivers
+	delete(c.subscriptions, addr)
+
+	c.logger.Info(ctx, "Unsubscribed from channel")
+}
+
 // UserController is the structure that provides sending capabilities to the
 // developer and and connect the broker with the User
 type UserController struct {
@@ -346,9 +499,20 @@ func (c UserController) wrapMiddlewares(
 	}
 }
 
-func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -366,14 +530,19 @@ func (c *UserController) Close(ctx context.Context) {
 }
 
 // SendToReceiveUserSignedUpOperation will send a UserMessageFromUserSignupChannel message on UserSignup channel.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *UserController) SendToReceiveUserSignedUpOperation(
 	ctx context.Context,
 	params UserSignupChannelParameters,
 	msg UserMessageFromUserSignupChannel,
+	options ...OperationOption,
 ) error {
+	// Set the default values of the parameters and check them
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		c.logger.Error(ctx, err.Error())
+		return err
+	}
+
 	// Set channel address
 	addr := fmt.Sprintf("v3.issue130.user.%s.signedup", params.UserId)
 
@@ -391,8 +560,11 @@ func (c *UserController) SendToReceiveUserSignedUpOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -411,6 +583,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -433,6 +611,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -440,6 +632,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -460,6 +679,33 @@ type UserSignupChannelParameters struct {
 	UserId string
 }
 
+// SetDefaults sets the default values of the empty UserSignupChannel channel parameters.
+func (p *UserSignupChannelParameters) SetDefaults() {
+}
+
+// Validate checks that the UserSignupChannel channel parameters are
+// one of their possible values, if any.
+func (p UserSignupChannelParameters) Validate() error {
+	return nil
+}
+
+var regexpUserSignupChannelParametersAddress = regexp.MustCompile("^v3\\.issue130\\.user\\.(.*?)\\.signedup$")
+
+// UserSignupChannelParametersFromAddress extracts the UserSignupChannel
+// channel parameters from an address of the channel.
+func UserSignupChannelParametersFromAddress(addr string) (UserSignupChannelParameters, error) {
+	matches := regexpUserSignupChannelParametersAddress.FindStringSubmatch(addr)
+	if matches == nil {
+		return UserSignupChannelParameters{}, fmt.Errorf("%w: address %q doesn't match %q",
+			extensions.ErrInvalidChannelParameter, addr, "v3.issue130.user.{userId}.signedup")
+	}
+
+	values := matches[1:]
+	return UserSignupChannelParameters{
+		UserId: values[0],
+	}, nil
+}
+
 // UserMessageFromUserSignupChannelPayload is a schema from the AsyncAPI specification required in messages
 type UserMessageFromUserSignupChannelPayload struct {
 	Name *string `json:"name,omitempty"`
@@ -481,9 +727,13 @@ func NewUserMessageFromUserSignupChannel() UserMessageFromUserSignupChannel {
 func brokerMessageToUserMessageFromUserSignupChannel(bMsg extensions.BrokerMessage) (UserMessageFromUserSignupChannel, error) {
 	var msg UserMessageFromUserSignupChannel
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
@@ -496,8 +746,9 @@ func brokerMessageToUserMessageFromUserSignupChannel(bMsg extensions.BrokerMessa
 func (msg UserMessageFromUserSignupChannel) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
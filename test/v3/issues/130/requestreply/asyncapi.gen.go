@@ -5,7 +5,7 @@ package requestreply
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
@@ -101,9 +101,20 @@ func (c AppController) wrapMiddlewares(
 	}
 }
 
-func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -150,14 +161,10 @@ func (c *AppController) UnsubscribeFromAllChannels(ctx context.Context) {
 // SubscribeToPingOperation will receive Ping messages from Ping channel.
 //
 // Callback function 'fn' will be called each time a new message is received.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SubscribeToPingOperation(
 	ctx context.Context,
 	fn func(ctx context.Context, msg PingMessage) error,
+	options ...OperationOption,
 ) error {
 	// Get channel address
 	addr := "v3.issue130.ping"
@@ -182,11 +189,14 @@ func (c *AppController) SubscribeToPingOperation(
 	}
 	c.logger.Info(ctx, "Subscribed to channel")
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Asynchronously listen to new messages and pass them to app receiver
 	go func() {
 		for {
 			// Listen to next message
-			stop, err := c.listenToPingOperationNextMessage(addr, sub, fn)
+			stop, err := c.listenToPingOperationNextMessage(addr, sub, opts, fn)
 			if err != nil {
 				c.logger.Error(ctx, err.Error())
 			}
@@ -207,6 +217,7 @@ func (c *AppController) SubscribeToPingOperation(
 func (c *AppController) listenToPingOperationNextMessage(
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 	fn func(ctx context.Context, msg PingMessage) error,
 ) (stop bool, err error) {
 	// Create a context for the received response
@@ -224,11 +235,21 @@ func (c *AppController) listenToPingOperationNextMessage(
 		return true, nil
 	}
 
-	// Set broker message to context
+	// Set broker message and its metadata to context
 	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
+
+	// Set the address on which the message has been received to context, if
+	// the broker gives it, as it can differ from the subscribed one
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsSubscribedChannel, addr)
+	receivedAddr := addr
+	if acknowledgeableBrokerMessage.Metadata.Channel != "" {
+		receivedAddr = acknowledgeableBrokerMessage.Metadata.Channel
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsChannel, receivedAddr)
+	}
 
 	// Execute middlewares before handling the message
-	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, func(middlewareCtx context.Context) error {
+	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, func(middlewareCtx context.Context) error {
 		// Process message
 		msg, err := brokerMessageToPingMessage(acknowledgeableBrokerMessage.BrokerMessage)
 		if err != nil {
@@ -244,6 +265,13 @@ func (c *AppController) listenToPingOperationNextMessage(
 
 		return nil
 	}); err != nil {
+		// If a middleware asked to skip the message, then acknowledge it as it
+		// should not be processed again and do not consider it as an error
+		if errors.Is(err, extensions.ErrSkipMessage) {
+			acknowledgeableBrokerMessage.Ack()
+			return false, nil
+		}
+
 		c.errorHandler(msgCtx, addr, &acknowledgeableBrokerMessage, err)
 		// On error execute the acknowledgeableBrokerMessage nack() function and
 		// let the BrokerAcknowledgment decide what is the right nack behavior for the broker
@@ -255,7 +283,7 @@ func (c *AppController) listenToPingOperationNextMessage(
 
 // ReplyToPingOperation is a helper function to
 // reply to a Ping message with a Pong message on Pong channel.
-func (c *AppController) ReplyToPingOperation(ctx context.Context, recvMsg PingMessage, fn func(replyMsg *PongMessage)) error {
+func (c *AppController) ReplyToPingOperation(ctx context.Context, recvMsg PingMessage, fn func(replyMsg *PongMessage), options ...OperationOption) error {
 	// Create reply message
 	replyMsg := NewPongMessage()
 
@@ -263,7 +291,7 @@ func (c *AppController) ReplyToPingOperation(ctx context.Context, recvMsg PingMe
 	fn(&replyMsg)
 
 	// Publish reply
-	return c.SendAsReplyToPingOperation(ctx, replyMsg)
+	return c.SendAsReplyToPingOperation(ctx, replyMsg, options...)
 }
 
 // UnsubscribeFromPingOperation will stop the reception of Ping messages from Ping channel.
@@ -292,14 +320,10 @@ func (c *AppController) UnsubscribeFromPingOperation(
 	c.logger.Info(ctx, "Unsubscribed from channel")
 } // SubscribeToPingWithIDOperation will receive PingWithID messages from PingWithID channel.
 // Callback function 'fn' will be called each time a new message is received.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SubscribeToPingWithIDOperation(
 	ctx context.Context,
 	fn func(ctx context.Context, msg PingWithIDMessage) error,
+	options ...OperationOption,
 ) error {
 	// Get channel address
 	addr := "v3.issue130.pingWithID"
@@ -324,11 +348,14 @@ func (c *AppController) SubscribeToPingWithIDOperation(
 	}
 	c.logger.Info(ctx, "Subscribed to channel")
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Asynchronously listen to new messages and pass them to app receiver
 	go func() {
 		for {
 			// Listen to next message
-			stop, err := c.listenToPingWithIDOperationNextMessage(addr, sub, fn)
+			stop, err := c.listenToPingWithIDOperationNextMessage(addr, sub, opts, fn)
 			if err != nil {
 				c.logger.Error(ctx, err.Error())
 			}
@@ -349,6 +376,7 @@ func (c *AppController) SubscribeToPingWithIDOperation(
 func (c *AppController) listenToPingWithIDOperationNextMessage(
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 	fn func(ctx context.Context, msg PingWithIDMessage) error,
 ) (stop bool, err error) {
 	// Create a context for the received response
@@ -366,11 +394,21 @@ func (c *AppController) listenToPingWithIDOperationNextMessage(
 		return true, nil
 	}
 
-	// Set broker message to context
+	// Set broker message and its metadata to context
 	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
+
+	// Set the address on which the message has been received to context, if
+	// the broker gives it, as it can differ from the subscribed one
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsSubscribedChannel, addr)
+	receivedAddr := addr
+	if acknowledgeableBrokerMessage.Metadata.Channel != "" {
+		receivedAddr = acknowledgeableBrokerMessage.Metadata.Channel
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsChannel, receivedAddr)
+	}
 
 	// Execute middlewares before handling the message
-	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, func(middlewareCtx context.Context) error {
+	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, func(middlewareCtx context.Context) error {
 		// Process message
 		msg, err := brokerMessageToPingWithIDMessage(acknowledgeableBrokerMessage.BrokerMessage)
 		if err != nil {
@@ -391,6 +429,13 @@ func (c *AppController) listenToPingWithIDOperationNextMessage(
 
 		return nil
 	}); err != nil {
+		// If a middleware asked to skip the message, then acknowledge it as it
+		// should not be processed again and do not consider it as an error
+		if errors.Is(err, extensions.ErrSkipMessage) {
+			acknowledgeableBrokerMessage.Ack()
+			return false, nil
+		}
+
 		c.errorHandler(msgCtx, addr, &acknowledgeableBrokerMessage, err)
 		// On error execute the acknowledgeableBrokerMessage nack() function and
 		// let the BrokerAcknowledgment decide what is the right nack behavior for the broker
@@ -402,7 +447,7 @@ func (c *AppController) listenToPingWithIDOperationNextMessage(
 
 // ReplyToPingWithIDOperation is a helper function to
 // reply to a PingWithID message with a PongWithID message on PongWithID channel.
-func (c *AppController) ReplyToPingWithIDOperation(ctx context.Context, recvMsg PingWithIDMessage, fn func(replyMsg *PongWithIDMessage)) error {
+func (c *AppController) ReplyToPingWithIDOperation(ctx context.Context, recvMsg PingWithIDMessage, fn func(replyMsg *PongWithIDMessage), options ...OperationOption) error {
 	// Create reply message
 	replyMsg := NewPongWithIDMessage()
 	replyMsg.SetAsResponseFrom(&recvMsg)
@@ -411,7 +456,7 @@ func (c *AppController) ReplyToPingWithIDOperation(ctx context.Context, recvMsg
 	fn(&replyMsg)
 
 	// Publish reply
-	return c.SendAsReplyToPingWithIDOperation(ctx, replyMsg)
+	return c.SendAsReplyToPingWithIDOperation(ctx, replyMsg, options...)
 }
 
 // UnsubscribeFromPingWithIDOperation will stop the reception of PingWithID messages from PingWithID channel.
@@ -441,12 +486,10 @@ func (c *AppController) UnsubscribeFromPingWithIDOperation(
 }
 
 // SendAsReplyToPingOperation will send a Pong message on Pong channel.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SendAsReplyToPingOperation(
 	ctx context.Context,
 	msg PongMessage,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := "v3.issue130.pong"
@@ -465,18 +508,19 @@ func (c *AppController) SendAsReplyToPingOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
 // SendAsReplyToPingWithIDOperation will send a PongWithID message on PongWithID channel.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SendAsReplyToPingWithIDOperation(
 	ctx context.Context,
 	msg PongWithIDMessage,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := "v3.issue130.pongWithID"
@@ -503,8 +547,11 @@ func (c *AppController) SendAsReplyToPingWithIDOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -587,9 +634,20 @@ func (c UserController) wrapMiddlewares(
 	}
 }
 
-func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -609,11 +667,10 @@ func (c *UserController) Close(ctx context.Context) {
 // SendToPingOperation will send a Ping message on Ping channel.
 //
 // NOTE: this won't wait for reply, use the normal version to get the reply or do the catching reply manually.
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *UserController) SendToPingOperation(
 	ctx context.Context,
 	msg PingMessage,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := "v3.issue130.ping"
@@ -632,8 +689,11 @@ func (c *UserController) SendToPingOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -644,11 +704,15 @@ func (c *UserController) SendToPingOperation(
 // reply with the same correlation ID. Otherwise, it will returns the first
 // message on the reply channel.
 //
+// The operation options are applied both to the request publication and to the
+// reply reception: the operation middlewares will be executed on both messages.
+//
 // A timeout can be set in context to avoid blocking operation, if needed.
 
 func (c *UserController) RequestToPingOperation(
 	ctx context.Context,
 	msg PingMessage,
+	options ...OperationOption,
 ) (PongMessage, error) {
 	// Get receiving channel address
 	addr := "v3.issue130.pong"
@@ -675,15 +739,18 @@ func (c *UserController) RequestToPingOperation(
 	}()
 
 	// Send the message
-	if err := c.SendToPingOperation(ctx, msg); err != nil {
+	if err := c.SendToPingOperation(ctx, msg, options...); err != nil {
 		c.logger.Error(ctx, "error happened when sending message", extensions.LogInfo{Key: "error", Value: err.Error()})
 		return PongMessage{}, fmt.Errorf("error happened when sending message: %w", err)
 	}
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Wait for corresponding response
 	for {
 		// Listen to next message
-		msg, err := c.waitForPingOperationNextResponse(ctx, addr, sub)
+		msg, err := c.waitForPingOperationNextResponse(ctx, addr, sub, opts)
 		if err != nil {
 			c.logger.Error(ctx, err.Error())
 		}
@@ -701,6 +768,7 @@ func (c *UserController) waitForPingOperationNextResponse(
 	ctx context.Context,
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 ) (*PongMessage, error) {
 	// Create a context for the received response
 	msgCtx, cancel := context.WithCancel(context.Background())
@@ -723,9 +791,10 @@ func (c *UserController) waitForPingOperationNextResponse(
 
 		// Set context with received values as it is the expected message
 		msgCtx := context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
 
 		// Execute middlewares before returning
-		if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, nil); err != nil {
+		if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, nil); err != nil {
 			return nil, err
 		}
 
@@ -748,11 +817,10 @@ func (c *UserController) waitForPingOperationNextResponse(
 // SendToPingWithIDOperation will send a PingWithID message on PingWithID channel.
 //
 // NOTE: this won't wait for reply, use the normal version to get the reply or do the catching reply manually.
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *UserController) SendToPingWithIDOperation(
 	ctx context.Context,
 	msg PingWithIDMessage,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := "v3.issue130.pingWithID"
@@ -777,8 +845,11 @@ func (c *UserController) SendToPingWithIDOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -789,11 +860,15 @@ func (c *UserController) SendToPingWithIDOperation(
 // reply with the same correlation ID. Otherwise, it will returns the first
 // message on the reply channel.
 //
+// The operation options are applied both to the request publication and to the
+// reply reception: the operation middlewares will be executed on both messages.
+//
 // A timeout can be set in context to avoid blocking operation, if needed.
 
 func (c *UserController) RequestToPingWithIDOperation(
 	ctx context.Context,
 	msg PingWithIDMessage,
+	options ...OperationOption,
 ) (PongWithIDMessage, error) {
 	// Get receiving channel address
 	addr := "v3.issue130.pongWithID"
@@ -825,15 +900,18 @@ func (c *UserController) RequestToPingWithIDOperation(
 	}
 
 	// Send the message
-	if err := c.SendToPingWithIDOperation(ctx, msg); err != nil {
+	if err := c.SendToPingWithIDOperation(ctx, msg, options...); err != nil {
 		c.logger.Error(ctx, "error happened when sending message", extensions.LogInfo{Key: "error", Value: err.Error()})
 		return PongWithIDMessage{}, fmt.Errorf("error happened when sending message: %w", err)
 	}
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Wait for corresponding response
 	for {
 		// Listen to next message
-		msg, err := c.waitForPingWithIDOperationNextResponse(ctx, addr, sub, msg)
+		msg, err := c.waitForPingWithIDOperationNextResponse(ctx, addr, sub, opts, msg)
 		if err != nil {
 			c.logger.Error(ctx, err.Error())
 		}
@@ -851,6 +929,7 @@ func (c *UserController) waitForPingWithIDOperationNextResponse(
 	ctx context.Context,
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 	msg PingWithIDMessage,
 ) (*PongWithIDMessage, error) {
 	// Create a context for the received response
@@ -886,9 +965,10 @@ func (c *UserController) waitForPingWithIDOperationNextResponse(
 
 		// Set context with received values as it is the expected message
 		msgCtx := context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
 
 		// Execute middlewares before returning
-		if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, nil); err != nil {
+		if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, nil); err != nil {
 			return nil, err
 		}
 
@@ -923,6 +1003,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -945,6 +1031,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -952,6 +1052,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -1000,6 +1127,12 @@ type PingMessage struct {
 func NewPingMessage() PingMessage {
 	var msg PingMessage
 
+	// Set constant 'event' payload property
+	{
+		v := string("ping")
+		msg.Payload.Event = &v
+	}
+
 	return msg
 }
 
@@ -1007,9 +1140,13 @@ func NewPingMessage() PingMessage {
 func brokerMessageToPingMessage(bMsg extensions.BrokerMessage) (PingMessage, error) {
 	var msg PingMessage
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
@@ -1022,8 +1159,9 @@ func brokerMessageToPingMessage(bMsg extensions.BrokerMessage) (PingMessage, err
 func (msg PingMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
@@ -1064,6 +1202,12 @@ func NewPingWithIDMessage() PingWithIDMessage {
 	u := uuid.New().String()
 	msg.Headers.CorrelationId = &u
 
+	// Set constant 'event' payload property
+	{
+		v := string("ping")
+		msg.Payload.Event = &v
+	}
+
 	return msg
 }
 
@@ -1071,9 +1215,13 @@ func NewPingWithIDMessage() PingWithIDMessage {
 func brokerMessageToPingWithIDMessage(bMsg extensions.BrokerMessage) (PingWithIDMessage, error) {
 	var msg PingWithIDMessage
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
@@ -1093,12 +1241,30 @@ func brokerMessageToPingWithIDMessage(bMsg extensions.BrokerMessage) (PingWithID
 	return msg, nil
 }
 
+const (
+	// PingWithIDMessageCorrelationIdHeader is the key of the 'correlationId' header of PingWithIDMessage.
+	PingWithIDMessageCorrelationIdHeader = "correlationId"
+)
+
+// GetPingWithIDMessageCorrelationIdHeader returns the 'correlationId' header from a broker
+// message carrying PingWithIDMessage (e.g. in middlewares), decoded as in the
+// message headers. It returns false if the header is not set.
+func GetPingWithIDMessageCorrelationIdHeader(bMsg extensions.BrokerMessage) (h string, ok bool, err error) {
+	v, ok := bMsg.Headers[PingWithIDMessageCorrelationIdHeader]
+	if !ok {
+		return h, false, nil
+	}
+	h = string(v)
+	return h, true, err
+}
+
 // toBrokerMessage will generate a generic broker message from PingWithIDMessage data
 func (msg PingWithIDMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
@@ -1153,6 +1319,12 @@ type PongMessage struct {
 func NewPongMessage() PongMessage {
 	var msg PongMessage
 
+	// Set constant 'event' payload property
+	{
+		v := string("pong")
+		msg.Payload.Event = &v
+	}
+
 	return msg
 }
 
@@ -1160,9 +1332,13 @@ func NewPongMessage() PongMessage {
 func brokerMessageToPongMessage(bMsg extensions.BrokerMessage) (PongMessage, error) {
 	var msg PongMessage
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
@@ -1175,8 +1351,9 @@ func brokerMessageToPongMessage(bMsg extensions.BrokerMessage) (PongMessage, err
 func (msg PongMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
@@ -1217,6 +1394,12 @@ func NewPongWithIDMessage() PongWithIDMessage {
 	u := uuid.New().String()
 	msg.Headers.CorrelationId = &u
 
+	// Set constant 'event' payload property
+	{
+		v := string("pong")
+		msg.Payload.Event = &v
+	}
+
 	return msg
 }
 
@@ -1224,9 +1407,13 @@ func NewPongWithIDMessage() PongWithIDMessage {
 func brokerMessageToPongWithIDMessage(bMsg extensions.BrokerMessage) (PongWithIDMessage, error) {
 	var msg PongWithIDMessage
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
@@ -1246,12 +1433,30 @@ func brokerMessageToPongWithIDMessage(bMsg extensions.BrokerMessage) (PongWithID
 	return msg, nil
 }
 
+const (
+	// PongWithIDMessageCorrelationIdHeader is the key of the 'correlationId' header of PongWithIDMessage.
+	PongWithIDMessageCorrelationIdHeader = "correlationId"
+)
+
+// GetPongWithIDMessageCorrelationIdHeader returns the 'correlationId' header from a broker
+// message carrying PongWithIDMessage (e.g. in middlewares), decoded as in the
+// message headers. It returns false if the header is not set.
+func GetPongWithIDMessageCorrelationIdHeader(bMsg extensions.BrokerMessage) (h string, ok bool, err error) {
+	v, ok := bMsg.Headers[PongWithIDMessageCorrelationIdHeader]
+	if !ok {
+		return h, false, nil
+	}
+	h = string(v)
+	return h, true, err
+}
+
 // toBrokerMessage will generate a generic broker message from PongWithIDMessage data
 func (msg PongWithIDMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
@@ -6,6 +6,7 @@ package issue131
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
@@ -96,9 +97,20 @@ func (c AppController) wrapMiddlewares(
 	}
 }
 
-func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -141,14 +153,10 @@ func (c *AppController) UnsubscribeFromAllChannels(ctx context.Context) {
 // SubscribeToReceiveTestOperation will receive TestMessageFromTestChannel messages from Test channel.
 //
 // Callback function 'fn' will be called each time a new message is received.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SubscribeToReceiveTestOperation(
 	ctx context.Context,
 	fn func(ctx context.Context, msg TestMessageFromTestChannel) error,
+	options ...OperationOption,
 ) error {
 	// Get channel address
 	addr := "v3.issue131.test"
@@ -173,11 +181,14 @@ func (c *AppController) SubscribeToReceiveTestOperation(
 	}
 	c.logger.Info(ctx, "Subscribed to channel")
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Asynchronously listen to new messages and pass them to app receiver
 	go func() {
 		for {
 			// Listen to next message
-			stop, err := c.listenToReceiveTestOperationNextMessage(addr, sub, fn)
+			stop, err := c.listenToReceiveTestOperationNextMessage(addr, sub, opts, fn)
 			if err != nil {
 				c.logger.Error(ctx, err.Error())
 			}
@@ -198,6 +209,7 @@ func (c *AppController) SubscribeToReceiveTestOperation(
 func (c *AppController) listenToReceiveTestOperationNextMessage(
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 	fn func(ctx context.Context, msg TestMessageFromTestChannel) error,
 ) (stop bool, err error) {
 	// Create a context for the received response
@@ -215,11 +227,21 @@ func (c *AppController) listenToReceiveTestOperationNextMessage(
 		return true, nil
 	}
 
-	// Set broker message to context
+	// Set broker message and its metadata to context
 	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
+
+	// Set the address on which the message has been received to context, if
+	// the broker gives it, as it can differ from the subscribed one
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsSubscribedChannel, addr)
+	receivedAddr := addr
+	if acknowledgeableBrokerMessage.Metadata.Channel != "" {
+		receivedAddr = acknowledgeableBrokerMessage.Metadata.Channel
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsChannel, receivedAddr)
+	}
 
 	// Execute middlewares before handling the message
-	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, func(middlewareCtx context.Context) error {
+	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, func(middlewareCtx context.Context) error {
 		// Process message
 		msg, err := brokerMessageToTestMessageFromTestChannel(acknowledgeableBrokerMessage.BrokerMessage)
 		if err != nil {
@@ -235,6 +257,13 @@ func (c *AppController) listenToReceiveTestOperationNextMessage(
 
 		return nil
 	}); err != nil {
+		// If a middleware asked to skip the message, then acknowledge it as it
+		// should not be processed again and do not consider it as an error
+		if errors.Is(err, extensions.ErrSkipMessage) {
+			acknowledgeableBrokerMessage.Ack()
+			return false, nil
+		}
+
 		c.errorHandler(msgCtx, addr, &acknowledgeableBrokerMessage, err)
 		// On error execute the acknowledgeableBrokerMessage nack() function and
 		// let the BrokerAcknowledgment decide what is the right nack behavior for the broker
@@ -349,9 +378,20 @@ func (c UserController) wrapMiddlewares(
 	}
 }
 
-func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -369,12 +409,10 @@ func (c *UserController) Close(ctx context.Context) {
 }
 
 // SendToReceiveTestOperation will send a TestMessageFromTestChannel message on Test channel.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *UserController) SendToReceiveTestOperation(
 	ctx context.Context,
 	msg TestMessageFromTestChannel,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := "v3.issue131.test"
@@ -393,8 +431,11 @@ func (c *UserController) SendToReceiveTestOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -413,6 +454,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -435,6 +482,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -442,6 +503,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -465,6 +553,12 @@ type TestMessageFromTestChannel struct {
 func NewTestMessageFromTestChannel() TestMessageFromTestChannel {
 	var msg TestMessageFromTestChannel
 
+	// Set constant 'ConstProp' payload property
+	{
+		v := string("Canada")
+		msg.Payload.ConstProp = &v
+	}
+
 	return msg
 }
 
@@ -472,9 +566,13 @@ func NewTestMessageFromTestChannel() TestMessageFromTestChannel {
 func brokerMessageToTestMessageFromTestChannel(bMsg extensions.BrokerMessage) (TestMessageFromTestChannel, error) {
 	var msg TestMessageFromTestChannel
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
@@ -487,8 +585,9 @@ func brokerMessageToTestMessageFromTestChannel(bMsg extensions.BrokerMessage) (T
 func (msg TestMessageFromTestChannel) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
@@ -504,14 +603,61 @@ func (msg TestMessageFromTestChannel) toBrokerMessage() (extensions.BrokerMessag
 
 // TestSchema is a schema from the AsyncAPI specification required in messages
 type TestSchema struct {
-	ArrayProp            []string `json:"ArrayProp,omitempty" validate:"omitempty,min=2,max=5,unique"`
-	ConstProp            *string  `json:"ConstProp,omitempty" validate:"omitempty,eq=Canada"`
-	EnumProp             *string  `json:"EnumProp,omitempty" validate:"omitempty,oneof='red' 'amber' 'green'"`
-	FloatProp            *float64 `json:"FloatProp,omitempty" validate:"omitempty,gte=2.5,lte=5.5"`
-	IntegerExclusiveProp *int64   `json:"IntegerExclusiveProp,omitempty" validate:"omitempty,gt=2,lt=5"`
-	IntegerProp          *int64   `json:"IntegerProp,omitempty" validate:"omitempty,gte=2,lte=5"`
-	RequiredProp         string   `json:"RequiredProp"`
-	StringProp           *string  `json:"StringProp,omitempty" validate:"omitempty,min=2,max=5"`
+	ArrayProp            []string                        `json:"ArrayProp,omitempty" validate:"omitempty,min=2,max=5,unique"`
+	ConstProp            *string                         `json:"ConstProp,omitempty" validate:"omitempty,eq=Canada"`
+	EnumProp             *EnumPropPropertyFromTestSchema `json:"EnumProp,omitempty" validate:"omitempty,oneof='red' 'amber' 'green'"`
+	FloatProp            *float64                        `json:"FloatProp,omitempty" validate:"omitempty,gte=2.5,lte=5.5"`
+	IntegerExclusiveProp *int64                          `json:"IntegerExclusiveProp,omitempty" validate:"omitempty,gt=2,lt=5"`
+	IntegerProp          *int64                          `json:"IntegerProp,omitempty" validate:"omitempty,gte=2,lte=5"`
+	RequiredProp         string                          `json:"RequiredProp"`
+	StringProp           *string                         `json:"StringProp,omitempty" validate:"omitempty,min=2,max=5"`
+}
+
+// EnumPropPropertyFromTestSchema is a schema from the AsyncAPI specification required in messages
+type EnumPropPropertyFromTestSchema string
+
+const (
+	// EnumPropPropertyFromTestSchemaRed is the "red" value of EnumPropPropertyFromTestSchema.
+	EnumPropPropertyFromTestSchemaRed EnumPropPropertyFromTestSchema = "red"
+	// EnumPropPropertyFromTestSchemaAmber is the "amber" value of EnumPropPropertyFromTestSchema.
+	EnumPropPropertyFromTestSchemaAmber EnumPropPropertyFromTestSchema = "amber"
+	// EnumPropPropertyFromTestSchemaGreen is the "green" value of EnumPropPropertyFromTestSchema.
+	EnumPropPropertyFromTestSchemaGreen EnumPropPropertyFromTestSchema = "green"
+)
+
+// Values returns all the possible values of EnumPropPropertyFromTestSchema.
+func (EnumPropPropertyFromTestSchema) Values() []EnumPropPropertyFromTestSchema {
+	return []EnumPropPropertyFromTestSchema{
+		EnumPropPropertyFromTestSchemaRed,
+		EnumPropPropertyFromTestSchemaAmber,
+		EnumPropPropertyFromTestSchemaGreen,
+	}
+}
+
+// IsValid checks if the value is one of the possible values of EnumPropPropertyFromTestSchema.
+func (e EnumPropPropertyFromTestSchema) IsValid() bool {
+	switch e {
+	case EnumPropPropertyFromTestSchemaRed, EnumPropPropertyFromTestSchemaAmber, EnumPropPropertyFromTestSchemaGreen:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnmarshalJSON unmarshals the JSON value and checks that it is one of the
+// possible values of EnumPropPropertyFromTestSchema.
+func (e *EnumPropPropertyFromTestSchema) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if !EnumPropPropertyFromTestSchema(v).IsValid() {
+		return fmt.Errorf("%w: %v is not a valid 'EnumPropPropertyFromTestSchema' value", extensions.ErrInvalidEnumValue, v)
+	}
+
+	*e = EnumPropPropertyFromTestSchema(v)
+	return nil
 }
 
 const (
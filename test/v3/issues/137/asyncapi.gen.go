@@ -4,6 +4,7 @@
 package issue137
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
@@ -24,6 +25,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -46,6 +53,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -53,6 +74,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -75,3 +123,53 @@ type AuditSchema struct {
 
 // ChannelSchema is a schema from the AsyncAPI specification required in messages
 type ChannelSchema string
+
+const (
+	// ChannelSchemaAPI0 is the "API0" value of ChannelSchema.
+	ChannelSchemaAPI0 ChannelSchema = "API0"
+	// ChannelSchemaAPI1 is the "API1" value of ChannelSchema.
+	ChannelSchemaAPI1 ChannelSchema = "API1"
+	// ChannelSchemaAPI2 is the "API2" value of ChannelSchema.
+	ChannelSchemaAPI2 ChannelSchema = "API2"
+	// ChannelSchemaAPI3 is the "API3" value of ChannelSchema.
+	ChannelSchemaAPI3 ChannelSchema = "API3"
+	// ChannelSchemaAPI4 is the "API4" value of ChannelSchema.
+	ChannelSchemaAPI4 ChannelSchema = "API4"
+)
+
+// Values returns all the possible values of ChannelSchema.
+func (ChannelSchema) Values() []ChannelSchema {
+	return []ChannelSchema{
+		ChannelSchemaAPI0,
+		ChannelSchemaAPI1,
+		ChannelSchemaAPI2,
+		ChannelSchemaAPI3,
+		ChannelSchemaAPI4,
+	}
+}
+
+// IsValid checks if the value is one of the possible values of ChannelSchema.
+func (e ChannelSchema) IsValid() bool {
+	switch e {
+	case ChannelSchemaAPI0, ChannelSchemaAPI1, ChannelSchemaAPI2, ChannelSchemaAPI3, ChannelSchemaAPI4:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnmarshalJSON unmarshals the JSON value and checks that it is one of the
+// possible values of ChannelSchema.
+func (e *ChannelSchema) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if !ChannelSchema(v).IsValid() {
+		return fmt.Errorf("%w: %v is not a valid 'ChannelSchema' value", extensions.ErrInvalidEnumValue, v)
+	}
+
+	*e = ChannelSchema(v)
+	return nil
+}
@@ -5,10 +5,12 @@ package issue145
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
+
+	"github.com/google/uuid"
 )
 
 // AppSubscriber contains all handlers that are listening messages for App
@@ -96,9 +98,20 @@ func (c AppController) wrapMiddlewares(
 	}
 }
 
-func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -141,14 +154,10 @@ func (c *AppController) UnsubscribeFromAllChannels(ctx context.Context) {
 // SubscribeToPingRequestOperation will receive Ping messages from Ping channel.
 //
 // Callback function 'fn' will be called each time a new message is received.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SubscribeToPingRequestOperation(
 	ctx context.Context,
 	fn func(ctx context.Context, msg PingMessage) error,
+	options ...OperationOption,
 ) error {
 	// Get channel address
 	addr := "v3.issue145.ping"
@@ -173,11 +182,14 @@ func (c *AppController) SubscribeToPingRequestOperation(
 	}
 	c.logger.Info(ctx, "Subscribed to channel")
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Asynchronously listen to new messages and pass them to app receiver
 	go func() {
 		for {
 			// Listen to next message
-			stop, err := c.listenToPingRequestOperationNextMessage(addr, sub, fn)
+			stop, err := c.listenToPingRequestOperationNextMessage(addr, sub, opts, fn)
 			if err != nil {
 				c.logger.Error(ctx, err.Error())
 			}
@@ -198,6 +210,7 @@ func (c *AppController) SubscribeToPingRequestOperation(
 func (c *AppController) listenToPingRequestOperationNextMessage(
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 	fn func(ctx context.Context, msg PingMessage) error,
 ) (stop bool, err error) {
 	// Create a context for the received response
@@ -215,11 +228,21 @@ func (c *AppController) listenToPingRequestOperationNextMessage(
 		return true, nil
 	}
 
-	// Set broker message to context
+	// Set broker message and its metadata to context
 	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
+
+	// Set the address on which the message has been received to context, if
+	// the broker gives it, as it can differ from the subscribed one
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsSubscribedChannel, addr)
+	receivedAddr := addr
+	if acknowledgeableBrokerMessage.Metadata.Channel != "" {
+		receivedAddr = acknowledgeableBrokerMessage.Metadata.Channel
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsChannel, receivedAddr)
+	}
 
 	// Execute middlewares before handling the message
-	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, func(middlewareCtx context.Context) error {
+	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, func(middlewareCtx context.Context) error {
 		// Process message
 		msg, err := brokerMessageToPingMessage(acknowledgeableBrokerMessage.BrokerMessage)
 		if err != nil {
@@ -235,6 +258,13 @@ func (c *AppController) listenToPingRequestOperationNextMessage(
 
 		return nil
 	}); err != nil {
+		// If a middleware asked to skip the message, then acknowledge it as it
+		// should not be processed again and do not consider it as an error
+		if errors.Is(err, extensions.ErrSkipMessage) {
+			acknowledgeableBrokerMessage.Ack()
+			return false, nil
+		}
+
 		c.errorHandler(msgCtx, addr, &acknowledgeableBrokerMessage, err)
 		// On error execute the acknowledgeableBrokerMessage nack() function and
 		// let the BrokerAcknowledgment decide what is the right nack behavior for the broker
@@ -246,7 +276,7 @@ func (c *AppController) listenToPingRequestOperationNextMessage(
 
 // ReplyToPingRequestOperation is a helper function to
 // reply to a Ping message with a Pong message on Pong channel.
-func (c *AppController) ReplyToPingRequestOperation(ctx context.Context, recvMsg PingMessage, fn func(replyMsg *PongMessage)) error {
+func (c *AppController) ReplyToPingRequestOperation(ctx context.Context, recvMsg PingMessage, fn func(replyMsg *PongMessage), options ...OperationOption) error {
 	// Create reply message
 	replyMsg := NewPongMessage()
 
@@ -259,7 +289,7 @@ func (c *AppController) ReplyToPingRequestOperation(ctx context.Context, recvMsg
 	}
 	chanAddr := *recvMsg.Headers.ReplyTo
 
-	return c.SendAsReplyToPingRequestOperation(ctx, chanAddr, replyMsg)
+	return c.SendAsReplyToPingRequestOperation(ctx, chanAddr, replyMsg, options...)
 }
 
 // UnsubscribeFromPingRequestOperation will stop the reception of Ping messages from Ping channel.
@@ -289,13 +319,11 @@ func (c *AppController) UnsubscribeFromPingRequestOperation(
 }
 
 // SendAsReplyToPingRequestOperation will send a Pong message on Pong channel.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SendAsReplyToPingRequestOperation(
 	ctx context.Context,
 	chanAddr string,
 	msg PongMessage,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := chanAddr
@@ -314,8 +342,11 @@ func (c *AppController) SendAsReplyToPingRequestOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -398,9 +429,20 @@ func (c UserController) wrapMiddlewares(
 	}
 }
 
-func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -420,11 +462,10 @@ func (c *UserController) Close(ctx context.Context) {
 // SendToPingRequestOperation will send a Ping message on Ping channel.
 //
 // NOTE: this won't wait for reply, use the normal version to get the reply or do the catching reply manually.
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *UserController) SendToPingRequestOperation(
 	ctx context.Context,
 	msg PingMessage,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := "v3.issue145.ping"
@@ -443,8 +484,11 @@ func (c *UserController) SendToPingRequestOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -455,11 +499,15 @@ func (c *UserController) SendToPingRequestOperation(
 // reply with the same correlation ID. Otherwise, it will returns the first
 // message on the reply channel.
 //
+// The operation options are applied both to the request publication and to the
+// reply reception: the operation middlewares will be executed on both messages.
+//
 // A timeout can be set in context to avoid blocking operation, if needed.
 
 func (c *UserController) RequestToPingRequestOperation(
 	ctx context.Context,
 	msg PingMessage,
+	options ...OperationOption,
 ) (PongMessage, error) {
 	// Get receiving channel address
 	if msg.Headers.ReplyTo == nil {
@@ -489,15 +537,18 @@ func (c *UserController) RequestToPingRequestOperation(
 	}()
 
 	// Send the message
-	if err := c.SendToPingRequestOperation(ctx, msg); err != nil {
+	if err := c.SendToPingRequestOperation(ctx, msg, options...); err != nil {
 		c.logger.Error(ctx, "error happened when sending message", extensions.LogInfo{Key: "error", Value: err.Error()})
 		return PongMessage{}, fmt.Errorf("error happened when sending message: %w", err)
 	}
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Wait for corresponding response
 	for {
 		// Listen to next message
-		msg, err := c.waitForPingRequestOperationNextResponse(ctx, addr, sub)
+		msg, err := c.waitForPingRequestOperationNextResponse(ctx, addr, sub, opts)
 		if err != nil {
 			c.logger.Error(ctx, err.Error())
 		}
@@ -515,6 +566,7 @@ func (c *UserController) waitForPingRequestOperationNextResponse(
 	ctx context.Context,
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 ) (*PongMessage, error) {
 	// Create a context for the received response
 	msgCtx, cancel := context.WithCancel(context.Background())
@@ -537,9 +589,10 @@ func (c *UserController) waitForPingRequestOperationNextResponse(
 
 		// Set context with received values as it is the expected message
 		msgCtx := context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
 
 		// Execute middlewares before returning
-		if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, nil); err != nil {
+		if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, nil); err != nil {
 			return nil, err
 		}
 
@@ -574,6 +627,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -596,6 +655,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -603,6 +676,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -633,7 +733,7 @@ type HeadersFromPingMessage struct {
 	ReplyTo *string `json:"replyTo,omitempty"`
 
 	// Description: Provide request id that you will use to identify the reply match
-	RequestId *string `json:"requestId,omitempty"`
+	RequestId *uuid.UUID `json:"requestId,omitempty"`
 }
 
 // PingMessagePayload is a schema from the AsyncAPI specification required in messages
@@ -653,6 +753,12 @@ type PingMessage struct {
 func NewPingMessage() PingMessage {
 	var msg PingMessage
 
+	// Set constant 'event' payload property
+	{
+		v := string("ping")
+		msg.Payload.Event = &v
+	}
+
 	return msg
 }
 
@@ -660,9 +766,13 @@ func NewPingMessage() PingMessage {
 func brokerMessageToPingMessage(bMsg extensions.BrokerMessage) (PingMessage, error) {
 	var msg PingMessage
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
@@ -673,7 +783,10 @@ func brokerMessageToPingMessage(bMsg extensions.BrokerMessage) (PingMessage, err
 			h := string(v)
 			msg.Headers.ReplyTo = &h
 		case k == "requestId": // Retrieving RequestId header
-			h := string(v)
+			var h uuid.UUID
+			if err := h.UnmarshalText(v); err != nil {
+				return msg, err
+			}
 			msg.Headers.RequestId = &h
 		default:
 			// TODO: log unknown error
@@ -685,12 +798,44 @@ func brokerMessageToPingMessage(bMsg extensions.BrokerMessage) (PingMessage, err
 	return msg, nil
 }
 
+const (
+	// PingMessageReplyToHeader is the key of the 'replyTo' header of PingMessage.
+	PingMessageReplyToHeader = "replyTo"
+	// PingMessageRequestIdHeader is the key of the 'requestId' header of PingMessage.
+	PingMessageRequestIdHeader = "requestId"
+)
+
+// GetPingMessageReplyToHeader returns the 'replyTo' header from a broker
+// message carrying PingMessage (e.g. in middlewares), decoded as in the
+// message headers. It returns false if the header is not set.
+func GetPingMessageReplyToHeader(bMsg extensions.BrokerMessage) (h string, ok bool, err error) {
+	v, ok := bMsg.Headers[PingMessageReplyToHeader]
+	if !ok {
+		return h, false, nil
+	}
+	h = string(v)
+	return h, true, err
+}
+
+// GetPingMessageRequestIdHeader returns the 'requestId' header from a broker
+// message carrying PingMessage (e.g. in middlewares), decoded as in the
+// message headers. It returns false if the header is not set.
+func GetPingMessageRequestIdHeader(bMsg extensions.BrokerMessage) (h uuid.UUID, ok bool, err error) {
+	v, ok := bMsg.Headers[PingMessageRequestIdHeader]
+	if !ok {
+		return h, false, nil
+	}
+	err = h.UnmarshalText(v)
+	return h, true, err
+}
+
 // toBrokerMessage will generate a generic broker message from PingMessage data
 func (msg PingMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
@@ -705,7 +850,11 @@ func (msg PingMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 
 	// Adding RequestId header
 	if msg.Headers.RequestId != nil {
-		headers["requestId"] = []byte(*msg.Headers.RequestId)
+		h, err := msg.Headers.RequestId.MarshalText()
+		if err != nil {
+			return extensions.BrokerMessage{}, err
+		}
+		headers["requestId"] = h
 	}
 
 	return extensions.BrokerMessage{
@@ -717,7 +866,7 @@ func (msg PingMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 // HeadersFromPongMessage is a schema from the AsyncAPI specification required in messages
 type HeadersFromPongMessage struct {
 	// Description: Reply message must contain id of the request message
-	RequestId *string `json:"requestId,omitempty"`
+	RequestId *uuid.UUID `json:"requestId,omitempty"`
 }
 
 // PongMessagePayload is a schema from the AsyncAPI specification required in messages
@@ -737,6 +886,12 @@ type PongMessage struct {
 func NewPongMessage() PongMessage {
 	var msg PongMessage
 
+	// Set constant 'event' payload property
+	{
+		v := string("pong")
+		msg.Payload.Event = &v
+	}
+
 	return msg
 }
 
@@ -744,9 +899,13 @@ func NewPongMessage() PongMessage {
 func brokerMessageToPongMessage(bMsg extensions.BrokerMessage) (PongMessage, error) {
 	var msg PongMessage
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
@@ -754,7 +913,10 @@ func brokerMessageToPongMessage(bMsg extensions.BrokerMessage) (PongMessage, err
 	for k, v := range bMsg.Headers {
 		switch {
 		case k == "requestId": // Retrieving RequestId header
-			h := string(v)
+			var h uuid.UUID
+			if err := h.UnmarshalText(v); err != nil {
+				return msg, err
+			}
 			msg.Headers.RequestId = &h
 		default:
 			// TODO: log unknown error
@@ -766,12 +928,30 @@ func brokerMessageToPongMessage(bMsg extensions.BrokerMessage) (PongMessage, err
 	return msg, nil
 }
 
+const (
+	// PongMessageRequestIdHeader is the key of the 'requestId' header of PongMessage.
+	PongMessageRequestIdHeader = "requestId"
+)
+
+// GetPongMessageRequestIdHeader returns the 'requestId' header from a broker
+// message carrying PongMessage (e.g. in middlewares), decoded as in the
+// message headers. It returns false if the header is not set.
+func GetPongMessageRequestIdHeader(bMsg extensions.BrokerMessage) (h uuid.UUID, ok bool, err error) {
+	v, ok := bMsg.Headers[PongMessageRequestIdHeader]
+	if !ok {
+		return h, false, nil
+	}
+	err = h.UnmarshalText(v)
+	return h, true, err
+}
+
 // toBrokerMessage will generate a generic broker message from PongMessage data
 func (msg PongMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
@@ -781,7 +961,11 @@ func (msg PongMessage) toBrokerMessage() (extensions.BrokerMessage, error) {
 
 	// Adding RequestId header
 	if msg.Headers.RequestId != nil {
-		headers["requestId"] = []byte(*msg.Headers.RequestId)
+		h, err := msg.Headers.RequestId.MarshalText()
+		if err != nil {
+			return extensions.BrokerMessage{}, err
+		}
+		headers["requestId"] = h
 	}
 
 	return extensions.BrokerMessage{
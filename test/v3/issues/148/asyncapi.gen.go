@@ -5,6 +5,7 @@ package issue148
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
@@ -95,9 +96,20 @@ func (c AppController) wrapMiddlewares(
 	}
 }
 
-func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -140,14 +152,10 @@ func (c *AppController) UnsubscribeFromAllChannels(ctx context.Context) {
 // SubscribeToGetServiceInfoOperation will receive RequestMessageFromReceptionChannel messages from Reception channel.
 //
 // Callback function 'fn' will be called each time a new message is received.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SubscribeToGetServiceInfoOperation(
 	ctx context.Context,
 	fn func(ctx context.Context, msg RequestMessageFromReceptionChannel) error,
+	options ...OperationOption,
 ) error {
 	// Get channel address
 	addr := "v3.issue148.reception"
@@ -172,11 +180,14 @@ func (c *AppController) SubscribeToGetServiceInfoOperation(
 	}
 	c.logger.Info(ctx, "Subscribed to channel")
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Asynchronously listen to new messages and pass them to app receiver
 	go func() {
 		for {
 			// Listen to next message
-			stop, err := c.listenToGetServiceInfoOperationNextMessage(addr, sub, fn)
+			stop, err := c.listenToGetServiceInfoOperationNextMessage(addr, sub, opts, fn)
 			if err != nil {
 				c.logger.Error(ctx, err.Error())
 			}
@@ -197,6 +208,7 @@ func (c *AppController) SubscribeToGetServiceInfoOperation(
 func (c *AppController) listenToGetServiceInfoOperationNextMessage(
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 	fn func(ctx context.Context, msg RequestMessageFromReceptionChannel) error,
 ) (stop bool, err error) {
 	// Create a context for the received response
@@ -214,11 +226,21 @@ func (c *AppController) listenToGetServiceInfoOperationNextMessage(
 		return true, nil
 	}
 
-	// Set broker message to context
+	// Set broker message and its metadata to context
 	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
+
+	// Set the address on which the message has been received to context, if
+	// the broker gives it, as it can differ from the subscribed one
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsSubscribedChannel, addr)
+	receivedAddr := addr
+	if acknowledgeableBrokerMessage.Metadata.Channel != "" {
+		receivedAddr = acknowledgeableBrokerMessage.Metadata.Channel
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsChannel, receivedAddr)
+	}
 
 	// Execute middlewares before handling the message
-	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, func(middlewareCtx context.Context) error {
+	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, func(middlewareCtx context.Context) error {
 		// Process message
 		msg, err := brokerMessageToRequestMessageFromReceptionChannel(acknowledgeableBrokerMessage.BrokerMessage)
 		if err != nil {
@@ -234,6 +256,13 @@ func (c *AppController) listenToGetServiceInfoOperationNextMessage(
 
 		return nil
 	}); err != nil {
+		// If a middleware asked to skip the message, then acknowledge it as it
+		// should not be processed again and do not consider it as an error
+		if errors.Is(err, extensions.ErrSkipMessage) {
+			acknowledgeableBrokerMessage.Ack()
+			return false, nil
+		}
+
 		c.errorHandler(msgCtx, addr, &acknowledgeableBrokerMessage, err)
 		// On error execute the acknowledgeableBrokerMessage nack() function and
 		// let the BrokerAcknowledgment decide what is the right nack behavior for the broker
@@ -245,7 +274,7 @@ func (c *AppController) listenToGetServiceInfoOperationNextMessage(
 
 // ReplyToGetServiceInfoOperation is a helper function to
 // reply to a RequestMessageFromReceptionChannel message with a ReplyMessageFromReplyChannel message on Reply channel.
-func (c *AppController) ReplyToGetServiceInfoOperation(ctx context.Context, recvMsg RequestMessageFromReceptionChannel, fn func(replyMsg *ReplyMessageFromReplyChannel)) error {
+func (c *AppController) ReplyToGetServiceInfoOperation(ctx context.Context, recvMsg RequestMessageFromReceptionChannel, fn func(replyMsg *ReplyMessageFromReplyChannel), options ...OperationOption) error {
 	// Create reply message
 	replyMsg := NewReplyMessageFromReplyChannel()
 
@@ -258,7 +287,7 @@ func (c *AppController) ReplyToGetServiceInfoOperation(ctx context.Context, recv
 	}
 	chanAddr := *recvMsg.Headers.ReplyTo
 
-	return c.SendAsReplyToGetServiceInfoOperation(ctx, chanAddr, replyMsg)
+	return c.SendAsReplyToGetServiceInfoOperation(ctx, chanAddr, replyMsg, options...)
 }
 
 // UnsubscribeFromGetServiceInfoOperation will stop the reception of RequestMessageFromReceptionChannel messages from Reception channel.
@@ -288,13 +317,11 @@ func (c *AppController) UnsubscribeFromGetServiceInfoOperation(
 }
 
 // SendAsReplyToGetServiceInfoOperation will send a ReplyMessageFromReplyChannel message on Reply channel.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SendAsReplyToGetServiceInfoOperation(
 	ctx context.Context,
 	chanAddr string,
 	msg ReplyMessageFromReplyChannel,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := chanAddr
@@ -313,8 +340,11 @@ func (c *AppController) SendAsReplyToGetServiceInfoOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -397,9 +427,20 @@ func (c UserController) wrapMiddlewares(
 	}
 }
 
-func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -419,11 +460,10 @@ func (c *UserController) Close(ctx context.Context) {
 // SendToGetServiceInfoOperation will send a RequestMessageFromReceptionChannel message on Reception channel.
 //
 // NOTE: this won't wait for reply, use the normal version to get the reply or do the catching reply manually.
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *UserController) SendToGetServiceInfoOperation(
 	ctx context.Context,
 	msg RequestMessageFromReceptionChannel,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := "v3.issue148.reception"
@@ -442,8 +482,11 @@ func (c *UserController) SendToGetServiceInfoOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -454,11 +497,15 @@ func (c *UserController) SendToGetServiceInfoOperation(
 // reply with the same correlation ID. Otherwise, it will returns the first
 // message on the reply channel.
 //
+// The operation options are applied both to the request publication and to the
+// reply reception: the operation middlewares will be executed on both messages.
+//
 // A timeout can be set in context to avoid blocking operation, if needed.
 
 func (c *UserController) RequestToGetServiceInfoOperation(
 	ctx context.Context,
 	msg RequestMessageFromReceptionChannel,
+	options ...OperationOption,
 ) (ReplyMessageFromReplyChannel, error) {
 	// Get receiving channel address
 	if msg.Headers.ReplyTo == nil {
@@ -488,15 +535,18 @@ func (c *UserController) RequestToGetServiceInfoOperation(
 	}()
 
 	// Send the message
-	if err := c.SendToGetServiceInfoOperation(ctx, msg); err != nil {
+	if err := c.SendToGetServiceInfoOperation(ctx, msg, options...); err != nil {
 		c.logger.Error(ctx, "error happened when sending message", extensions.LogInfo{Key: "error", Value: err.Error()})
 		return ReplyMessageFromReplyChannel{}, fmt.Errorf("error happened when sending message: %w", err)
 	}
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Wait for corresponding response
 	for {
 		// Listen to next message
-		msg, err := c.waitForGetServiceInfoOperationNextResponse(ctx, addr, sub)
+		msg, err := c.waitForGetServiceInfoOperationNextResponse(ctx, addr, sub, opts)
 		if err != nil {
 			c.logger.Error(ctx, err.Error())
 		}
@@ -514,6 +564,7 @@ func (c *UserController) waitForGetServiceInfoOperationNextResponse(
 	ctx context.Context,
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 ) (*ReplyMessageFromReplyChannel, error) {
 	// Create a context for the received response
 	msgCtx, cancel := context.WithCancel(context.Background())
@@ -536,9 +587,10 @@ func (c *UserController) waitForGetServiceInfoOperationNextResponse(
 
 		// Set context with received values as it is the expected message
 		msgCtx := context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
 
 		// Execute middlewares before returning
-		if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, nil); err != nil {
+		if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, nil); err != nil {
 			return nil, err
 		}
 
@@ -573,6 +625,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -595,6 +653,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -602,6 +674,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -640,9 +739,15 @@ func NewRequestMessageFromReceptionChannel() RequestMessageFromReceptionChannel
 func brokerMessageToRequestMessageFromReceptionChannel(bMsg extensions.BrokerMessage) (RequestMessageFromReceptionChannel, error) {
 	var msg RequestMessageFromReceptionChannel
 
-	// Convert to string
-	payload := string(bMsg.Payload)
-	msg.Payload = payload // No need for type conversion to reference
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
+		return msg, err
+	}
 
 	// Get each headers from broker message
 	for k, v := range bMsg.Headers {
@@ -660,12 +765,33 @@ func brokerMessageToRequestMessageFromReceptionChannel(bMsg extensions.BrokerMes
 	return msg, nil
 }
 
+const (
+	// RequestMessageFromReceptionChannelReplyToHeader is the key of the 'replyTo' header of RequestMessageFromReceptionChannel.
+	RequestMessageFromReceptionChannelReplyToHeader = "replyTo"
+)
+
+// GetRequestMessageFromReceptionChannelReplyToHeader returns the 'replyTo' header from a broker
+// message carrying RequestMessageFromReceptionChannel (e.g. in middlewares), decoded as in the
+// message headers. It returns false if the header is not set.
+func GetRequestMessageFromReceptionChannelReplyToHeader(bMsg extensions.BrokerMessage) (h string, ok bool, err error) {
+	v, ok := bMsg.Headers[RequestMessageFromReceptionChannelReplyToHeader]
+	if !ok {
+		return h, false, nil
+	}
+	h = string(v)
+	return h, true, err
+}
+
 // toBrokerMessage will generate a generic broker message from RequestMessageFromReceptionChannel data
 func (msg RequestMessageFromReceptionChannel) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Convert to []byte
-	payload := []byte(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
+	if err != nil {
+		return extensions.BrokerMessage{}, err
+	}
 
 	// Add each headers to broker message
 	headers := make(map[string][]byte, 1)
@@ -697,9 +823,15 @@ func NewReplyMessageFromReplyChannel() ReplyMessageFromReplyChannel {
 func brokerMessageToReplyMessageFromReplyChannel(bMsg extensions.BrokerMessage) (ReplyMessageFromReplyChannel, error) {
 	var msg ReplyMessageFromReplyChannel
 
-	// Convert to string
-	payload := string(bMsg.Payload)
-	msg.Payload = payload // No need for type conversion to reference
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
+		return msg, err
+	}
 
 	// TODO: run checks on msg type
 
@@ -710,8 +842,12 @@ func brokerMessageToReplyMessageFromReplyChannel(bMsg extensions.BrokerMessage)
 func (msg ReplyMessageFromReplyChannel) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Convert to []byte
-	payload := []byte(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
+	if err != nil {
+		return extensions.BrokerMessage{}, err
+	}
 
 	// There is no headers here
 	headers := make(map[string][]byte, 0)
@@ -18,7 +18,7 @@ import (
 )
 
 func TestWildcardSubscription(t *testing.T) {
-	name := "issue186"
+	name := "v3issue186"
 
 	brokerAddrParams := testutil.BrokerAddressParams{
 		Schema:         "nats",
@@ -88,7 +88,7 @@ func (suite *Suite) TestIssue186Star() {
 
 	// Publish a message
 	wg.Add(1)
-	err = suite.nats.Publish("v2.issue186.star.dynamic.msg", []byte(payload))
+	err = suite.nats.Publish("v3.issue186.star.dynamic.msg", []byte(`"`+payload+`"`))
 	suite.Require().NoError(err)
 
 	wg.Wait()
@@ -111,7 +111,7 @@ func (suite *Suite) TestIssue186Angle() {
 
 	// Publish a message
 	wg.Add(1)
-	err = suite.nats.Publish("v2.issue186.angle.dynamic.msg", []byte(payload))
+	err = suite.nats.Publish("v3.issue186.angle.dynamic.msg", []byte(`"`+payload+`"`))
 	suite.Require().NoError(err)
 
 	wg.Wait()
@@ -4,8 +4,10 @@
 package issue224
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/lerenn/asyncapi-codegen/pkg/extensions"
@@ -96,9 +98,20 @@ func (c AppController) wrapMiddlewares(
 	}
 }
 
-func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c AppController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -141,14 +154,10 @@ func (c *AppController) UnsubscribeFromAllChannels(ctx context.Context) {
 // SubscribeToReceiveTestOperation will receive TestMessageFromTestChannel messages from Test channel.
 //
 // Callback function 'fn' will be called each time a new message is received.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *AppController) SubscribeToReceiveTestOperation(
 	ctx context.Context,
 	fn func(ctx context.Context, msg TestMessageFromTestChannel) error,
+	options ...OperationOption,
 ) error {
 	// Get channel address
 	addr := "v3.issue224.test"
@@ -173,11 +182,14 @@ func (c *AppController) SubscribeToReceiveTestOperation(
 	}
 	c.logger.Info(ctx, "Subscribed to channel")
 
+	// Get operation options
+	opts := newOperationOptions(options...)
+
 	// Asynchronously listen to new messages and pass them to app receiver
 	go func() {
 		for {
 			// Listen to next message
-			stop, err := c.listenToReceiveTestOperationNextMessage(addr, sub, fn)
+			stop, err := c.listenToReceiveTestOperationNextMessage(addr, sub, opts, fn)
 			if err != nil {
 				c.logger.Error(ctx, err.Error())
 			}
@@ -198,6 +210,7 @@ func (c *AppController) SubscribeToReceiveTestOperation(
 func (c *AppController) listenToReceiveTestOperationNextMessage(
 	addr string,
 	sub extensions.BrokerChannelSubscription,
+	opts operationOptions,
 	fn func(ctx context.Context, msg TestMessageFromTestChannel) error,
 ) (stop bool, err error) {
 	// Create a context for the received response
@@ -215,11 +228,21 @@ func (c *AppController) listenToReceiveTestOperationNextMessage(
 		return true, nil
 	}
 
-	// Set broker message to context
+	// Set broker message and its metadata to context
 	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessage, acknowledgeableBrokerMessage.String())
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsBrokerMessageMetadata, acknowledgeableBrokerMessage.Metadata)
+
+	// Set the address on which the message has been received to context, if
+	// the broker gives it, as it can differ from the subscribed one
+	msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsSubscribedChannel, addr)
+	receivedAddr := addr
+	if acknowledgeableBrokerMessage.Metadata.Channel != "" {
+		receivedAddr = acknowledgeableBrokerMessage.Metadata.Channel
+		msgCtx = context.WithValue(msgCtx, extensions.ContextKeyIsChannel, receivedAddr)
+	}
 
 	// Execute middlewares before handling the message
-	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, func(middlewareCtx context.Context) error {
+	if err := c.executeMiddlewares(msgCtx, &acknowledgeableBrokerMessage.BrokerMessage, opts, func(middlewareCtx context.Context) error {
 		// Process message
 		msg, err := brokerMessageToTestMessageFromTestChannel(acknowledgeableBrokerMessage.BrokerMessage)
 		if err != nil {
@@ -235,6 +258,13 @@ func (c *AppController) listenToReceiveTestOperationNextMessage(
 
 		return nil
 	}); err != nil {
+		// If a middleware asked to skip the message, then acknowledge it as it
+		// should not be processed again and do not consider it as an error
+		if errors.Is(err, extensions.ErrSkipMessage) {
+			acknowledgeableBrokerMessage.Ack()
+			return false, nil
+		}
+
 		c.errorHandler(msgCtx, addr, &acknowledgeableBrokerMessage, err)
 		// On error execute the acknowledgeableBrokerMessage nack() function and
 		// let the BrokerAcknowledgment decide what is the right nack behavior for the broker
@@ -349,9 +379,20 @@ func (c UserController) wrapMiddlewares(
 	}
 }
 
-func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, callback extensions.NextMiddleware) error {
+func (c UserController) executeMiddlewares(ctx context.Context, msg *extensions.BrokerMessage, opts operationOptions, callback extensions.NextMiddleware) error {
+	// Get the common middlewares, then the ones specific to the direction and
+	// finally the ones specific to the operation
+	middlewares := make([]extensions.Middleware, 0, len(c.middlewares)+len(opts.middlewares))
+	middlewares = append(middlewares, c.middlewares...)
+	if ctx.Value(extensions.ContextKeyIsDirection) == "publication" {
+		middlewares = append(middlewares, c.publicationMiddlewares...)
+	} else {
+		middlewares = append(middlewares, c.receptionMiddlewares...)
+	}
+	middlewares = append(middlewares, opts.middlewares...)
+
 	// Wrap middleware to have 'next' function when calling them
-	wrapped := c.wrapMiddlewares(c.middlewares, callback)
+	wrapped := c.wrapMiddlewares(middlewares, callback)
 
 	// Execute wrapped middlewares
 	return wrapped(ctx, msg)
@@ -369,12 +410,10 @@ func (c *UserController) Close(ctx context.Context) {
 }
 
 // SendToReceiveTestOperation will send a TestMessageFromTestChannel message on Test channel.
-//
-// NOTE: for now, this only support the first message from AsyncAPI list.
-// If you need support for other messages, please raise an issue.
 func (c *UserController) SendToReceiveTestOperation(
 	ctx context.Context,
 	msg TestMessageFromTestChannel,
+	options ...OperationOption,
 ) error {
 	// Set channel address
 	addr := "v3.issue224.test"
@@ -393,8 +432,11 @@ func (c *UserController) SendToReceiveTestOperation(
 	ctx = context.WithValue(ctx, extensions.ContextKeyIsBrokerMessage, brokerMsg.String())
 
 	// Send the message on event-broker through middlewares
-	return c.executeMiddlewares(ctx, &brokerMsg, func(ctx context.Context) error {
-		return c.broker.Publish(ctx, addr, brokerMsg)
+	return c.executeMiddlewares(ctx, &brokerMsg, newOperationOptions(options...), func(ctx context.Context) error {
+		if err := c.broker.Publish(ctx, addr, brokerMsg); err != nil {
+			return &extensions.BrokerError{Err: err}
+		}
+		return nil
 	})
 }
 
@@ -413,6 +455,12 @@ type controller struct {
 	// middlewares are the middlewares that will be executed when sending or
 	// receiving messages
 	middlewares []extensions.Middleware
+	// receptionMiddlewares are the middlewares that will be executed only when
+	// receiving messages, after the common middlewares
+	receptionMiddlewares []extensions.Middleware
+	// publicationMiddlewares are the middlewares that will be executed only when
+	// sending messages, after the common middlewares
+	publicationMiddlewares []extensions.Middleware
 	// handler to handle errors from consumers and middlewares
 	errorHandler extensions.ErrorHandler
 }
@@ -435,6 +483,20 @@ func WithMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
 	}
 }
 
+// WithReceptionMiddlewares attaches middlewares that will be executed only when receiving messages
+func WithReceptionMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.receptionMiddlewares = middlewares
+	}
+}
+
+// WithPublicationMiddlewares attaches middlewares that will be executed only when sending messages
+func WithPublicationMiddlewares(middlewares ...extensions.Middleware) ControllerOption {
+	return func(controller *controller) {
+		controller.publicationMiddlewares = middlewares
+	}
+}
+
 // WithErrorHandler attaches a errorhandler to handle errors from subscriber functions
 func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	return func(controller *controller) {
@@ -442,6 +504,33 @@ func WithErrorHandler(handler extensions.ErrorHandler) ControllerOption {
 	}
 }
 
+// operationOptions are the options that apply to only one operation
+type operationOptions struct {
+	// middlewares are the middlewares that will be executed only for this
+	// operation, after the controller middlewares
+	middlewares []extensions.Middleware
+}
+
+// OperationOption is the type of the options that can be passed
+// when subscribing or sending messages
+type OperationOption func(options *operationOptions)
+
+// WithOperationMiddlewares attaches middlewares that will be executed only for the
+// operation (subscription or sending) they are passed to
+func WithOperationMiddlewares(middlewares ...extensions.Middleware) OperationOption {
+	return func(options *operationOptions) {
+		options.middlewares = append(options.middlewares, middlewares...)
+	}
+}
+
+func newOperationOptions(options ...OperationOption) operationOptions {
+	var opts operationOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
 type MessageWithCorrelationID interface {
 	CorrelationID() string
 	SetCorrelationID(id string)
@@ -465,6 +554,9 @@ type TestMessageFromTestChannel struct {
 func NewTestMessageFromTestChannel() TestMessageFromTestChannel {
 	var msg TestMessageFromTestChannel
 
+	// Set default values
+	msg.Payload.SetDefaults()
+
 	return msg
 }
 
@@ -472,12 +564,19 @@ func NewTestMessageFromTestChannel() TestMessageFromTestChannel {
 func brokerMessageToTestMessageFromTestChannel(bMsg extensions.BrokerMessage) (TestMessageFromTestChannel, error) {
 	var msg TestMessageFromTestChannel
 
-	// Unmarshal payload to expected message payload format
-	err := json.Unmarshal(bMsg.Payload, &msg.Payload)
-	if err != nil {
+	// Unmarshal payload with the codec of the received content type, or of
+	// the message content type if there is none (JSON by default)
+	contentType := string(bMsg.Headers[extensions.ContentTypeHeader])
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if err := extensions.UnmarshalWithContentType(contentType, bMsg.Payload, &msg.Payload); err != nil {
 		return msg, err
 	}
 
+	// Set default values on the fields that are not set
+	msg.Payload.SetDefaults()
+
 	// TODO: run checks on msg type
 
 	return msg, nil
@@ -487,8 +586,9 @@ func brokerMessageToTestMessageFromTestChannel(bMsg extensions.BrokerMessage) (T
 func (msg TestMessageFromTestChannel) toBrokerMessage() (extensions.BrokerMessage, error) {
 	// TODO: implement checks on message
 
-	// Marshal payload to JSON
-	payload, err := json.Marshal(msg.Payload)
+	// Marshal payload with the codec of the message content type, or JSON if
+	// there is none
+	payload, err := extensions.MarshalWithContentType("application/json", msg.Payload)
 	if err != nil {
 		return extensions.BrokerMessage{}, err
 	}
@@ -509,10 +609,101 @@ type ColliderSchema struct {
 	Shape  ShapePropertyFromColliderSchema `json:"shape"`
 }
 
+// NewColliderSchema creates a new ColliderSchema with the default values from the
+// specification.
+func NewColliderSchema() ColliderSchema {
+	var s ColliderSchema
+	s.SetDefaults()
+	return s
+}
+
+// SetDefaults sets the default values from the specification on the fields
+// that are not set, including in nested objects.
+func (s *ColliderSchema) SetDefaults() {
+	if s.Margin == nil {
+		v := float32(0)
+		s.Margin = &v
+	}
+	if s.Pose == nil {
+		// The default value comes from the specification, so it is valid
+		_ = json.Unmarshal([]byte(`{"orientation":[0,0,0],"position":[0,0,0]}`), &s.Pose)
+	}
+}
+
 // ShapePropertyFromColliderSchema is a schema from the AsyncAPI specification required in messages
+// It can be one of the following variants, each one being set in its own field.
 type ShapePropertyFromColliderSchema struct {
-	Radius    float64 `json:"radius"`
-	ShapeType string  `json:"shape_type" validate:"oneof='sphere'"`
+	// Sphere is set when the value is a 'SphereSchema'.
+	Sphere *SphereSchema
+}
+
+// NewShapePropertyFromColliderSchemaWithSphere creates a new ShapePropertyFromColliderSchema set with the Sphere variant.
+func NewShapePropertyFromColliderSchemaWithSphere(v SphereSchema) ShapePropertyFromColliderSchema {
+	return ShapePropertyFromColliderSchema{Sphere: &v}
+}
+
+// AsSphere returns the Sphere variant and true if it is set.
+func (u ShapePropertyFromColliderSchema) AsSphere() (SphereSchema, bool) {
+	if u.Sphere == nil {
+		var zero SphereSchema
+		return zero, false
+	}
+	return *u.Sphere, true
+}
+
+// Value returns the value of the first variant that is set, or nil if there is none.
+func (u ShapePropertyFromColliderSchema) Value() any {
+	if u.Sphere != nil {
+		return *u.Sphere
+	}
+	return nil
+}
+
+// MarshalJSON marshals the variant that is set into JSON.
+func (u ShapePropertyFromColliderSchema) MarshalJSON() ([]byte, error) {
+	// Check that there is only one variant set
+	set := make([]string, 0, 1)
+	if u.Sphere != nil {
+		set = append(set, "Sphere")
+	}
+	if len(set) > 1 {
+		return nil, fmt.Errorf("%w: %q are set on 'ShapePropertyFromColliderSchema'", extensions.ErrMultipleVariantsSet, set)
+	}
+
+	// Marshal the variant that is set
+	if u.Sphere != nil {
+		return json.Marshal(u.Sphere)
+	}
+
+	return []byte("null"), nil
+}
+
+// UnmarshalJSON unmarshals the JSON into the corresponding variant.
+// The variant is identified by the 'shape_type' property.
+func (u *ShapePropertyFromColliderSchema) UnmarshalJSON(data []byte) error {
+	*u = ShapePropertyFromColliderSchema{}
+
+	// Nothing to set if there is no value
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	// Get the variant from the discriminator property
+	var discriminator struct {
+		Value string `json:"shape_type"`
+	}
+	if err := json.Unmarshal(data, &discriminator); err != nil {
+		return err
+	}
+
+	switch discriminator.Value {
+	case "sphere":
+		u.Sphere = new(SphereSchema)
+		return json.Unmarshal(data, u.Sphere)
+	default:
+		return fmt.Errorf("%w: unknown 'shape_type' value %q for 'ShapePropertyFromColliderSchema'",
+			extensions.ErrUnknownVariant, discriminator.Value)
+	}
 }
 
 // ColliderDictionarySchema is a schema from the AsyncAPI specification required in messages
@@ -537,8 +728,9 @@ func (t ColliderDictionarySchema) MarshalJSON() ([]byte, error) {
 	// When there are no properties, we cant start with a separator
 	needSeparator := len(b) > 1
 
-	// Add additional properties
-	for k, v := range t.AdditionalProperties {
+	// Add additional properties, sorted to be deterministic
+	for _, k := range extensions.SortedKeys(t.AdditionalProperties) {
+		v := t.AdditionalProperties[k]
 		if needSeparator {
 			b = append(b, ',')
 		}
@@ -560,7 +752,7 @@ func (t *ColliderDictionarySchema) UnmarshalJSON(data []byte) error {
 	type alias ColliderDictionarySchema
 
 	// Unmarshal to map to get all fields
-	var m map[string]ColliderSchema
+	var m map[string]json.RawMessage
 	if err := json.Unmarshal(data, &m); err != nil {
 		return err
 	}
@@ -573,18 +765,39 @@ func (t *ColliderDictionarySchema) UnmarshalJSON(data []byte) error {
 	}
 	*t = ColliderDictionarySchema(a)
 
-	// Get all fields that are additional and add them to the AdditionalProperties field.
+	// Get all fields that are not properties and add them to the corresponding map.
 	t.AdditionalProperties = make(map[string]ColliderSchema, len(m))
 	for k, v := range m {
-		switch k {
+		switch {
 		default:
-			t.AdditionalProperties[k] = v
+			var p ColliderSchema
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+			t.AdditionalProperties[k] = p
 		}
 	}
 
 	return nil
 }
 
+// NewColliderDictionarySchema creates a new ColliderDictionarySchema with the default values from the
+// specification.
+func NewColliderDictionarySchema() ColliderDictionarySchema {
+	var s ColliderDictionarySchema
+	s.SetDefaults()
+	return s
+}
+
+// SetDefaults sets the default values from the specification on the fields
+// that are not set, including in nested objects.
+func (s *ColliderDictionarySchema) SetDefaults() {
+	for k, v := range s.AdditionalProperties {
+		v.SetDefaults()
+		s.AdditionalProperties[k] = v
+	}
+}
+
 // PoseSchema is a schema from the AsyncAPI specification required in messages
 type PoseSchema struct {
 	Orientation *Vector3dSchema `json:"orientation,omitempty"`
@@ -593,8 +806,49 @@ type PoseSchema struct {
 
 // SphereSchema is a schema from the AsyncAPI specification required in messages
 type SphereSchema struct {
-	Radius    float64 `json:"radius"`
-	ShapeType string  `json:"shape_type" validate:"oneof='sphere'"`
+	Radius    float64                           `json:"radius"`
+	ShapeType ShapeTypePropertyFromSphereSchema `json:"shape_type" validate:"oneof='sphere'"`
+}
+
+// ShapeTypePropertyFromSphereSchema is a schema from the AsyncAPI specification required in messages
+type ShapeTypePropertyFromSphereSchema string
+
+const (
+	// ShapeTypePropertyFromSphereSchemaSphere is the "sphere" value of ShapeTypePropertyFromSphereSchema.
+	ShapeTypePropertyFromSphereSchemaSphere ShapeTypePropertyFromSphereSchema = "sphere"
+)
+
+// Values returns all the possible values of ShapeTypePropertyFromSphereSchema.
+func (ShapeTypePropertyFromSphereSchema) Values() []ShapeTypePropertyFromSphereSchema {
+	return []ShapeTypePropertyFromSphereSchema{
+		ShapeTypePropertyFromSphereSchemaSphere,
+	}
+}
+
+// IsValid checks if the value is one of the possible values of ShapeTypePropertyFromSphereSchema.
+func (e ShapeTypePropertyFromSphereSchema) IsValid() bool {
+	switch e {
+	case ShapeTypePropertyFromSphereSchemaSphere:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnmarshalJSON unmarshals the JSON value and checks that it is one of the
+// possible values of ShapeTypePropertyFromSphereSchema.
+func (e *ShapeTypePropertyFromSphereSchema) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if !ShapeTypePropertyFromSphereSchema(v).IsValid() {
+		return fmt.Errorf("%w: %v is not a valid 'ShapeTypePropertyFromSphereSchema' value", extensions.ErrInvalidEnumValue, v)
+	}
+
+	*e = ShapeTypePropertyFromSphereSchema(v)
+	return nil
 }
 
 // Vector3dSchema is a schema from the AsyncAPI specification required in messages
@@ -32,10 +32,10 @@ func (suite *Suite) TestMarshalAndUnmarshalRoundtrip() {
 					Position:    &Vector3dSchema{1.1, 2.2, 3.3},
 					Orientation: &Vector3dSchema{4.4, 5.5, 6.6, 7.7},
 				},
-				Shape: ShapePropertyFromColliderSchema{
+				Shape: NewShapePropertyFromColliderSchemaWithSphere(SphereSchema{
 					Radius:    10.0,
 					ShapeType: "sphere",
-				},
+				}),
 			},
 		},
 	}
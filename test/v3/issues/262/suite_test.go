@@ -47,6 +47,7 @@ func (suite *Suite) TestBrokerMessageToTestMessage() {
 	_, err := brokerMessageToTestMessage(
 		extensions.BrokerMessage{
 			Headers: map[string][]byte{},
+			Payload: []byte(`""`),
 		},
 	)
 	// There is currently no check on fields returned by brokerMessageToTestMessage